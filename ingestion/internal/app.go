@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"time"
 
@@ -13,6 +13,12 @@ import (
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/rest"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/services"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/health"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/kafkabus"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
 	redis "github.com/redis/go-redis/v9"
 	"golang.org/x/sync/errgroup"
 )
@@ -20,27 +26,81 @@ import (
 // App centralizes dependency wiring for the ingestion service.
 type App struct {
 	cfg    config.Config
-	logger *log.Logger
+	logger *slog.Logger
 
 	redis     *redis.Client
 	store     *store.InfluencerStore
 	publisher *kafka.SignalPublisher
+	outbox    *store.SignalOutbox
+	flusher   *services.OutboxFlusher
+	orders    *kafka.OrderIntentPublisher
+	positions *kafka.PositionPublisher
+	dlq       *kafka.DeadLetterPublisher
+	posStore  *store.PositionStore
+	raw       services.RawEventSink
+	markets   *markets.Registry
 	signal    *services.SignalService
+	settings  *libconfig.Reloader[config.Runtime]
+	modes     *tradingmode.Switch
 
 	httpServer *http.Server
 }
 
-// NewApp builds an App with all required dependencies.
-func NewApp(cfg config.Config, logger *log.Logger) *App {
+// NewApp builds an App with all required dependencies. level is the logger's
+// level, adjusted when the log_level runtime setting changes.
+func NewApp(cfg config.Config, logger *slog.Logger, level *slog.LevelVar) (*App, error) {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.RedisAddr,
 		Password: cfg.RedisPassword,
 		DB:       cfg.RedisDB,
 	})
+	settings, err := newSettings(cfg, redisClient, logger, level)
+	if err != nil {
+		_ = redisClient.Close()
+		return nil, err
+	}
 	infStore := store.NewInfluencerStore(redisClient, cfg.InfluencerSetKey)
-	publisher := kafka.NewSignalPublisher(cfg)
-	client := services.NewHyperliquidService(cfg, logger)
-	signal := services.NewSignalService(infStore, client, publisher)
+	publisher := kafka.NewSignalPublisher(cfg, kafkaPublisher(cfg, cfg.KafkaTopic))
+	orders := kafka.NewOrderIntentPublisher(cfg, kafkaPublisher(cfg, cfg.KafkaTopicOrders))
+	positionPublisher := kafka.NewPositionPublisher(cfg, kafkabus.NewPublisher(kafkabus.PublisherConfig{
+		Brokers: cfg.KafkaBrokers,
+		Topic:   cfg.KafkaTopicPositions,
+	}))
+	dlq := kafka.NewDeadLetterPublisher(cfg, kafkaPublisher(cfg, cfg.KafkaTopicDeadLetters))
+	raw, err := newRawEventSink(cfg)
+	if err != nil {
+		_ = publisher.Close()
+		_ = orders.Close()
+		_ = positionPublisher.Close()
+		_ = dlq.Close()
+		_ = redisClient.Close()
+		return nil, fmt.Errorf("raw event sink: %w", err)
+	}
+	outbox, err := store.NewSignalOutbox(cfg.SignalOutboxDir, cfg.SignalOutboxSegmentBytes)
+	if err != nil {
+		_ = publisher.Close()
+		_ = orders.Close()
+		_ = positionPublisher.Close()
+		_ = dlq.Close()
+		if raw != nil {
+			_ = raw.Close()
+		}
+		_ = redisClient.Close()
+		return nil, fmt.Errorf("signal outbox: %w", err)
+	}
+	cursors := store.NewCursorStore(redisClient, cfg.CursorKeyPrefix)
+	sequences := store.NewSequenceStore(redisClient, cfg.SequenceKeyPrefix)
+	registry := markets.NewRegistry(markets.NewHyperliquidLoader(cfg.HyperAPIURL))
+	positions := store.NewPositionStore(redisClient, cfg.PositionKeyPrefix)
+	book := services.NewPositionBook(positions, positionPublisher.Publish, settings)
+	modes := tradingmode.NewSwitch(redisClient, cfg.TradingModeKey, cfg.SettingsPollInterval, logger)
+	client := services.NewHyperliquidService(cfg, cursors, sequences, book, raw, dlq, registry, modes, logger)
+	var shards *services.ShardCoordinator
+	if cfg.InfluencerAssignment == config.AssignmentHash {
+		membership := store.NewMembershipStore(redisClient, cfg.InstanceRegistryKey, cfg.InstanceID, cfg.InstanceTTL)
+		shards = services.NewShardCoordinator(cfg, infStore, membership, logger)
+	}
+	signal := services.NewSignalService(infStore, client, publisher, outbox, orders, shards, settings, modes, logger)
 
 	return &App{
 		cfg:       cfg,
@@ -48,18 +108,145 @@ func NewApp(cfg config.Config, logger *log.Logger) *App {
 		redis:     redisClient,
 		store:     infStore,
 		publisher: publisher,
+		outbox:    outbox,
+		flusher:   services.NewOutboxFlusher(outbox, publisher, settings, logger),
+		orders:    orders,
+		positions: positionPublisher,
+		dlq:       dlq,
+		posStore:  positions,
+		raw:       raw,
+		markets:   registry,
 		signal:    signal,
+		settings:  settings,
+		modes:     modes,
+	}, nil
+}
+
+// newSettings builds the runtime settings reloader, reading SETTINGS_FILE when
+// set and the SETTINGS_KEY Redis hash otherwise.
+func newSettings(cfg config.Config, redisClient *redis.Client, logger *slog.Logger, level *slog.LevelVar) (*libconfig.Reloader[config.Runtime], error) {
+	var source libconfig.Source = libconfig.NewRedisSource(redisClient, cfg.SettingsKey)
+	if cfg.SettingsFile != "" {
+		source = libconfig.FileSource{Path: cfg.SettingsFile}
+	}
+	settings, err := libconfig.NewReloader[config.Runtime](source, cfg.SettingsPollInterval, logger)
+	if err != nil {
+		return nil, err
+	}
+	settings.OnChange(func(_, next config.Runtime) {
+		name := next.LogLevel
+		if name == "" {
+			name = cfg.LogLevel
+		}
+		if l, err := observability.ParseLevel(name); err == nil {
+			level.Set(l)
+		}
+	})
+	return settings, nil
+}
+
+// newRawEventSink selects the raw event backend configured by RAW_EVENT_SINK.
+func newRawEventSink(cfg config.Config) (services.RawEventSink, error) {
+	switch cfg.RawEventSink {
+	case config.RawEventSinkFile:
+		return store.NewRawEventFileStore(cfg.RawEventDir, cfg.RawEventSegmentBytes)
+	case config.RawEventSinkNone:
+		return nil, nil
+	default:
+		return kafka.NewRawEventPublisher(cfg, kafkaPublisher(cfg, cfg.KafkaTopicRawEvents)), nil
+	}
+}
+
+// kafkaPublisher writes to topic on KAFKA_BROKERS, creating it on first use.
+func kafkaPublisher(cfg config.Config, topic string) *kafkabus.Publisher {
+	return kafkabus.NewPublisher(kafkabus.PublisherConfig{
+		Brokers:         cfg.KafkaBrokers,
+		Topic:           topic,
+		AutoCreateTopic: true,
+	})
+}
+
+// ensurePositionsTopic creates the compacted positions topic, retrying with
+// exponential backoff until it succeeds or ctx is done. The positions
+// publisher does not auto-create topics (that would use the delete policy),
+// so snapshots fail to publish until this succeeds; each market's next
+// snapshot replaces the ones lost.
+func (a *App) ensurePositionsTopic(ctx context.Context) {
+	backoff := time.Second
+	for {
+		err := kafkabus.EnsureCompactedTopic(ctx, a.cfg.KafkaBrokers, a.cfg.KafkaTopicPositions)
+		if err == nil {
+			return
+		}
+		a.logger.ErrorContext(ctx, "ensure compacted positions topic", slog.String("topic", a.cfg.KafkaTopicPositions), slog.Duration("retry_in", backoff), observability.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, time.Minute)
 	}
 }
 
 // Run starts background services and blocks until ctx cancellation or fatal error.
 func (a *App) Run(ctx context.Context) error {
+	shutdownTracing, err := observability.SetupTracing(ctx, observability.TracingConfig{
+		ServiceName:     "ingestion",
+		ServiceInstance: a.cfg.InstanceID,
+		Exporter:        a.cfg.TracingExporter,
+		SampleRatio:     a.cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			a.logger.Error("flush traces", observability.Err(err))
+		}
+	}()
+
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 	defer a.cleanup()
 
+	// Start on the stored settings; defaults apply until they can be read.
+	if err := a.settings.Reload(ctx); err != nil {
+		a.logger.ErrorContext(ctx, "load runtime settings", observability.Err(err))
+	}
+
+	if err := a.modes.Reload(ctx); err != nil {
+		a.logger.ErrorContext(ctx, "load trading modes", observability.Err(err))
+	}
+
 	g, gctx := errgroup.WithContext(ctx)
 
+	g.Go(func() error {
+		return a.settings.Run(gctx)
+	})
+
+	g.Go(func() error {
+		a.ensurePositionsTopic(gctx)
+		return nil
+	})
+
+	g.Go(func() error {
+		return a.modes.Run(gctx)
+	})
+
+	g.Go(func() error {
+		a.markets.Run(gctx, a.cfg.MarketMetaRefresh, func(err error) {
+			a.logger.Error("refresh market metadata", observability.Err(err))
+		})
+		return nil
+	})
+
+	// Signals still in the outbox at shutdown are flushed on the next start.
+	g.Go(func() error {
+		return a.flusher.Run(gctx)
+	})
+
 	g.Go(func() error {
 		if err := a.signal.Start(gctx); err != nil {
 			return fmt.Errorf("start stream service: %w", err)
@@ -78,14 +265,21 @@ func (a *App) Run(ctx context.Context) error {
 }
 
 func (a *App) runHTTPServer(ctx context.Context) error {
-	r, srv := rest.NewServer(a.cfg)
+	r, srv := rest.NewServer(a.cfg, a.readiness())
 	a.httpServer = srv
 	infController := rest.NewInfluencerController(a.store)
 	infController.RegisterInfluencerRoutes(r.Group(""))
+	posController := rest.NewPositionController(a.posStore)
+	posController.RegisterPositionRoutes(r.Group(""))
+	cfgController, err := rest.NewConfigController(a.cfg, a.settings)
+	if err != nil {
+		return fmt.Errorf("config controller: %w", err)
+	}
+	cfgController.RegisterConfigRoutes(r.Group(""))
 
 	serverErr := make(chan error, 1)
 	go func() {
-		fmt.Printf("HTTP server started at: %s\n", srv.Addr)
+		a.logger.Info("HTTP server started", slog.String("addr", srv.Addr))
 		serverErr <- srv.ListenAndServe()
 	}()
 
@@ -111,15 +305,50 @@ func (a *App) runHTTPServer(ctx context.Context) error {
 	}
 }
 
+// readiness checks the Redis and Kafka dependencies of ingestion.
+func (a *App) readiness() *health.Checker {
+	checker := health.NewChecker(0)
+	checker.Add("redis", func(ctx context.Context) error {
+		return a.redis.Ping(ctx).Err()
+	})
+	checker.Add("kafka", health.CheckKafkaBrokers(a.cfg.KafkaBrokers))
+	return checker
+}
+
 func (a *App) cleanup() {
 	if a.publisher != nil {
 		if err := a.publisher.Close(); err != nil {
-			a.logger.Printf("error closing Kafka publisher: %v", err)
+			a.logger.Error("close Kafka publisher", observability.Err(err))
+		}
+	}
+	if a.outbox != nil {
+		if err := a.outbox.Close(); err != nil {
+			a.logger.Error("close signal outbox", observability.Err(err))
+		}
+	}
+	if a.orders != nil {
+		if err := a.orders.Close(); err != nil {
+			a.logger.Error("close order intent publisher", observability.Err(err))
+		}
+	}
+	if a.positions != nil {
+		if err := a.positions.Close(); err != nil {
+			a.logger.Error("close position publisher", observability.Err(err))
+		}
+	}
+	if a.dlq != nil {
+		if err := a.dlq.Close(); err != nil {
+			a.logger.Error("close dead-letter publisher", observability.Err(err))
+		}
+	}
+	if a.raw != nil {
+		if err := a.raw.Close(); err != nil {
+			a.logger.Error("close raw event sink", observability.Err(err))
 		}
 	}
 	if a.redis != nil {
 		if err := a.redis.Close(); err != nil {
-			a.logger.Printf("error closing Redis client: %v", err)
+			a.logger.Error("close Redis client", observability.Err(err))
 		}
 	}
 }
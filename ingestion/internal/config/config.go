@@ -3,78 +3,132 @@ package config
 import (
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
-)
-
-// Config holds runtime configuration for the ingestion service.
-type Config struct {
-	RedisAddr     string
-	RedisPassword string
-	RedisDB       int
-
-	KafkaBrokers []string
-	KafkaTopic   string
+	"time"
 
-	HyperWSURL string
-
-	InfluencerSetKey string
-
-	HTTPAddr string
-}
-
-// envOrDefault returns the value of an environment variable or a default.
-func envOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return def
-}
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+)
 
-func envIntOrDefault(key string, def int) (int, error) {
-	if raw := os.Getenv(key); raw != "" {
-		val, err := strconv.Atoi(raw)
-		if err != nil {
-			return 0, fmt.Errorf("invalid %s: %w", key, err)
-		}
-		return val, nil
-	}
+// Raw event sink backends.
+const (
+	RawEventSinkKafka = "kafka"
+	RawEventSinkFile  = "file"
+	RawEventSinkNone  = "none"
+)
 
-	return def, nil
-}
+// Influencer assignment strategies.
+const (
+	// AssignmentQueue pops influencers from the shared Redis set as a work queue.
+	AssignmentQueue = "queue"
+	// AssignmentHash assigns influencers to live instances by consistent hashing.
+	AssignmentHash = "hash"
+)
 
-func envCSVOrDefault(key, def string) []string {
-	raw := envOrDefault(key, def)
-	parts := strings.Split(raw, ",")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
-	}
-	return parts
+// Config holds runtime configuration for the ingestion service. Fields are
+// loaded by libs/go/config from their env tags, a YAML file and flags.
+type Config struct {
+	RedisAddr     string `env:"REDIS_ADDR" default:"localhost:6379" validate:"required"`
+	RedisPassword string `env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB       int    `env:"REDIS_DB" default:"0" validate:"min=0"`
+
+	KafkaBrokers        []string `env:"KAFKA_BROKERS" default:"localhost:9092" validate:"required"`
+	KafkaTopic          string   `env:"KAFKA_TOPIC_INFLUENCER_SIGNALS" default:"influencer_signals" validate:"required"`
+	KafkaTopicRawEvents string   `env:"KAFKA_TOPIC_RAW_EVENTS" default:"raw_hyperliquid_events" validate:"required"`
+	// KafkaTopicOrders receives OrderIntents built from influencer order updates.
+	KafkaTopicOrders string `env:"KAFKA_TOPIC_INFLUENCER_ORDERS" default:"influencer_orders" validate:"required"`
+	// KafkaTopicPositions is the compacted topic holding the latest position
+	// per influencer and market.
+	KafkaTopicPositions string `env:"KAFKA_TOPIC_INFLUENCER_POSITIONS" default:"influencer_positions" validate:"required"`
+	// KafkaTopicDeadLetters receives events rejected during normalization.
+	KafkaTopicDeadLetters string `env:"KAFKA_TOPIC_DEAD_LETTERS" default:"ingestion_dead_letters" validate:"required"`
+
+	// RawEventSink selects where raw Hyperliquid events are recorded
+	// (RawEventSinkKafka, RawEventSinkFile or RawEventSinkNone).
+	RawEventSink         string `env:"RAW_EVENT_SINK" default:"kafka" validate:"oneof=kafka file none"`
+	RawEventDir          string `env:"RAW_EVENT_DIR" default:"data/raw-events"`
+	RawEventSegmentMB    int    `env:"RAW_EVENT_SEGMENT_MB" default:"64" validate:"min=1"`
+	RawEventSegmentBytes int64  `env:"-"`
+
+	// SignalOutboxDir holds the write-ahead log every signal is appended to
+	// before it is published to Kafka.
+	SignalOutboxDir          string `env:"SIGNAL_OUTBOX_DIR" default:"data/signal-outbox"`
+	SignalOutboxSegmentMB    int    `env:"SIGNAL_OUTBOX_SEGMENT_MB" default:"64" validate:"min=1"`
+	SignalOutboxSegmentBytes int64  `env:"-"`
+
+	HyperWSURL  string `env:"HYPERLIQUID_WS_URL" default:"wss://api.hyperliquid.xyz/ws" validate:"required"`
+	HyperAPIURL string `env:"HYPERLIQUID_API_URL" default:"https://api.hyperliquid.xyz" validate:"required"`
+
+	// MarketMetaRefresh is how often Hyperliquid market metadata used for
+	// signal enrichment is reloaded.
+	MarketMetaRefresh time.Duration `env:"MARKET_META_REFRESH" default:"5m" validate:"min=1s"`
+
+	// BackfillMaxLookback bounds how far back the REST gap backfill reaches
+	// when a stream (re)starts after a long outage.
+	BackfillMaxLookback time.Duration `env:"BACKFILL_MAX_LOOKBACK" default:"24h" validate:"min=0s"`
+	// FillAggregationWindow coalesces partial fills of the same order that
+	// arrive within this window into one signal. Zero disables aggregation.
+	FillAggregationWindow time.Duration `env:"FILL_AGGREGATION_WINDOW" default:"0s" validate:"min=0s"`
+
+	InfluencerSetKey  string `env:"INFLUENCER_SET_KEY" default:"ingestion:influencers:primary" validate:"required"`
+	CursorKeyPrefix   string `env:"CURSOR_KEY_PREFIX" default:"ingestion:cursors" validate:"required"`
+	PositionKeyPrefix string `env:"POSITION_KEY_PREFIX" default:"ingestion:positions" validate:"required"`
+	SequenceKeyPrefix string `env:"SEQUENCE_KEY_PREFIX" default:"ingestion:sequences" validate:"required"`
+
+	// ReconcileInterval is how often each stream compares its position book
+	// with a clearinghouseState snapshot. Zero disables reconciliation.
+	ReconcileInterval time.Duration `env:"POSITION_RECONCILE_INTERVAL" default:"1m" validate:"min=0s"`
+
+	// InfluencerAssignment selects how influencers are distributed across
+	// ingestion instances (AssignmentQueue or AssignmentHash).
+	InfluencerAssignment string `env:"INFLUENCER_ASSIGNMENT" default:"queue" validate:"oneof=queue hash"`
+	// InstanceID defaults to the hostname.
+	InstanceID          string        `env:"INSTANCE_ID"`
+	InstanceRegistryKey string        `env:"INSTANCE_REGISTRY_KEY" default:"ingestion:instances" validate:"required"`
+	InstanceTTL         time.Duration `env:"INSTANCE_TTL" default:"15s" validate:"min=1s"`
+	ShardRebalance      time.Duration `env:"SHARD_REBALANCE_INTERVAL" default:"5s" validate:"min=100ms"`
+	ShardHandoffOverlap time.Duration `env:"SHARD_HANDOFF_OVERLAP" default:"30s" validate:"min=0s"`
+	ShardVirtualNodes   int           `env:"SHARD_VIRTUAL_NODES" default:"64" validate:"min=1"`
+
+	// TracingExporter selects the span exporter (none, stdout or otlp);
+	// TracingSampleRatio is the fraction of new traces sampled.
+	TracingExporter    string  `env:"TRACING_EXPORTER" default:"none" validate:"oneof=none stdout otlp"`
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" default:"1" validate:"min=0,max=1"`
+
+	// LogLevel is the minimum level logged: debug, info, warn or error.
+	LogLevel string `env:"LOG_LEVEL" default:"info" validate:"oneof=debug info warn error"`
+
+	// SettingsKey is the Redis hash holding the Runtime settings, unless
+	// SettingsFile names a YAML file to read them from instead. Both are
+	// polled every SettingsPollInterval; the hash is also reloaded on publish
+	// to its channel.
+	SettingsKey          string        `env:"SETTINGS_KEY" default:"ingestion:settings" validate:"required"`
+	SettingsFile         string        `env:"SETTINGS_FILE"`
+	SettingsPollInterval time.Duration `env:"SETTINGS_POLL_INTERVAL" default:"10s" validate:"min=1s"`
+
+	// TradingModeKey is the Redis hash of the system-wide trading kill switch
+	// (libs/go/tradingmode), shared by every service and polled every
+	// SettingsPollInterval.
+	TradingModeKey string `env:"TRADING_MODE_KEY" default:"trading:modes" validate:"required"`
+
+	HTTPAddr string `env:"HTTP_ADDR" default:":8080" validate:"required"`
 }
 
-// LoadConfig loads configuration from environment variables.
-// This matches the TECHNICAL_SPECS at a coarse level and can be
-// refined as shared config libraries are introduced.
+// LoadConfig loads configuration from defaults, the YAML file named by
+// -config or CONFIG_FILE, environment variables and command-line flags.
 func LoadConfig() (Config, error) {
-	redisDB, err := envIntOrDefault("REDIS_DB", 0)
-	if err != nil {
+	var cfg Config
+	if err := libconfig.Load(&cfg, libconfig.Options{Args: os.Args[1:]}); err != nil {
 		return Config{}, err
 	}
 
-	cfg := Config{
-		RedisAddr:     envOrDefault("REDIS_ADDR", "localhost:6379"),
-		RedisPassword: os.Getenv("REDIS_PASSWORD"),
-		RedisDB:       redisDB,
-
-		KafkaBrokers: envCSVOrDefault("KAFKA_BROKERS", "localhost:9092"),
-		KafkaTopic:   envOrDefault("KAFKA_TOPIC_INFLUENCER_SIGNALS", "influencer_signals"),
-
-		HyperWSURL: envOrDefault("HYPERLIQUID_WS_URL", "wss://api.hyperliquid.xyz/ws"),
-
-		InfluencerSetKey: envOrDefault("INFLUENCER_SET_KEY", "ingestion:influencers:primary"),
-
-		HTTPAddr: envOrDefault("HTTP_ADDR", ":8080"),
+	if cfg.InstanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return Config{}, fmt.Errorf("resolve INSTANCE_ID from hostname: %w", err)
+		}
+		cfg.InstanceID = hostname
 	}
+	cfg.RawEventSegmentBytes = int64(cfg.RawEventSegmentMB) << 20
+	cfg.SignalOutboxSegmentBytes = int64(cfg.SignalOutboxSegmentMB) << 20
 
 	return cfg, nil
 }
@@ -0,0 +1,20 @@
+package config
+
+import "time"
+
+// Runtime holds the settings that can change without a restart. They are read
+// by key from the SETTINGS_KEY Redis hash (or SETTINGS_FILE) and applied
+// live, e.g. `HSET ingestion:settings max_streams 20` followed by
+// `PUBLISH ingestion:settings changed`. Unset keys take their defaults.
+type Runtime struct {
+	// MaxStreams caps the influencer streams one instance runs, whether
+	// acquired from the work queue or owned through sharding. Zero is
+	// unlimited.
+	MaxStreams int `key:"max_streams" default:"0" validate:"min=0"`
+	// PublishTimeout bounds direct signal and order intent writes to Kafka.
+	PublishTimeout time.Duration `key:"publish_timeout" default:"5s" validate:"min=100ms"`
+	// OutboxPublishTimeout bounds each outbox batch write to Kafka.
+	OutboxPublishTimeout time.Duration `key:"outbox_publish_timeout" default:"10s" validate:"min=100ms"`
+	// LogLevel overrides LOG_LEVEL while set.
+	LogLevel string `key:"log_level" validate:"oneof=debug info warn error"`
+}
@@ -1,7 +1,40 @@
 package domain
 
+import "slices"
+
 // Influencer represents minimal configuration for an influencer account.
 // For now, we only track the Hyperliquid address.
 type Influencer struct {
 	Address string `json:"address"`
 }
+
+// Cursor marks the last fill published for an influencer+market pair.
+// Fills are ordered by exchange time, then trade id (tid).
+type Cursor struct {
+	TimeMs int64 `json:"time"`
+	Tid    int64 `json:"tid"`
+	// Tids lists every tid published at TimeMs when known. Fills of the same
+	// millisecond can arrive out of tid order, so a lower tid missing from
+	// Tids is not covered.
+	Tids []int64 `json:"tids,omitempty"`
+}
+
+// Covers reports whether a fill with the given time and tid is at or below the cursor.
+func (c Cursor) Covers(timeMs, tid int64) bool {
+	if timeMs != c.TimeMs {
+		return timeMs < c.TimeMs
+	}
+	if len(c.Tids) == 0 {
+		return tid <= c.Tid
+	}
+	return slices.Contains(c.Tids, tid)
+}
+
+// Position is an influencer's net position on one market. Size is signed:
+// positive for long, negative for short, zero when flat.
+type Position struct {
+	Market    string  `json:"market"`
+	Size      float64 `json:"size"`
+	EntryPx   float64 `json:"entry_px"`
+	UpdatedMs int64   `json:"updated_ms"`
+}
@@ -0,0 +1,34 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// DeadLetterPublisher publishes rejected events to the ingestion dead-letter
+// topic, keyed by influencer address.
+type DeadLetterPublisher struct {
+	pub   *messaging.TypedPublisher[*busv1.DeadLetter]
+	Topic string
+}
+
+// NewDeadLetterPublisher publishes to pub, which writes to the
+// KAFKA_TOPIC_DEAD_LETTERS topic.
+func NewDeadLetterPublisher(cfg config.Config, pub messaging.Publisher) *DeadLetterPublisher {
+	key := func(dl *busv1.DeadLetter) string { return dl.GetEvent().GetInfluencerAddress() }
+	return &DeadLetterPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), key),
+		Topic: cfg.KafkaTopicDeadLetters,
+	}
+}
+
+func (p *DeadLetterPublisher) Publish(ctx context.Context, dl *busv1.DeadLetter) error {
+	return p.pub.Publish(ctx, dl)
+}
+
+func (p *DeadLetterPublisher) Close() error {
+	return p.pub.Close()
+}
@@ -0,0 +1,11 @@
+package kafka
+
+import (
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// producerFor identifies this ingestion instance in message headers.
+func producerFor(cfg config.Config) messaging.Producer {
+	return messaging.Producer{Service: "ingestion", Instance: cfg.InstanceID}
+}
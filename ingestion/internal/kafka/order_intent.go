@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// OrderIntentPublisher publishes influencer order intents keyed by
+// influencer.
+type OrderIntentPublisher struct {
+	pub   *messaging.TypedPublisher[*busv1.OrderIntent]
+	Topic string
+}
+
+// NewOrderIntentPublisher publishes to pub, which writes to the
+// KAFKA_TOPIC_INFLUENCER_ORDERS topic.
+func NewOrderIntentPublisher(cfg config.Config, pub messaging.Publisher) *OrderIntentPublisher {
+	return &OrderIntentPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), (*busv1.OrderIntent).GetInfluencerId),
+		Topic: cfg.KafkaTopicOrders,
+	}
+}
+
+func (p *OrderIntentPublisher) Publish(ctx context.Context, oi *busv1.OrderIntent) error {
+	return p.pub.Publish(ctx, oi)
+}
+
+func (p *OrderIntentPublisher) Close() error {
+	return p.pub.Close()
+}
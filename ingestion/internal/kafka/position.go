@@ -0,0 +1,35 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// PositionPublisher publishes influencer position snapshots to a compacted
+// topic keyed by influencer and market.
+type PositionPublisher struct {
+	pub   *messaging.TypedPublisher[*busv1.InfluencerPosition]
+	Topic string
+}
+
+// NewPositionPublisher publishes to pub, which writes to the
+// KAFKA_TOPIC_INFLUENCER_POSITIONS topic. On Kafka the topic must be created
+// compacted (kafkabus.EnsureCompactedTopic) rather than auto-created.
+func NewPositionPublisher(cfg config.Config, pub messaging.Publisher) *PositionPublisher {
+	key := func(pos *busv1.InfluencerPosition) string { return pos.GetInfluencerId() + ":" + pos.GetMarket() }
+	return &PositionPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), key),
+		Topic: cfg.KafkaTopicPositions,
+	}
+}
+
+func (p *PositionPublisher) Publish(ctx context.Context, pos *busv1.InfluencerPosition) error {
+	return p.pub.Publish(ctx, pos)
+}
+
+func (p *PositionPublisher) Close() error {
+	return p.pub.Close()
+}
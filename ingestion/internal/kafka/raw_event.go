@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// RawEventPublisher appends raw Hyperliquid events to the raw events topic,
+// keyed by influencer address.
+type RawEventPublisher struct {
+	pub   *messaging.TypedPublisher[*busv1.RawEvent]
+	Topic string
+}
+
+// NewRawEventPublisher publishes to pub, which writes to the
+// KAFKA_TOPIC_RAW_EVENTS topic.
+func NewRawEventPublisher(cfg config.Config, pub messaging.Publisher) *RawEventPublisher {
+	return &RawEventPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), (*busv1.RawEvent).GetInfluencerAddress),
+		Topic: cfg.KafkaTopicRawEvents,
+	}
+}
+
+func (p *RawEventPublisher) Append(ctx context.Context, ev *busv1.RawEvent) error {
+	return p.pub.Publish(ctx, ev)
+}
+
+func (p *RawEventPublisher) Close() error {
+	return p.pub.Close()
+}
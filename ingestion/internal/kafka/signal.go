@@ -2,48 +2,36 @@ package kafka
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
 )
 
-// SignalPublisher publishes normalized Signals to Kafka.
+// SignalPublisher publishes normalized Signals keyed by influencer.
 type SignalPublisher struct {
-	writer *kafka.Writer
-	Topic  string
+	pub   *messaging.TypedPublisher[*busv1.Signal]
+	Topic string
 }
 
-func NewSignalPublisher(cfg config.Config) *SignalPublisher {
-	writer := &kafka.Writer{
-		Addr:                   kafka.TCP(cfg.KafkaBrokers...),
-		Topic:                  cfg.KafkaTopic,
-		RequiredAcks:           kafka.RequireAll,
-		Balancer:               &kafka.Hash{},
-		AllowAutoTopicCreation: true,
+// NewSignalPublisher publishes to pub, which writes to the KAFKA_TOPIC_INFLUENCER_SIGNALS topic.
+func NewSignalPublisher(cfg config.Config, pub messaging.Publisher) *SignalPublisher {
+	return &SignalPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), (*busv1.Signal).GetInfluencerId),
+		Topic: cfg.KafkaTopic,
 	}
-	return &SignalPublisher{writer: writer, Topic: cfg.KafkaTopic}
 }
 
 func (p *SignalPublisher) Publish(ctx context.Context, s *busv1.Signal) error {
-	value, err := proto.Marshal(s)
-	if err != nil {
-		return fmt.Errorf("marshal signal proto: %w", err)
-	}
-
-	msg := kafka.Message{
-		Key:   []byte(s.GetInfluencerId()),
-		Value: value,
-	}
+	return p.pub.Publish(ctx, s)
+}
 
-	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		return fmt.Errorf("kafka write: %w", err)
-	}
-	return nil
+// PublishBatch writes msgs in one request. Messages of the same key go to one
+// partition in slice order.
+func (p *SignalPublisher) PublishBatch(ctx context.Context, msgs []messaging.Traced[*busv1.Signal]) error {
+	return p.pub.PublishBatch(ctx, msgs)
 }
 
 func (p *SignalPublisher) Close() error {
-	return p.writer.Close()
+	return p.pub.Close()
 }
@@ -0,0 +1,36 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigController reports the effective configuration: the static config
+// loaded at startup and the runtime settings currently in effect. Secrets are
+// redacted.
+type ConfigController struct {
+	static   map[string]string
+	settings *libconfig.Reloader[config.Runtime]
+}
+
+func NewConfigController(cfg config.Config, settings *libconfig.Reloader[config.Runtime]) (*ConfigController, error) {
+	static, err := libconfig.Redacted(&cfg)
+	if err != nil {
+		return nil, err
+	}
+	return &ConfigController{static: static, settings: settings}, nil
+}
+
+func (c *ConfigController) RegisterConfigRoutes(rg *gin.RouterGroup) {
+	rg.GET("/config", c.handleGetConfig)
+}
+
+func (c *ConfigController) handleGetConfig(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, gin.H{
+		"static":  c.static,
+		"runtime": c.settings.Redacted(),
+	})
+}
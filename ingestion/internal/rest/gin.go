@@ -4,17 +4,23 @@ import (
 	"net/http"
 
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/health"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
 	"github.com/gin-gonic/gin"
 )
 
-func NewServer(cfg config.Config) (*gin.Engine, *http.Server) {
+// NewServer builds the ingestion HTTP engine with the probe and metrics routes.
+// /health/ready answers 503 while any check in ready fails; /health is kept as
+// an alias of /health/live for existing probes.
+func NewServer(cfg config.Config, ready *health.Checker) (*gin.Engine, *http.Server) {
 	gin.SetMode(gin.ReleaseMode)
 	r := gin.New()
 	r.Use(gin.Recovery())
 
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(http.StatusOK, gin.H{"status": "ok"})
-	})
+	r.GET("/health", gin.WrapH(health.LiveHandler()))
+	r.GET("/health/live", gin.WrapH(health.LiveHandler()))
+	r.GET("/health/ready", gin.WrapH(health.ReadyHandler(ready)))
+	r.GET("/metrics", gin.WrapH(observability.MetricsHandler()))
 	srv := &http.Server{
 		Addr:    cfg.HTTPAddr,
 		Handler: r,
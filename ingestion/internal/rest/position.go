@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	"github.com/gin-gonic/gin"
+)
+
+// PositionController serves the materialized influencer positions kept by the
+// ingestion streams.
+type PositionController struct {
+	store *store.PositionStore
+}
+
+func NewPositionController(store *store.PositionStore) *PositionController {
+	return &PositionController{store: store}
+}
+
+func (c *PositionController) RegisterPositionRoutes(rg *gin.RouterGroup) {
+	rg.GET("/influencers/:address/positions", c.handleInfluencerPositions)
+	rg.GET("/positions", c.handleListPositions)
+}
+
+type positionResponse struct {
+	Influencer string    `json:"influencer"`
+	Market     string    `json:"market"`
+	Side       string    `json:"side"`
+	Size       float64   `json:"size"`
+	EntryPx    float64   `json:"entry_px"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func newPositionResponse(influencer string, pos domain.Position) positionResponse {
+	side := "LONG"
+	if pos.Size < 0 {
+		side = "SHORT"
+	}
+	return positionResponse{
+		Influencer: influencer,
+		Market:     pos.Market,
+		Side:       side,
+		Size:       math.Abs(pos.Size),
+		EntryPx:    pos.EntryPx,
+		UpdatedAt:  time.UnixMilli(pos.UpdatedMs).UTC(),
+	}
+}
+
+func (c *PositionController) handleInfluencerPositions(ctx *gin.Context) {
+	address := strings.ToLower(ctx.Param("address"))
+
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+	defer cancel()
+	positions, err := c.store.All(reqCtx, address)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	res := make([]positionResponse, 0, len(positions))
+	for _, pos := range positions {
+		res = append(res, newPositionResponse(address, pos))
+	}
+	sortPositions(res)
+	ctx.JSON(http.StatusOK, res)
+}
+
+// handleListPositions returns the open positions on ?market= (canonical, e.g.
+// ETH-PERP), or on every market when it is omitted.
+func (c *PositionController) handleListPositions(ctx *gin.Context) {
+	reqCtx, cancel := context.WithTimeout(ctx.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	list := []string{ctx.Query("market")}
+	if list[0] == "" {
+		var err error
+		if list, err = c.store.Markets(reqCtx); err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	res := make([]positionResponse, 0)
+	for _, market := range list {
+		positions, err := c.store.ByMarket(reqCtx, market)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		for address, pos := range positions {
+			res = append(res, newPositionResponse(address, pos))
+		}
+	}
+	sortPositions(res)
+	ctx.JSON(http.StatusOK, res)
+}
+
+func sortPositions(res []positionResponse) {
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Market != res[j].Market {
+			return res[i].Market < res[j].Market
+		}
+		return res[i].Influencer < res[j].Influencer
+	})
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// FillAggregator coalesces partial fills of the same order (oid) that arrive
+// within a short window into one group before normalization, so a single
+// influencer market order does not fan out as many tiny signals.
+//
+// Groups are emitted when their window elapses, when a fill for the same coin
+// but a different order arrives (preserving per-market ordering), or on Flush.
+// emit is always invoked with emitLock held, and Add must be called with it
+// held too, so groups closed by the window timer are emitted in order with
+// those closed by Add. The aggregator's own lock is never held during emit.
+type FillAggregator struct {
+	window   time.Duration
+	emitLock sync.Locker
+	emit     func(fills []hl.WsOrderFill, backfilled bool)
+
+	mu      sync.Mutex
+	nextSeq uint64
+	pending map[int64]*fillGroup
+}
+
+type fillGroup struct {
+	seq        uint64
+	coin       string
+	fills      []hl.WsOrderFill
+	backfilled bool
+	timer      *time.Timer
+}
+
+func NewFillAggregator(window time.Duration, emitLock sync.Locker, emit func(fills []hl.WsOrderFill, backfilled bool)) *FillAggregator {
+	return &FillAggregator{
+		window:   window,
+		emitLock: emitLock,
+		emit:     emit,
+		pending:  make(map[int64]*fillGroup),
+	}
+}
+
+// Add buffers fill into the group of its order, opening a new group if needed.
+// Groups it closes are emitted before it returns. The caller holds emitLock.
+func (a *FillAggregator) Add(fill hl.WsOrderFill, backfilled bool) {
+	for _, g := range a.add(fill, backfilled) {
+		a.emit(g.fills, g.backfilled)
+	}
+}
+
+func (a *FillAggregator) add(fill hl.WsOrderFill, backfilled bool) []*fillGroup {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if g, ok := a.pending[fill.Oid]; ok && fill.Oid != 0 && g.coin == fill.Coin {
+		// A backfill and the following snapshot can both deliver a fill that
+		// is still buffered here, before the cursor has moved past it.
+		if !slices.ContainsFunc(g.fills, func(f hl.WsOrderFill) bool { return f.Tid == fill.Tid }) {
+			g.fills = append(g.fills, fill)
+			g.backfilled = g.backfilled || backfilled
+		}
+		return nil
+	}
+
+	due := a.takeLocked(func(g *fillGroup) bool { return g.coin == fill.Coin })
+	if fill.Oid == 0 {
+		return append(due, &fillGroup{coin: fill.Coin, fills: []hl.WsOrderFill{fill}, backfilled: backfilled})
+	}
+
+	a.nextSeq++
+	g := &fillGroup{seq: a.nextSeq, coin: fill.Coin, fills: []hl.WsOrderFill{fill}, backfilled: backfilled}
+	oid := fill.Oid
+	g.timer = time.AfterFunc(a.window, func() { a.expire(oid, g) })
+	a.pending[oid] = g
+	return due
+}
+
+// Pending reports whether a fill with the given oid and tid is buffered.
+func (a *FillAggregator) Pending(oid, tid int64) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	g, ok := a.pending[oid]
+	return ok && slices.ContainsFunc(g.fills, func(f hl.WsOrderFill) bool { return f.Tid == tid })
+}
+
+// Flush emits every pending group in arrival order.
+func (a *FillAggregator) Flush() {
+	a.emitLock.Lock()
+	defer a.emitLock.Unlock()
+	a.mu.Lock()
+	due := a.takeLocked(func(*fillGroup) bool { return true })
+	a.mu.Unlock()
+	for _, g := range due {
+		a.emit(g.fills, g.backfilled)
+	}
+}
+
+func (a *FillAggregator) expire(oid int64, g *fillGroup) {
+	a.emitLock.Lock()
+	defer a.emitLock.Unlock()
+	a.mu.Lock()
+	if a.pending[oid] != g {
+		a.mu.Unlock()
+		return
+	}
+	delete(a.pending, oid)
+	a.mu.Unlock()
+	a.emit(g.fills, g.backfilled)
+}
+
+// takeLocked removes the pending groups matching match and returns them in
+// arrival order.
+func (a *FillAggregator) takeLocked(match func(*fillGroup) bool) []*fillGroup {
+	var due []*fillGroup
+	for oid, g := range a.pending {
+		if !match(g) {
+			continue
+		}
+		g.timer.Stop()
+		delete(a.pending, oid)
+		due = append(due, g)
+	}
+	slices.SortFunc(due, func(x, y *fillGroup) int { return cmp.Compare(x.seq, y.seq) })
+	return due
+}
+
+// mergedPxPlaces is the precision the average price of merged fills is
+// computed at before it is rounded to the market's price precision.
+const mergedPxPlaces = 18
+
+// MergeFills combines partial fills of one order into a single fill: sizes,
+// fees and closed PnL are summed, the price is the size-weighted average
+// rounded to a valid price on market, the start position is taken from the
+// earliest fill by exchange time and tid and time/tid from the latest, so the
+// persisted cursor covers every constituent. It also returns the tids of the
+// source fills in that order.
+func MergeFills(fills []hl.WsOrderFill, market markets.Market) (hl.WsOrderFill, []int64, error) {
+	if len(fills) == 0 {
+		return hl.WsOrderFill{}, nil, fmt.Errorf("no fills to merge")
+	}
+	// Fills can arrive out of exchange order (e.g. a backfilled fill after a
+	// live one), so order them before picking the earliest.
+	fills = slices.SortedStableFunc(slices.Values(fills), compareFills)
+	merged := fills[0]
+	tids := make([]int64, 0, len(fills))
+	if len(fills) == 1 {
+		return merged, append(tids, merged.Tid), nil
+	}
+
+	var totalSz, notional, fee, pnl numbers.Decimal
+	for _, f := range fills {
+		px, err := numbers.ParseDecimal(f.Px)
+		if err != nil {
+			return hl.WsOrderFill{}, nil, fmt.Errorf("fill tid %d px: %w", f.Tid, err)
+		}
+		sz, err := numbers.ParseDecimal(f.Sz)
+		if err != nil {
+			return hl.WsOrderFill{}, nil, fmt.Errorf("fill tid %d sz: %w", f.Tid, err)
+		}
+		totalSz = totalSz.Add(sz)
+		notional = notional.Add(px.Mul(sz))
+		if v, err := numbers.ParseDecimal(f.Fee); err == nil {
+			fee = fee.Add(v)
+		}
+		if v, err := numbers.ParseDecimal(f.ClosedPnl); err == nil {
+			pnl = pnl.Add(v)
+		}
+		merged.Crossed = merged.Crossed || f.Crossed
+		tids = append(tids, f.Tid)
+	}
+	latest := fills[len(fills)-1]
+	merged.Time = latest.Time
+	merged.Tid = latest.Tid
+	if totalSz.IsZero() {
+		return hl.WsOrderFill{}, nil, fmt.Errorf("fills for oid %d have zero total size", merged.Oid)
+	}
+	avgPx, err := notional.Quo(totalSz, mergedPxPlaces)
+	if err != nil {
+		return hl.WsOrderFill{}, nil, fmt.Errorf("average price of oid %d: %w", merged.Oid, err)
+	}
+
+	merged.Sz = totalSz.String()
+	merged.Px = market.RoundPrice(avgPx).String()
+	merged.Fee = fee.String()
+	merged.ClosedPnl = pnl.String()
+	merged.BuilderFee = nil
+	return merged, tids, nil
+}
+
+// fillMarket returns the market whose price precision merged fills on coin are
+// rounded to. Markets missing from the registry (HIP-3, newly listed before the
+// next refresh) get the finest precision their type allows.
+func fillMarket(registry *markets.Registry, coin string) markets.Market {
+	sym := registry.Normalize(coin)
+	if registry != nil {
+		if m, ok := registry.Lookup(sym.Market); ok {
+			return m
+		}
+	}
+	return markets.Market{Symbol: sym.Market, Type: sym.Type}
+}
+
+// compareFills orders fills by exchange time, then tid.
+func compareFills(a, b hl.WsOrderFill) int {
+	if a.Time != b.Time {
+		return cmp.Compare(a.Time, b.Time)
+	}
+	return cmp.Compare(a.Tid, b.Tid)
+}
+
+func joinTids(tids []int64) string {
+	parts := make([]string, len(tids))
+	for i, tid := range tids {
+		parts[i] = strconv.FormatInt(tid, 10)
+	}
+	return strings.Join(parts, ",")
+}
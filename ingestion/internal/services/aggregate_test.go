@@ -0,0 +1,346 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+func wsFill(coin string, oid, tid, timeMs int64, px, sz, startPos string) hl.WsOrderFill {
+	return hl.WsOrderFill{
+		Coin:          coin,
+		Px:            px,
+		Sz:            sz,
+		Side:          "B",
+		Time:          timeMs,
+		StartPosition: startPos,
+		Oid:           oid,
+		Tid:           tid,
+		Fee:           "0.01",
+		ClosedPnl:     "0.0",
+	}
+}
+
+func TestMergeFills(t *testing.T) {
+	eth := markets.Market{Name: "ETH", Symbol: "ETH-PERP", Type: markets.TypePerp, SzDecimals: 4}
+	tests := []struct {
+		name      string
+		fills     []hl.WsOrderFill
+		market    markets.Market
+		wantPx    string
+		wantSz    string
+		wantStart string
+		wantTime  int64
+		wantTid   int64
+		wantTids  []int64
+		wantFee   string
+		wantErr   bool
+	}{
+		{
+			name:      "single fill is returned as is",
+			fills:     []hl.WsOrderFill{wsFill("ETH", 1, 10, 1000, "2000", "0.5", "0")},
+			wantPx:    "2000",
+			wantSz:    "0.5",
+			wantStart: "0",
+			wantTime:  1000,
+			wantTid:   10,
+			wantTids:  []int64{10},
+			wantFee:   "0.01",
+		},
+		{
+			name: "sizes and fees are summed and price is size weighted",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "2000", "1.0", "0"),
+				wsFill("ETH", 1, 11, 1001, "2010", "3.0", "1.0"),
+			},
+			wantPx:    "2007.5",
+			wantSz:    "4",
+			wantStart: "0",
+			wantTime:  1001,
+			wantTid:   11,
+			wantTids:  []int64{10, 11},
+			wantFee:   "0.02",
+		},
+		{
+			name: "start position comes from the earliest fill, not the first to arrive",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 12, 1002, "2000", "1", "2"),
+				wsFill("ETH", 1, 10, 1000, "2000", "1", "0"),
+				wsFill("ETH", 1, 11, 1000, "2000", "1", "1"),
+			},
+			wantPx:    "2000",
+			wantSz:    "3",
+			wantStart: "0",
+			wantTime:  1002,
+			wantTid:   12,
+			wantTids:  []int64{10, 11, 12},
+			wantFee:   "0.03",
+		},
+		{
+			name: "sizes are summed exactly",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "2000", "0.1", "0"),
+				wsFill("ETH", 1, 11, 1001, "2000", "0.2", "0.1"),
+			},
+			wantPx:    "2000",
+			wantSz:    "0.3",
+			wantStart: "0",
+			wantTime:  1001,
+			wantTid:   11,
+			wantTids:  []int64{10, 11},
+			wantFee:   "0.02",
+		},
+		{
+			name: "average price is rounded to five significant figures",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "2000.1", "1", "0"),
+				wsFill("ETH", 1, 11, 1001, "2000.2", "2", "1"),
+			},
+			wantPx:    "2000.2",
+			wantSz:    "3",
+			wantStart: "0",
+			wantTime:  1001,
+			wantTid:   11,
+			wantTids:  []int64{10, 11},
+			wantFee:   "0.02",
+		},
+		{
+			name: "average price is rounded to the market's price decimals",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "1.001", "1", "0"),
+				wsFill("ETH", 1, 11, 1001, "1.010", "1", "1"),
+			},
+			wantPx:    "1.01",
+			wantSz:    "2",
+			wantStart: "0",
+			wantTime:  1001,
+			wantTid:   11,
+			wantTids:  []int64{10, 11},
+			wantFee:   "0.02",
+		},
+		{
+			name: "spot prices keep more decimals",
+			fills: []hl.WsOrderFill{
+				wsFill("PURR/USDC", 1, 10, 1000, "1.001", "1", "0"),
+				wsFill("PURR/USDC", 1, 11, 1001, "1.010", "1", "1"),
+			},
+			market:    markets.Market{Name: "PURR/USDC", Symbol: "PURR/USDC-SPOT", Type: markets.TypeSpot},
+			wantPx:    "1.0055",
+			wantSz:    "2",
+			wantStart: "0",
+			wantTime:  1001,
+			wantTid:   11,
+			wantTids:  []int64{10, 11},
+			wantFee:   "0.02",
+		},
+		{
+			name: "unparsable size",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "2000", "1", "0"),
+				wsFill("ETH", 1, 11, 1001, "2000", "x", "1"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero total size",
+			fills: []hl.WsOrderFill{
+				wsFill("ETH", 1, 10, 1000, "2000", "0", "0"),
+				wsFill("ETH", 1, 11, 1001, "2000", "0", "0"),
+			},
+			wantErr: true,
+		},
+		{
+			name:    "no fills",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			market := tt.market
+			if market.Name == "" {
+				market = eth
+			}
+			merged, tids, err := MergeFills(tt.fills, market)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("MergeFills = %+v, want error", merged)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("MergeFills: %v", err)
+			}
+			if merged.Px != tt.wantPx || merged.Sz != tt.wantSz || merged.StartPosition != tt.wantStart || merged.Fee != tt.wantFee {
+				t.Errorf("merged px/sz/start/fee = %s/%s/%s/%s, want %s/%s/%s/%s", merged.Px, merged.Sz, merged.StartPosition, merged.Fee, tt.wantPx, tt.wantSz, tt.wantStart, tt.wantFee)
+			}
+			if merged.Time != tt.wantTime || merged.Tid != tt.wantTid {
+				t.Errorf("merged time/tid = %d/%d, want %d/%d", merged.Time, merged.Tid, tt.wantTime, tt.wantTid)
+			}
+			if !slices.Equal(tids, tt.wantTids) {
+				t.Errorf("tids = %v, want %v", tids, tt.wantTids)
+			}
+		})
+	}
+}
+
+// emitted records the groups a FillAggregator emits.
+type emitted struct {
+	mu     sync.Mutex
+	groups [][]int64
+	ch     chan struct{}
+}
+
+func (e *emitted) emit(fills []hl.WsOrderFill, _ bool) {
+	tids := make([]int64, len(fills))
+	for i, f := range fills {
+		tids[i] = f.Tid
+	}
+	e.mu.Lock()
+	e.groups = append(e.groups, tids)
+	e.mu.Unlock()
+	if e.ch != nil {
+		e.ch <- struct{}{}
+	}
+}
+
+func (e *emitted) snapshot() [][]int64 {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return slices.Clone(e.groups)
+}
+
+func TestFillAggregatorGroupsByOrder(t *testing.T) {
+	var lock sync.Mutex
+	out := &emitted{}
+	agg := NewFillAggregator(time.Hour, &lock, out.emit)
+
+	lock.Lock()
+	agg.Add(wsFill("ETH", 1, 10, 1000, "2000", "1", "0"), false)
+	agg.Add(wsFill("BTC", 2, 20, 1000, "40000", "1", "0"), false)
+	agg.Add(wsFill("ETH", 1, 11, 1001, "2000", "1", "1"), false)
+	agg.Add(wsFill("ETH", 1, 11, 1001, "2000", "1", "1"), true) // duplicate delivery
+	if got := out.snapshot(); len(got) != 0 {
+		t.Fatalf("emitted %v before any group closed", got)
+	}
+	// A different order on the same coin closes the pending ETH group first.
+	agg.Add(wsFill("ETH", 3, 12, 1002, "2000", "1", "2"), false)
+	// Fills without an oid are never grouped.
+	agg.Add(wsFill("SOL", 0, 30, 1003, "100", "1", "0"), false)
+	lock.Unlock()
+
+	agg.Flush()
+	want := [][]int64{{10, 11}, {30}, {20}, {12}}
+	if got := out.snapshot(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("emitted groups = %v, want %v", got, want)
+	}
+}
+
+func TestFillAggregatorExpiresGroupsAfterWindow(t *testing.T) {
+	var lock sync.Mutex
+	out := &emitted{ch: make(chan struct{}, 1)}
+	agg := NewFillAggregator(20*time.Millisecond, &lock, out.emit)
+
+	lock.Lock()
+	agg.Add(wsFill("ETH", 1, 10, 1000, "2000", "1", "0"), false)
+	agg.Add(wsFill("ETH", 1, 11, 1001, "2000", "1", "1"), false)
+	lock.Unlock()
+
+	select {
+	case <-out.ch:
+	case <-time.After(time.Second):
+		t.Fatal("group was not emitted after the window")
+	}
+	if got := out.snapshot(); !slices.EqualFunc(got, [][]int64{{10, 11}}, slices.Equal) {
+		t.Fatalf("emitted groups = %v", got)
+	}
+}
+
+// A slow emit (e.g. a Kafka publish) must not hold the aggregator's lock, so
+// fills of other orders can still be buffered while it runs.
+func TestFillAggregatorEmitsOutsideItsLock(t *testing.T) {
+	var lock sync.Mutex
+	release := make(chan struct{})
+	entered := make(chan struct{}, 2)
+	agg := NewFillAggregator(10*time.Millisecond, &lock, func([]hl.WsOrderFill, bool) {
+		entered <- struct{}{}
+		<-release
+	})
+
+	lock.Lock()
+	agg.Add(wsFill("ETH", 1, 10, 1000, "2000", "1", "0"), false)
+	lock.Unlock()
+	<-entered // the window timer is emitting, holding only the emit lock
+
+	added := make(chan struct{})
+	go func() {
+		agg.add(wsFill("BTC", 2, 20, 1000, "40000", "1", "0"), false)
+		close(added)
+	}()
+	select {
+	case <-added:
+	case <-time.After(time.Second):
+		t.Fatal("buffering a fill blocked on an in-flight emit")
+	}
+	close(release)
+}
+
+// rawSink counts appended raw events.
+type rawSink struct {
+	mu     sync.Mutex
+	events []*busv1.RawEvent
+}
+
+func (r *rawSink) Append(_ context.Context, ev *busv1.RawEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, ev)
+	return nil
+}
+
+func (r *rawSink) Close() error { return nil }
+
+// A fill delivered by both userFills and userEvents while its group is still
+// buffered is recorded and published once.
+func TestProcessFillsDropsBufferedDuplicates(t *testing.T) {
+	svc := newStubService(t, http.NotFoundHandler())
+	raw := &rawSink{}
+	svc.raw = raw
+
+	var published []*busv1.Signal
+	p := &fillPipeline{
+		inf: &domain.Influencer{Address: testInfluencer},
+		handler: func(_ context.Context, sig *busv1.Signal) error {
+			published = append(published, sig)
+			return nil
+		},
+	}
+	ctx := context.Background()
+	p.agg = NewFillAggregator(time.Hour, &p.mu, func(fills []hl.WsOrderFill, backfilled bool) {
+		svc.publishAggregate(ctx, p, fills, backfilled)
+	})
+
+	fill := Received[hl.WsOrderFill]{Value: wsFill("ETH", 1, 10, 1000, "2000", "1", "0")}
+	now := time.Now()
+	if skipped := svc.processFills(ctx, p, []Received[hl.WsOrderFill]{fill}, now, false); skipped != 0 {
+		t.Fatalf("first delivery skipped %d fills", skipped)
+	}
+	if skipped := svc.processFills(ctx, p, []Received[hl.WsOrderFill]{fill}, now, false); skipped != 1 {
+		t.Fatalf("second delivery skipped %d fills, want 1", skipped)
+	}
+	p.agg.Flush()
+
+	if len(raw.events) != 1 {
+		t.Fatalf("recorded %d raw events, want 1", len(raw.events))
+	}
+	if len(published) != 1 {
+		t.Fatalf("published %d signals, want 1", len(published))
+	}
+}
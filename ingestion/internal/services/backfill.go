@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// backfillPageSize is the maximum number of fills Hyperliquid returns for a
+// single userFillsByTime request; a full page means more may be pending.
+const backfillPageSize = 2000
+
+// backfillFromCursor replays fills published after the influencer's latest
+// persisted cursor up to until. Influencers without any cursor have no known
+// gap and are skipped.
+func (s *HyperliquidService) backfillFromCursor(ctx context.Context, p *fillPipeline, until time.Time) error {
+	inf := p.inf
+	if s.cursors == nil {
+		return nil
+	}
+	cur, ok, err := s.cursors.Latest(ctx, inf.Address)
+	if err != nil {
+		return fmt.Errorf("load latest cursor: %w", err)
+	}
+	if !ok {
+		return nil
+	}
+
+	since := time.UnixMilli(cur.TimeMs)
+	if s.backfillLookback > 0 {
+		if floor := until.Add(-s.backfillLookback); since.Before(floor) {
+			s.logger.InfoContext(ctx, "backfill truncated to lookback", slog.Time("since", floor.UTC()), slog.Time("cursor", since.UTC()))
+			since = floor
+		}
+	}
+
+	n, err := s.backfill(ctx, p, since, until)
+	if n > 0 {
+		s.logger.InfoContext(ctx, "backfilled fills", slog.Int("fills", n), slog.Time("since", since.UTC()))
+	}
+	return err
+}
+
+// Backfill queries Hyperliquid's userFillsByTime endpoint for fills in
+// [since, until] and feeds them, oldest first, through the regular
+// normalization path with metadata["backfilled"]="true". Fills at or below the
+// persisted cursor are dropped as usual. It returns the number of fills fetched.
+func (s *HyperliquidService) Backfill(ctx context.Context, inf *domain.Influencer, since, until time.Time, handler SignalHandler) (int, error) {
+	if err := s.markets.Wait(ctx); err != nil {
+		return 0, fmt.Errorf("wait for market metadata: %w", err)
+	}
+	return s.backfill(ctx, &fillPipeline{inf: inf, handler: handler, done: ctx.Done()}, since, until)
+}
+
+func (s *HyperliquidService) backfill(ctx context.Context, p *fillPipeline, since, until time.Time) (int, error) {
+	inf := p.inf
+	start := since.UnixMilli()
+	end := until.UnixMilli()
+	var (
+		last  domain.Cursor
+		total int
+	)
+	for start <= end {
+		page, err := s.info.UserFillsByTime(ctx, inf.Address, start, &end, nil)
+		if err != nil {
+			return total, fmt.Errorf("userFillsByTime: %w", err)
+		}
+
+		fills := make([]hl.WsOrderFill, 0, len(page))
+		for _, f := range page {
+			// Page boundaries are inclusive, so skip fills already replayed.
+			if total > 0 && last.Covers(f.Time, f.Tid) {
+				continue
+			}
+			fills = append(fills, wsFillFromREST(f))
+		}
+		slices.SortStableFunc(fills, compareFills)
+		if len(fills) == 0 {
+			break
+		}
+
+		received := make([]Received[hl.WsOrderFill], len(fills))
+		for i, f := range fills {
+			received[i] = Received[hl.WsOrderFill]{Value: f}
+		}
+		s.processFills(ctx, p, received, until, true)
+		total += len(fills)
+		tail := fills[len(fills)-1]
+		last = domain.Cursor{TimeMs: tail.Time, Tid: tail.Tid}
+
+		if len(page) < backfillPageSize {
+			break
+		}
+		next := tail.Time
+		if next <= start {
+			// A full page within a single millisecond; step past it rather than loop.
+			next = start + 1
+		}
+		start = next
+	}
+	return total, nil
+}
+
+// wsFillFromREST maps a REST fill onto the WebSocket fill shape consumed by
+// NormalizeEventToSignal.
+func wsFillFromREST(f hl.Fill) hl.WsOrderFill {
+	fill := hl.WsOrderFill{
+		Coin:          f.Coin,
+		Px:            f.Price,
+		Sz:            f.Size,
+		Side:          f.Side,
+		Time:          f.Time,
+		StartPosition: f.StartPosition,
+		Dir:           f.Dir,
+		ClosedPnl:     f.ClosedPnl,
+		Hash:          f.Hash,
+		Oid:           f.Oid,
+		Crossed:       f.Crossed,
+		Fee:           f.Fee,
+		Tid:           f.Tid,
+		FeeToken:      f.FeeToken,
+	}
+	if f.BuilderFee != "" {
+		builderFee := f.BuilderFee
+		fill.BuilderFee = &builderFee
+	}
+	return fill
+}
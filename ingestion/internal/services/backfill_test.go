@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+)
+
+const testInfluencer = "0x1111111111111111111111111111111111111111"
+
+// fillsStub serves userFillsByTime requests from a fixed list of pages and
+// records every request body it receives.
+type fillsStub struct {
+	mu       sync.Mutex
+	pages    [][]map[string]any
+	requests []map[string]any
+}
+
+func (f *fillsStub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/info" {
+		http.NotFound(w, r)
+		return
+	}
+	var body map[string]any
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, body)
+	page := []map[string]any{}
+	if len(f.pages) > 0 {
+		page, f.pages = f.pages[0], f.pages[1:]
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(page)
+}
+
+func restFill(coin, side, px, sz, startPos string, timeMs, tid int64) map[string]any {
+	return map[string]any{
+		"coin":          coin,
+		"px":            px,
+		"sz":            sz,
+		"side":          side,
+		"time":          timeMs,
+		"startPosition": startPos,
+		"dir":           "",
+		"closedPnl":     "0.0",
+		"hash":          fmt.Sprintf("0xhash%d", tid),
+		"oid":           tid * 10,
+		"crossed":       true,
+		"fee":           "0.01",
+		"tid":           tid,
+		"feeToken":      "USDC",
+	}
+}
+
+func newStubService(t *testing.T, stub http.Handler) *HyperliquidService {
+	t.Helper()
+	srv := httptest.NewServer(stub)
+	t.Cleanup(srv.Close)
+	cfg := config.Config{HyperAPIURL: srv.URL}
+	return NewHyperliquidService(cfg, nil, nil, nil, nil, nil, nil, nil, slog.New(slog.DiscardHandler))
+}
+
+func TestBackfillReplaysMissedFillsInOrder(t *testing.T) {
+	stub := &fillsStub{pages: [][]map[string]any{{
+		restFill("ETH", "A", "2010.5", "0.5", "1.0", 1_700_000_002_000, 3),
+		restFill("ETH", "B", "2000.0", "1.0", "0.0", 1_700_000_000_000, 1),
+		restFill("BTC", "B", "42000", "0.1", "0.0", 1_700_000_001_000, 2),
+	}}}
+	svc := newStubService(t, stub)
+
+	var got []*busv1.Signal
+	handler := func(_ context.Context, sig *busv1.Signal) error {
+		got = append(got, sig)
+		return nil
+	}
+
+	inf := &domain.Influencer{Address: testInfluencer}
+	since := time.UnixMilli(1_699_999_999_000)
+	until := time.UnixMilli(1_700_000_010_000)
+	n, err := svc.Backfill(context.Background(), inf, since, until, handler)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if n != 3 {
+		t.Fatalf("Backfill returned %d fills, want 3", n)
+	}
+
+	if len(stub.requests) != 1 {
+		t.Fatalf("stub received %d requests, want 1", len(stub.requests))
+	}
+	req := stub.requests[0]
+	if req["type"] != "userFillsByTime" || req["user"] != testInfluencer {
+		t.Fatalf("unexpected request body: %v", req)
+	}
+	if int64(req["startTime"].(float64)) != since.UnixMilli() || int64(req["endTime"].(float64)) != until.UnixMilli() {
+		t.Fatalf("unexpected time range in request: %v", req)
+	}
+
+	wantTimes := []int64{1_700_000_000_000, 1_700_000_001_000, 1_700_000_002_000}
+	wantMarkets := []string{"ETH-PERP", "BTC-PERP", "ETH-PERP"}
+	wantDeltas := []float64{1.0, 0.1, -0.5}
+	if len(got) != len(wantTimes) {
+		t.Fatalf("handler received %d signals, want %d", len(got), len(wantTimes))
+	}
+	for i, sig := range got {
+		if sig.GetTimestampMs() != wantTimes[i] {
+			t.Errorf("signal %d timestamp = %d, want %d", i, sig.GetTimestampMs(), wantTimes[i])
+		}
+		if sig.GetMarket() != wantMarkets[i] || sig.GetDeltaSize() != wantDeltas[i] {
+			t.Errorf("signal %d = %s/%v, want %s/%v", i, sig.GetMarket(), sig.GetDeltaSize(), wantMarkets[i], wantDeltas[i])
+		}
+		if sig.GetMetadata()["backfilled"] != "true" {
+			t.Errorf("signal %d missing backfilled metadata: %v", i, sig.GetMetadata())
+		}
+	}
+}
+
+func TestBackfillPaginatesFullPages(t *testing.T) {
+	const base = int64(1_700_000_000_000)
+	first := make([]map[string]any, 0, backfillPageSize)
+	for i := 0; i < backfillPageSize; i++ {
+		first = append(first, restFill("ETH", "B", "2000", "0.01", "0.0", base+int64(i), int64(i+1)))
+	}
+	// The next page starts at the last fill's time (inclusive), so the
+	// boundary fill is returned again and must not be replayed twice.
+	second := []map[string]any{
+		first[len(first)-1],
+		restFill("ETH", "B", "2000", "0.01", "0.0", base+backfillPageSize, backfillPageSize+1),
+	}
+	stub := &fillsStub{pages: [][]map[string]any{first, second}}
+	svc := newStubService(t, stub)
+
+	count := 0
+	handler := func(context.Context, *busv1.Signal) error {
+		count++
+		return nil
+	}
+
+	inf := &domain.Influencer{Address: testInfluencer}
+	n, err := svc.Backfill(context.Background(), inf, time.UnixMilli(base), time.UnixMilli(base+10_000), handler)
+	if err != nil {
+		t.Fatalf("Backfill: %v", err)
+	}
+	if n != backfillPageSize+1 || count != backfillPageSize+1 {
+		t.Fatalf("Backfill replayed %d fills (%d signals), want %d", n, count, backfillPageSize+1)
+	}
+	if len(stub.requests) != 2 {
+		t.Fatalf("stub received %d requests, want 2", len(stub.requests))
+	}
+	if got := int64(stub.requests[1]["startTime"].(float64)); got != base+backfillPageSize-1 {
+		t.Fatalf("second page startTime = %d, want %d", got, base+backfillPageSize-1)
+	}
+}
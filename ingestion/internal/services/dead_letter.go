@@ -0,0 +1,52 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+)
+
+// ErrInvalidFill marks fills whose numeric fields cannot be trusted. They are
+// dead-lettered instead of being published as signals.
+var ErrInvalidFill = errors.New("invalid fill")
+
+// ErrInvalidOrderUpdate marks order updates with a missing coin or unparsable
+// price or size. They are dead-lettered instead of being published as intents.
+var ErrInvalidOrderUpdate = errors.New("invalid order update")
+
+// DeadLetterSink receives events ingestion rejected. Implemented by
+// kafka.DeadLetterPublisher.
+type DeadLetterSink interface {
+	Publish(ctx context.Context, dl *busv1.DeadLetter) error
+}
+
+// deadLetter publishes payload with the rejection reason. It returns nil when
+// no sink is configured so the caller can treat the event as handled.
+func (s *HyperliquidService) deadLetter(
+	ctx context.Context,
+	inf *domain.Influencer,
+	eventType busv1.RawEventType,
+	market, sourceEventID string,
+	exchangeTimeMs int64,
+	received time.Time,
+	payload any,
+	stage string,
+	reason error,
+) error {
+	if s.deadLetters == nil {
+		return nil
+	}
+	ev, err := NewRawEvent(inf, eventType, market, sourceEventID, exchangeTimeMs, received, payload)
+	if err != nil {
+		return err
+	}
+	return s.deadLetters.Publish(ctx, &busv1.DeadLetter{
+		Event:        ev,
+		Stage:        stage,
+		Reason:       reason.Error(),
+		RejectedAtMs: time.Now().UTC().UnixMilli(),
+	})
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"math"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+)
+
+// enrichSignal fills the derived and market metadata fields of sig. Signals
+// for markets missing from the registry (HIP-3, newly listed before the next
+// refresh) only get their notional.
+func enrichSignal(sig *busv1.Signal, registry *markets.Registry) {
+	sig.NotionalUsd = math.Abs(sig.GetDeltaSize()) * sig.GetPrice()
+	if registry == nil {
+		return
+	}
+	m, ok := registry.Lookup(sig.GetMarket())
+	if !ok {
+		return
+	}
+	sig.MaxLeverage = int32(m.MaxLeverage)
+	sig.SizeDecimals = int32(m.SzDecimals)
+	sig.OnlyIsolated = m.OnlyIsolated
+}
+
+func marketType(t markets.Type) busv1.MarketType {
+	switch t {
+	case markets.TypePerp:
+		return busv1.MarketType_MARKET_TYPE_PERP
+	case markets.TypeSpot:
+		return busv1.MarketType_MARKET_TYPE_SPOT
+	default:
+		return busv1.MarketType_MARKET_TYPE_UNSPECIFIED
+	}
+}
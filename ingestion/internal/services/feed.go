@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	feedPingInterval = 50 * time.Second
+	feedReadTimeout  = 90 * time.Second
+	feedMaxBackoff   = time.Minute
+)
+
+// Received is a Hyperliquid payload decoded as T together with its JSON as
+// sent by the exchange, which is what the raw event log records.
+type Received[T any] struct {
+	Value T
+	// Raw is nil when the payload did not come from a feed frame (e.g. REST
+	// backfills, whose bytes the exchange client does not expose).
+	Raw json.RawMessage
+}
+
+func (r *Received[T]) UnmarshalJSON(b []byte) error {
+	if err := json.Unmarshal(b, &r.Value); err != nil {
+		return err
+	}
+	r.Raw = slices.Clone(b)
+	return nil
+}
+
+// payload returns the exchange bytes when known and the decoded value
+// otherwise, as accepted by NewRawEvent.
+func (r Received[T]) payload() any {
+	if r.Raw != nil {
+		return r.Raw
+	}
+	return r.Value
+}
+
+type feedFrame struct {
+	Channel string          `json:"channel"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// feedSubscriptions is the set of subscriptions carried by one feed
+// connection. Subscriptions can be added while the feed runs; they are sent on
+// the open connection and every one is sent again after a reconnect.
+type feedSubscriptions struct {
+	mu    sync.Mutex
+	subs  []map[string]string
+	added chan struct{}
+}
+
+func newFeedSubscriptions(subs ...map[string]string) *feedSubscriptions {
+	return &feedSubscriptions{subs: subs, added: make(chan struct{}, 1)}
+}
+
+// add appends sub and wakes the feed writer to send it.
+func (f *feedSubscriptions) add(sub map[string]string) {
+	f.mu.Lock()
+	f.subs = append(f.subs, sub)
+	f.mu.Unlock()
+	select {
+	case f.added <- struct{}{}:
+	default:
+	}
+}
+
+// since returns the subscriptions after the first n.
+func (f *feedSubscriptions) since(n int) []map[string]string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return slices.Clone(f.subs[n:])
+}
+
+// streamFeed carries subs over its own Hyperliquid WebSocket connection and
+// invokes handle with the channel and data of every frame delivered on one of
+// channels until ctx is done, reconnecting with exponential backoff.
+// go-hyperliquid's typed callbacks discard the frame bytes and cannot add
+// subscriptions to a running stream, so feeds that need either are read here.
+func (s *HyperliquidService) streamFeed(ctx context.Context, feed string, subs *feedSubscriptions, channels []string, handle func(channel string, data json.RawMessage)) {
+	backoff := time.Second
+	for {
+		started := time.Now()
+		err := s.readFeed(ctx, feed, subs, channels, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		if time.Since(started) > feedMaxBackoff {
+			backoff = time.Second
+		}
+		s.logger.WarnContext(ctx, "feed stopped, reconnecting", slog.String("feed", feed), slog.Duration("retry_in", backoff), observability.Err(err))
+		streamReconnects.WithLabelValues(feed).Inc()
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, feedMaxBackoff)
+	}
+}
+
+func (s *HyperliquidService) readFeed(ctx context.Context, feed string, subs *feedSubscriptions, channels []string, handle func(string, json.RawMessage)) error {
+	endpoint, err := feedURL(s.wsURL)
+	if err != nil {
+		return err
+	}
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("dial %s: %w", endpoint, err)
+	}
+	defer conn.Close()
+
+	// Unblock the read below once the stream is stopped.
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	writerDone := make(chan struct{})
+	defer close(writerDone)
+	go writeFeed(conn, subs, writerDone)
+
+	for {
+		if err := conn.SetReadDeadline(time.Now().Add(feedReadTimeout)); err != nil {
+			return fmt.Errorf("set read deadline: %w", err)
+		}
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			return fmt.Errorf("read: %w", err)
+		}
+
+		var frame feedFrame
+		if err := json.Unmarshal(msg, &frame); err != nil {
+			s.logger.WarnContext(ctx, "invalid feed frame", slog.String("feed", feed), observability.Err(err))
+			continue
+		}
+		if !slices.Contains(channels, frame.Channel) {
+			continue
+		}
+		handle(frame.Channel, frame.Data)
+	}
+}
+
+// writeFeed is the only writer on conn. It sends every subscription in subs,
+// including those added later, and pings until done is closed. A failed write
+// closes conn, which ends the read loop and reconnects the feed.
+func writeFeed(conn *websocket.Conn, subs *feedSubscriptions, done <-chan struct{}) {
+	ticker := time.NewTicker(feedPingInterval)
+	defer ticker.Stop()
+	sent := 0
+	for {
+		pending := subs.since(sent)
+		for _, sub := range pending {
+			if err := conn.WriteJSON(map[string]any{"method": "subscribe", "subscription": sub}); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+		sent += len(pending)
+
+		select {
+		case <-done:
+			return
+		case <-subs.added:
+		case <-ticker.C:
+			if err := conn.WriteJSON(map[string]string{"method": "ping"}); err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
+// feedURL accepts the same HYPERLIQUID_WS_URL forms as go-hyperliquid (an
+// https API base or a ws(s) endpoint) and returns the WebSocket endpoint.
+func feedURL(base string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", fmt.Errorf("parse websocket url %q: %w", base, err)
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = "/ws"
+	}
+	if !strings.HasPrefix(u.Scheme, "ws") {
+		return "", fmt.Errorf("websocket url %q must use ws, wss, http or https", base)
+	}
+	return u.String(), nil
+}
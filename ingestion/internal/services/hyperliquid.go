@@ -7,28 +7,72 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
 	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
 	hl "github.com/sonirico/go-hyperliquid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// tracer creates the ingestion spans, from fill reception to Kafka publish.
+var tracer = observability.Tracer("github.com/0xRichardL/vibe-copy-trading/ingestion")
+
+// fillRetryMaxBackoff caps the wait between attempts of a failing fill step.
+const fillRetryMaxBackoff = 30 * time.Second
+
 // HyperliquidService abstracts Hyperliquid WebSocket interactions across redundant listeners.
 type HyperliquidService struct {
-	wsURL  string
-	logger *log.Logger
+	wsURL   string
+	info    *hl.Info
+	cursors *store.CursorStore
+	// sequences is nil when signals are published without sequence numbers.
+	sequences *store.SequenceStore
+	// positions is nil when no position store is configured.
+	positions *PositionBook
+	raw       RawEventSink
+	// deadLetters is nil when rejected events are only logged and counted.
+	deadLetters DeadLetterSink
+	markets     *markets.Registry
+	// modes is nil when signals are never halted.
+	modes  *tradingmode.Switch
+	logger *slog.Logger
+
+	backfillLookback  time.Duration
+	aggregationWindow time.Duration
+	reconcileInterval time.Duration
 }
 
-func NewHyperliquidService(cfg config.Config, logger *log.Logger) *HyperliquidService {
+func NewHyperliquidService(cfg config.Config, cursors *store.CursorStore, sequences *store.SequenceStore, positions *PositionBook, raw RawEventSink, deadLetters DeadLetterSink, registry *markets.Registry, modes *tradingmode.Switch, logger *slog.Logger) *HyperliquidService {
+	// Empty metadata skips the asset-mapping bootstrap requests; ingestion only
+	// uses account-level info endpoints.
+	info := hl.NewInfo(context.Background(), cfg.HyperAPIURL, true, &hl.Meta{}, &hl.SpotMeta{}, nil)
 	return &HyperliquidService{
-		wsURL:  cfg.HyperWSURL,
-		logger: logger,
+		wsURL:             cfg.HyperWSURL,
+		info:              info,
+		cursors:           cursors,
+		sequences:         sequences,
+		positions:         positions,
+		raw:               raw,
+		deadLetters:       deadLetters,
+		markets:           registry,
+		modes:             modes,
+		logger:            logger,
+		backfillLookback:  cfg.BackfillMaxLookback,
+		aggregationWindow: cfg.FillAggregationWindow,
+		reconcileInterval: cfg.ReconcileInterval,
 	}
 }
 
@@ -36,152 +80,633 @@ func NewHyperliquidService(cfg config.Config, logger *log.Logger) *HyperliquidSe
 type SignalHandler func(context.Context, *busv1.Signal) error
 
 // SubscribeAccountEvents connects to the Hyperliquid WebSocket and streams
-// account-level events for a single influencer.
+// account-level events for a single influencer. Order updates are only
+// subscribed when orders is non-nil.
 func (s *HyperliquidService) SubscribeAccountEvents(
 	ctx context.Context,
 	inf *domain.Influencer,
 	handler SignalHandler,
+	orders OrderIntentHandler,
 ) error {
 	if handler == nil {
 		return errors.New("SubscribeAccountEvents: handler is required")
 	}
+	ctx = observability.WithLogAttrs(ctx, slog.String(observability.LogKeyInfluencerID, inf.Address))
 
-	ws := hl.NewWebsocketClient(s.wsURL)
-	if err := ws.Connect(ctx); err != nil {
-		return fmt.Errorf("connect websocket: %w", err)
+	// Spot index coins only get their canonical market once metadata is
+	// loaded, so fills are not taken before then.
+	if err := s.markets.Wait(ctx); err != nil {
+		return fmt.Errorf("wait for market metadata: %w", err)
 	}
-	defer func() {
-		if err := ws.Close(); err != nil {
-			s.logger.Printf("error closing websocket for influencer %s: %v", inf.Address, err)
+
+	// Every subscription of the influencer shares one connection. Liquidations
+	// and exchange-initiated cancels are only published on userEvents; its
+	// fills duplicate userFills and are deduplicated by cursor.
+	user := strings.ToLower(inf.Address)
+	subs := newFeedSubscriptions(
+		map[string]string{"type": channelUserFills, "user": user},
+		map[string]string{"type": channelUserEvents, "user": user},
+	)
+	p := &fillPipeline{inf: inf, handler: handler, done: ctx.Done(), leverage: newLeverageTracker(user, subs)}
+	if s.positions != nil {
+		stored, err := s.positions.Load(ctx, inf)
+		if err != nil {
+			return fmt.Errorf("load position book: %w", err)
 		}
-	}()
+		defer s.positions.Drop(inf)
+		if !stored {
+			// First stream for this influencer: positions opened before it was
+			// tracked never produce signals, so take them from the exchange.
+			if err := s.seedPositions(ctx, inf); err != nil {
+				s.logger.ErrorContext(ctx, "seed positions", observability.Err(err))
+			}
+		}
+		if s.reconcileInterval > 0 {
+			reconcileDone := make(chan struct{})
+			go func() {
+				defer close(reconcileDone)
+				s.reconcilePositions(ctx, p)
+			}()
+			defer func() { <-reconcileDone }()
+		}
+	}
 
-	s.logger.Printf("subscribing influencer %s to Hyperliquid user fills", inf.Address)
-	sub, err := ws.OrderFills(
-		hl.OrderFillsSubscriptionParams{User: inf.Address},
-		func(fills hl.WsOrderFills, err error) {
-			if err != nil {
-				s.logger.Printf("order fills callback error for influencer %s: %v", inf.Address, err)
+	if s.aggregationWindow > 0 {
+		// Groups may still be pending when the stream stops; they are flushed
+		// below and must still reach the publisher, so emit outlives ctx. The
+		// flush is deferred after the position book's Drop so it runs first
+		// and applies the final groups to the live book.
+		emitCtx := context.WithoutCancel(ctx)
+		p.agg = NewFillAggregator(s.aggregationWindow, &p.mu, func(fills []hl.WsOrderFill, backfilled bool) {
+			s.publishAggregate(emitCtx, p, fills, backfilled)
+		})
+		defer p.agg.Flush()
+	}
+
+	var updates chan []hl.WsOrder
+	if orders != nil {
+		tracker := newOrderTracker(inf, orders)
+		updates = make(chan []hl.WsOrder, orderUpdatesBuffer)
+		trackerDone := make(chan struct{})
+		go func() {
+			defer close(trackerDone)
+			s.trackOrders(ctx, tracker, updates)
+		}()
+		defer func() { <-trackerDone }()
+		subs.add(map[string]string{"type": channelOrderUpdates, "user": user})
+	}
+
+	s.logger.InfoContext(ctx, "subscribing to Hyperliquid account feed", slog.Bool("order_updates", orders != nil))
+	s.streamAccount(ctx, p, subs, updates)
+	return ctx.Err()
+}
+
+// streamAccount reads the influencer's feed until ctx is done: fills and user
+// events go through the fill pipeline, position and active asset pushes keep
+// p.leverage current and order updates are queued on updates, which is nil
+// when they are not subscribed.
+func (s *HyperliquidService) streamAccount(ctx context.Context, p *fillPipeline, subs *feedSubscriptions, updates chan<- []hl.WsOrder) {
+	// Hyperliquid delivers userEvents data on the "user" channel.
+	channels := []string{channelUserFills, channelUser, channelUserEvents, channelClearinghouseState, channelActiveAssetData}
+	if updates != nil {
+		channels = append(channels, channelOrderUpdates)
+	}
+	s.streamFeed(ctx, "account", subs, channels, func(channel string, data json.RawMessage) {
+		switch channel {
+		case channelUserFills:
+			var frame UserFills
+			if err := json.Unmarshal(data, &frame); err != nil {
+				s.logger.WarnContext(ctx, "invalid user fills payload", observability.Err(err))
+				return
+			}
+			s.handleUserFills(ctx, p, frame)
+		case channelUser, channelUserEvents:
+			var ev UserEvent
+			if err := json.Unmarshal(data, &ev); err != nil {
+				s.logger.WarnContext(ctx, "invalid user event payload", observability.Err(err))
 				return
 			}
-			if len(fills.Fills) == 0 {
+			s.handleUserEvent(ctx, p, ev)
+		case channelOrderUpdates:
+			var batch hl.WsOrders
+			if err := json.Unmarshal(data, &batch); err != nil {
+				s.logger.WarnContext(ctx, "invalid order updates payload", observability.Err(err))
 				return
 			}
+			select {
+			case updates <- batch:
+			case <-ctx.Done():
+			}
+		default:
+			if err := p.leverage.handle(channel, data); err != nil {
+				s.logger.WarnContext(ctx, "invalid leverage payload", slog.String("channel", channel), observability.Err(err))
+			}
+		}
+	})
+}
+
+// handleUserFills publishes a userFills frame. Every (re)subscription starts
+// with a snapshot frame; the gap since the persisted cursor is closed over
+// REST before it.
+func (s *HyperliquidService) handleUserFills(ctx context.Context, p *fillPipeline, frame UserFills) {
+	if len(frame.Fills) == 0 {
+		return
+	}
+	received := time.Now().UTC()
+	if frame.IsSnapshot {
+		if err := s.backfillFromCursor(ctx, p, received); err != nil {
+			s.logger.ErrorContext(ctx, "backfill", observability.Err(err))
+		}
+	}
+	if skipped := s.processFills(ctx, p, frame.Fills, received, false); skipped > 0 {
+		s.logger.InfoContext(ctx, "dropped already published fills", slog.Int("fills", skipped), slog.Bool("snapshot", frame.IsSnapshot))
+	}
+}
+
+// handleUserEvent routes a userEvents frame: fills go through the regular
+// pipeline, liquidation notices and non-user cancels are recorded as raw events.
+// Liquidation signals themselves are derived from the liquidation fills.
+func (s *HyperliquidService) handleUserEvent(ctx context.Context, p *fillPipeline, ev UserEvent) {
+	inf := p.inf
+	received := time.Now().UTC()
+
+	if len(ev.Fills) > 0 {
+		s.processFills(ctx, p, ev.Fills, received, false)
+	}
+	if ev.Liquidation != nil && strings.EqualFold(ev.Liquidation.Value.LiquidatedUser, inf.Address) {
+		liq := ev.Liquidation.Value
+		s.logger.WarnContext(ctx, "influencer liquidated", slog.Int64("lid", liq.Lid), slog.String("notional", liq.LiquidatedNtlPos), slog.String("account_value", liq.LiquidatedAccountValue))
+		sourceID := fmt.Sprintf("lid:%d", liq.Lid)
+		if err := s.recordRaw(ctx, inf, busv1.RawEventType_RAW_EVENT_TYPE_LIQUIDATION, "", sourceID, received.UnixMilli(), received, ev.Liquidation.payload()); err != nil {
+			s.logger.ErrorContext(ctx, "record raw liquidation", observability.Err(err))
+		}
+	}
+	for _, rc := range ev.NonUserCancel {
+		c := rc.Value
+		s.logger.InfoContext(ctx, "order cancelled by exchange", slog.Int64("oid", c.Oid), slog.String("coin", c.Coin))
+		sourceID := fmt.Sprintf("oid:%d", c.Oid)
+		if err := s.recordRaw(ctx, inf, busv1.RawEventType_RAW_EVENT_TYPE_NON_USER_CANCEL, s.markets.Normalize(c.Coin).Market, sourceID, received.UnixMilli(), received, rc.payload()); err != nil {
+			s.logger.ErrorContext(ctx, "record raw non-user cancel", observability.Err(err))
+		}
+	}
+}
+
+// fillPipeline carries the per-stream state used to turn an influencer's
+// fills into published signals.
+type fillPipeline struct {
+	// mu serializes processFills across the userFills and userEvents feeds so
+	// a fill delivered by both is published once, and with the aggregator's
+	// window timer so aggregated groups are published in order.
+	mu sync.Mutex
+
+	inf     *domain.Influencer
+	handler SignalHandler
+	// done is closed when the stream stops; failed steps are retried until
+	// then (see retry).
+	done <-chan struct{}
+	// agg is nil when partial fill aggregation is disabled.
+	agg *FillAggregator
+	// leverage is nil when signals are not stamped with leverage (REST-only
+	// backfills outside a stream).
+	leverage *leverageTracker
+}
+
+// processFills records fills not yet published in order, then normalizes and
+// publishes them and advances the cursor after each successful publish. A
+// fill still failing when the stream stops ends the batch, so no later fill
+// moves the cursor past it. It returns the number of fills dropped as already
+// published.
+func (s *HyperliquidService) processFills(
+	ctx context.Context,
+	p *fillPipeline,
+	fills []Received[hl.WsOrderFill],
+	received time.Time,
+	backfilled bool,
+) int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
 
-			received := time.Now().UTC()
-			for _, f := range fills.Fills {
-				sig, err := NormalizeEventToSignal(inf, f, received)
-				if err != nil {
-					s.logger.Printf("normalize event error for influencer %s: %v", inf.Address, err)
-					continue
-				}
-				if sig == nil {
-					continue
-				}
-				if err := handler(ctx, sig); err != nil && !errors.Is(err, context.Canceled) {
-					s.logger.Printf("handler error for influencer %s: %v", inf.Address, err)
-				}
+	skipped := 0
+	for _, f := range fills {
+		if ctx.Err() != nil {
+			break
+		}
+		if !s.processFill(ctx, p, f, received, backfilled) {
+			skipped++
+		}
+	}
+	return skipped
+}
+
+// processFill handles one fill of processFills under a reception span that
+// parents the normalization and publish spans. It returns false when the fill
+// was already published.
+func (s *HyperliquidService) processFill(ctx context.Context, p *fillPipeline, rf Received[hl.WsOrderFill], received time.Time, backfilled bool) bool {
+	inf := p.inf
+	f := rf.Value
+	ctx, span := tracer.Start(ctx, "hyperliquid.fill.receive",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithTimestamp(received),
+		trace.WithAttributes(
+			attribute.String("influencer.address", inf.Address),
+			attribute.String("hyperliquid.coin", f.Coin),
+			attribute.Int64("hyperliquid.tid", f.Tid),
+			attribute.Bool("backfilled", backfilled),
+		))
+	defer span.End()
+	fillsReceived.WithLabelValues(fillSource(backfilled)).Inc()
+	if !backfilled {
+		observability.ObserveSinceMillis(stageLatency.WithLabelValues(stageReceived), f.Time, received)
+	}
+
+	// With aggregation on, a fill delivered by both userFills and userEvents
+	// may still be buffered here before the cursor covers it.
+	if (p.agg != nil && p.agg.Pending(f.Oid, f.Tid)) || s.alreadyPublished(ctx, inf, f) {
+		span.SetAttributes(attribute.Bool("duplicate", true))
+		fillsRejected.WithLabelValues(rejectDuplicate).Inc()
+		return false
+	}
+	sym, err := s.markets.Resolve(f.Coin)
+	if err != nil {
+		// Publishing under the structural fallback would put the market's
+		// signals, positions and sequences under a second name.
+		fillsRejected.WithLabelValues(rejectUnknownMarket).Inc()
+		s.rejectFill(ctx, p, rf, sym, received, err)
+		return true
+	}
+	// The exchange payload must be durable before anything is derived from
+	// it; later fills wait while the append is retried.
+	err = s.retry(ctx, p, stepRawEvent, func() error {
+		return s.recordRaw(ctx, inf, busv1.RawEventType_RAW_EVENT_TYPE_FILL, sym.Market, fillSourceID(f, sym.Market, received), fillTimestamp(f, received), received, rf.payload())
+	})
+	if err != nil {
+		return true
+	}
+	_, normSpan := tracer.Start(ctx, "signal.normalize", trace.WithAttributes(attribute.String("market", sym.Market)))
+	sig, err := NormalizeEventToSignal(inf, f, sym, received)
+	observability.EndSpan(normSpan, err)
+	if err != nil {
+		fillsRejected.WithLabelValues(rejectInvalid).Inc()
+		s.rejectFill(ctx, p, rf, sym, received, err)
+		return true
+	}
+	if sig == nil {
+		return true
+	}
+	if !backfilled {
+		observability.ObserveSinceMillis(stageLatency.WithLabelValues(stageNormalized), sig.GetTimestampMs(), time.Now())
+	}
+	if p.agg != nil {
+		p.agg.Add(f, backfilled)
+		return true
+	}
+	_ = s.publishFill(ctx, p, []hl.WsOrderFill{f}, sig, backfilled)
+	return true
+}
+
+// rejectFill dead-letters a fill that failed validation and advances the
+// cursor past it once the dead letter is durable, so backfills do not reject
+// it again. The position book is left as is; reconciliation corrects it.
+func (s *HyperliquidService) rejectFill(ctx context.Context, p *fillPipeline, rf Received[hl.WsOrderFill], sym markets.Symbol, received time.Time, reason error) {
+	fill := rf.Value
+	s.logger.WarnContext(ctx, "rejecting fill", slog.Int64("tid", fill.Tid), observability.Err(reason))
+	sourceID := fmt.Sprintf("tid:%d", fill.Tid)
+	err := s.retry(ctx, p, stepDeadLetter, func() error {
+		return s.deadLetter(ctx, p.inf, busv1.RawEventType_RAW_EVENT_TYPE_FILL, sym.Market, sourceID, fill.Time, received, rf.payload(), "normalize", reason)
+	})
+	if err != nil {
+		return
+	}
+	s.advanceCursor(ctx, p.inf, fill)
+}
+
+// publishAggregate merges a group of partial fills of one order into a single
+// signal. If the group cannot be merged its fills are published individually,
+// in order.
+func (s *HyperliquidService) publishAggregate(ctx context.Context, p *fillPipeline, fills []hl.WsOrderFill, backfilled bool) {
+	merged, tids, err := MergeFills(fills, fillMarket(s.markets, fills[0].Coin))
+	var sig *busv1.Signal
+	if err == nil {
+		sig, err = NormalizeEventToSignal(p.inf, merged, s.markets.Normalize(merged.Coin), time.Now().UTC())
+	}
+	if err != nil {
+		s.logger.WarnContext(ctx, "aggregate fills failed, publishing individually", slog.Int("fills", len(fills)), observability.Err(err))
+		for _, f := range fills {
+			sig, err := NormalizeEventToSignal(p.inf, f, s.markets.Normalize(f.Coin), time.Now().UTC())
+			if err != nil {
+				// Each fill passed normalization before it was buffered and its
+				// raw event is recorded, so a retry cannot succeed; skip it.
+				fillsRejected.WithLabelValues(rejectNormalizeError).Inc()
+				s.logger.ErrorContext(ctx, "normalize fill", slog.Int64("tid", f.Tid), observability.Err(err))
+				s.advanceCursor(ctx, p.inf, f)
+				continue
 			}
-		},
-	)
+			if err := s.publishFill(ctx, p, []hl.WsOrderFill{f}, sig, backfilled); err != nil {
+				return
+			}
+		}
+		return
+	}
+	if len(fills) > 1 {
+		sig.Metadata["aggregated_fills"] = strconv.Itoa(len(fills))
+	}
+	sig.Metadata["source_tids"] = joinTids(tids)
+	_ = s.publishFill(ctx, p, fills, sig, backfilled)
+}
+
+// publishFill publishes sig, derived from the source fills, and then advances
+// the cursor to them. If the publish still fails when the stream stops, the
+// cursor stays before them and the error is returned.
+func (s *HyperliquidService) publishFill(ctx context.Context, p *fillPipeline, fills []hl.WsOrderFill, sig *busv1.Signal, backfilled bool) error {
+	if backfilled {
+		sig.Metadata["backfilled"] = "true"
+	}
+	if err := s.publish(ctx, p, fills[0].Coin, sig); err != nil {
+		return err
+	}
+	s.advanceCursor(ctx, p.inf, fills...)
+	return nil
+}
+
+// publish enriches and sequences sig, hands it to the stream handler and
+// applies it to the position book. A failing handler is retried with the same
+// sequence number until the stream stops, then its error is returned.
+// When the trading mode of the influencer or market is halted, sig only moves
+// the position book and is dropped before it takes a sequence number, so the
+// matcher sees no gap and the fill is not replayed once trading resumes.
+// Callers hold p.mu, so sequence numbers follow publish order.
+func (s *HyperliquidService) publish(ctx context.Context, p *fillPipeline, coin string, sig *busv1.Signal) (err error) {
+	ctx, span := tracer.Start(ctx, "signal.publish", trace.WithAttributes(
+		attribute.String("signal.id", sig.GetSignalId()),
+		attribute.String("market", sig.GetMarket()),
+		attribute.String("signal.action", sig.GetAction().String()),
+	))
+	defer func() {
+		span.SetAttributes(attribute.Int64("signal.sequence", sig.GetSequence()))
+		observability.EndSpan(span, err)
+	}()
+
+	ctx = observability.WithLogAttrs(ctx, slog.String(observability.LogKeySignalID, sig.GetSignalId()))
+	enrichSignal(sig, s.markets)
+	stampLeverage(p.leverage, coin, sig)
+	if s.halted(sig) {
+		fillsRejected.WithLabelValues(rejectTradingHalted).Inc()
+		s.logger.InfoContext(ctx, "signal dropped", slog.String("reason", rejectTradingHalted))
+		s.applyPosition(ctx, p, sig)
+		return nil
+	}
+	if s.sequences != nil {
+		// A Redis outage publishes unsequenced signals rather than none.
+		seq, err := s.sequences.Next(ctx, p.inf.Address, sig.GetMarket())
+		if err != nil {
+			s.logger.ErrorContext(ctx, "assign sequence", slog.String("market", sig.GetMarket()), observability.Err(err))
+		}
+		sig.Sequence = seq
+	}
+	if err := s.retry(ctx, p, stepPublish, func() error { return p.handler(ctx, sig) }); err != nil {
+		return err
+	}
+	s.applyPosition(ctx, p, sig)
+	return nil
+}
+
+// retry runs step until it succeeds, backing off exponentially between
+// attempts. Callers hold p.mu, so a fill that cannot be recorded or published
+// holds back every later fill of the stream instead of being overtaken by
+// them. Once the stream stops it gives up and returns the last error.
+func (s *HyperliquidService) retry(ctx context.Context, p *fillPipeline, step string, op func() error) error {
+	backoff := time.Second
+	for {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-p.done:
+			s.logger.WarnContext(ctx, "stream stopped before fill step succeeded", slog.String("step", step), observability.Err(err))
+			return err
+		default:
+		}
+		fillRetries.WithLabelValues(step).Inc()
+		s.logger.ErrorContext(ctx, "fill step failed, retrying", slog.String("step", step), slog.Duration("retry_in", backoff), observability.Err(err))
+		select {
+		case <-p.done:
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, fillRetryMaxBackoff)
+	}
+}
+
+// halted reports whether the trading kill switch halts the influencer or
+// market of sig.
+func (s *HyperliquidService) halted(sig *busv1.Signal) bool {
+	if s.modes == nil {
+		return false
+	}
+	return s.modes.Resolve(tradingmode.Target{Influencer: sig.GetInfluencerId(), Market: sig.GetMarket()}) == tradingmode.Halted
+}
+
+func (s *HyperliquidService) applyPosition(ctx context.Context, p *fillPipeline, sig *busv1.Signal) {
+	if s.positions == nil {
+		return
+	}
+	if _, err := s.positions.Apply(ctx, p.inf, sig); err != nil {
+		s.logger.ErrorContext(ctx, "update position book", slog.String("market", sig.GetMarket()), observability.Err(err))
+	}
+}
+
+// recordRaw appends an untouched Hyperliquid payload to the raw event sink.
+func (s *HyperliquidService) recordRaw(
+	ctx context.Context,
+	inf *domain.Influencer,
+	eventType busv1.RawEventType,
+	market, sourceEventID string,
+	exchangeTimeMs int64,
+	received time.Time,
+	payload any,
+) error {
+	if s.raw == nil {
+		return nil
+	}
+	ev, err := NewRawEvent(inf, eventType, market, sourceEventID, exchangeTimeMs, received, payload)
 	if err != nil {
-		return fmt.Errorf("subscribe to order fills: %w", err)
+		return err
 	}
-	defer sub.Close()
+	return s.raw.Append(ctx, ev)
+}
 
-	<-ctx.Done()
-	return ctx.Err()
+// alreadyPublished reports whether the fill is covered by the persisted cursor
+// for the influencer+market. Cursor lookup failures let the fill through so a
+// Redis outage degrades to downstream dedup instead of dropping signals.
+func (s *HyperliquidService) alreadyPublished(ctx context.Context, inf *domain.Influencer, fill hl.WsOrderFill) bool {
+	if s.cursors == nil {
+		return false
+	}
+	market := cursorMarket(fill)
+	cur, ok, err := s.cursors.Get(ctx, inf.Address, market)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "load cursor", slog.String("market", market), observability.Err(err))
+		return false
+	}
+	return ok && cur.Covers(fill.Time, fill.Tid)
+}
+
+// advanceCursor moves the cursor to fills, which share a coin.
+func (s *HyperliquidService) advanceCursor(ctx context.Context, inf *domain.Influencer, fills ...hl.WsOrderFill) {
+	if s.cursors == nil || len(fills) == 0 {
+		return
+	}
+	market := cursorMarket(fills[0])
+	cursors := make([]domain.Cursor, len(fills))
+	for i, f := range fills {
+		cursors[i] = fillCursor(f)
+	}
+	if _, err := s.cursors.Advance(ctx, inf.Address, market, cursors...); err != nil {
+		s.logger.ErrorContext(ctx, "advance cursor", slog.String("market", market), observability.Err(err))
+	}
+}
+
+func fillCursor(fill hl.WsOrderFill) domain.Cursor {
+	return domain.Cursor{TimeMs: fill.Time, Tid: fill.Tid}
 }
 
-// NormalizeEventToSignal converts a single Hyperliquid WsOrderFill into a Signal.
-func NormalizeEventToSignal(inf *domain.Influencer, fill hl.WsOrderFill, receivedAt time.Time) (*busv1.Signal, error) {
+// cursorMarket is the cursor field of fill: the upper-cased exchange coin
+// rather than the canonical market, so cursors do not depend on spot metadata
+// being loaded.
+func cursorMarket(fill hl.WsOrderFill) string {
+	return strings.ToUpper(fill.Coin)
+}
+
+// NormalizeEventToSignal converts a single Hyperliquid WsOrderFill into a
+// Signal on the canonical market sym (see markets.Registry.Normalize). Fills
+// with a missing coin, an unparsable or non-finite number, or a non-positive
+// price or size fail with ErrInvalidFill.
+func NormalizeEventToSignal(inf *domain.Influencer, fill hl.WsOrderFill, sym markets.Symbol, receivedAt time.Time) (*busv1.Signal, error) {
 	if fill.Coin == "" {
-		return nil, fmt.Errorf("missing market (coin) in fill for influencer %s", inf.Address)
+		return nil, fmt.Errorf("%w: missing market (coin) for influencer %s", ErrInvalidFill, inf.Address)
 	}
 
-	market := strings.ToUpper(fill.Coin)
-	price, _ := numbers.ExtractFloat(fill.Px)
-	startPosition, _ := numbers.ExtractFloat(fill.StartPosition)
-	size, _ := numbers.ExtractFloat(fill.Sz)
-	timestamp := fill.Time
-	if timestamp == 0 && !receivedAt.IsZero() {
-		timestamp = receivedAt.UnixMilli()
+	market := sym.Market
+	price, err := numbers.ExtractDecimal(fill.Px)
+	if err != nil || price.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: px %q for influencer %s", ErrInvalidFill, fill.Px, inf.Address)
+	}
+	size, err := numbers.ExtractDecimal(fill.Sz)
+	if err != nil || size.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: sz %q for influencer %s", ErrInvalidFill, fill.Sz, inf.Address)
+	}
+	startPosition, err := numbers.ExtractDecimal(fill.StartPosition)
+	if err != nil {
+		return nil, fmt.Errorf("%w: startPosition %q for influencer %s", ErrInvalidFill, fill.StartPosition, inf.Address)
 	}
+	timestamp := fillTimestamp(fill, receivedAt)
 
 	sideToken := strings.ToUpper(strings.TrimSpace(fill.Side))
 	isBuy := sideToken == "B" || sideToken == "BUY"
-	var newPosition float64
-	if isBuy {
-		newPosition = startPosition + size
-	} else {
-		newPosition = startPosition - size
-	}
-
-	prevSideStr := positionSideFromSize(startPosition)
-	sideStr := positionSideFromSize(newPosition)
-	positionSize := math.Abs(newPosition)
-	deltaSize := newPosition - startPosition
-	action := deriveSignalAction(prevSideStr, sideStr, positionSize, deltaSize)
-	sideEnum := normalizeSignalSide(sideStr)
-
-	sourceID := ""
-	if fill.Hash != "" {
-		sourceID = fill.Hash
-	} else if fill.Tid != 0 {
-		sourceID = fmt.Sprintf("tid:%d", fill.Tid)
-	} else if fill.Oid != 0 {
-		sourceID = fmt.Sprintf("oid:%d", fill.Oid)
+	// Decimal arithmetic keeps a fully closed position at exactly zero.
+	deltaSize := size
+	if !isBuy {
+		deltaSize = size.Neg()
 	}
-	if sourceID == "" {
-		sourceID = fmt.Sprintf("fill:%s:%d", market, timestamp)
+	newPosition := startPosition.Add(deltaSize)
+
+	sideStr := positionSideFromSize(newPosition.Float64())
+	positionSize := newPosition.Abs()
+	action := deriveSignalAction(startPosition.Float64(), newPosition.Float64())
+	liquidated := isLiquidationOf(inf, fill)
+	if liquidated {
+		action = busv1.SignalAction_SIGNAL_ACTION_LIQUIDATED
 	}
+	sideEnum := normalizeSignalSide(sideStr)
+
+	sourceID := fillSourceID(fill, market, receivedAt)
 	signalID := buildSignalID(inf.Address, market, sourceID)
 
 	metadata := map[string]string{
 		"event_type":      "fill",
 		"source_event_id": sourceID,
+		"coin":            fill.Coin,
 	}
 	if inf.Address != "" {
 		metadata["influencer_address"] = inf.Address
 	}
-	if rawJSON, err := json.Marshal(fill); err == nil {
-		metadata["raw"] = string(rawJSON)
+	if liquidated {
+		metadata["event_type"] = "liquidation"
+		metadata["liquidation_method"] = fill.Liquidation.Method
+		metadata["liquidation_mark_px"] = fill.Liquidation.MarkPx
 	}
 
 	return &busv1.Signal{
-		SignalId:      signalID,
-		InfluencerId:  inf.Address,
-		Exchange:      "hyperliquid",
-		Market:        market,
-		Action:        action,
-		Side:          sideEnum,
-		Size:          positionSize,
-		DeltaSize:     deltaSize,
-		Price:         price,
-		TimestampMs:   timestamp,
-		SourceEventId: sourceID,
-		Metadata:      metadata,
+		SignalId:         signalID,
+		InfluencerId:     inf.Address,
+		Exchange:         "hyperliquid",
+		Market:           market,
+		MarketType:       marketType(sym.Type),
+		Action:           action,
+		Side:             sideEnum,
+		Size:             positionSize.Float64(),
+		DeltaSize:        deltaSize.Float64(),
+		Price:            price.Float64(),
+		SizeDecimal:      positionSize.String(),
+		DeltaSizeDecimal: deltaSize.String(),
+		PriceDecimal:     price.String(),
+		TimestampMs:      timestamp,
+		SourceEventId:    sourceID,
+		Metadata:         metadata,
 	}, nil
 }
 
-func deriveSignalAction(prevSide, side string, positionSize, deltaSize float64) busv1.SignalAction {
+// fillTimestamp is the exchange time of fill, or receivedAt when missing.
+func fillTimestamp(fill hl.WsOrderFill, receivedAt time.Time) int64 {
+	if fill.Time == 0 && !receivedAt.IsZero() {
+		return receivedAt.UnixMilli()
+	}
+	return fill.Time
+}
+
+// fillSourceID identifies fill for signal and raw event IDs: its hash, else
+// its tid or oid.
+func fillSourceID(fill hl.WsOrderFill, market string, receivedAt time.Time) string {
+	switch {
+	case fill.Hash != "":
+		return fill.Hash
+	case fill.Tid != 0:
+		return fmt.Sprintf("tid:%d", fill.Tid)
+	case fill.Oid != 0:
+		return fmt.Sprintf("oid:%d", fill.Oid)
+	default:
+		return fmt.Sprintf("fill:%s:%d", market, fillTimestamp(fill, receivedAt))
+	}
+}
+
+// isLiquidationOf reports whether fill closed part of inf's position through a
+// liquidation. Fills where inf is the liquidator are ordinary trades.
+func isLiquidationOf(inf *domain.Influencer, fill hl.WsOrderFill) bool {
+	if fill.Liquidation == nil {
+		return false
+	}
+	user := fill.Liquidation.LiquidatedUser
+	return user == nil || strings.EqualFold(*user, inf.Address)
+}
+
+// deriveSignalAction classifies the move from the signed position prev to the
+// signed position next. Leaving a flat position is OPEN and reaching flat from
+// either side is CLOSE; staying flat is unspecified.
+func deriveSignalAction(prev, next float64) busv1.SignalAction {
 	switch {
-	case prevSide != "" && side != "" && !strings.EqualFold(prevSide, side):
+	case prev == 0 && next == 0:
+		return busv1.SignalAction_SIGNAL_ACTION_UNSPECIFIED
+	case prev != 0 && next != 0 && (prev > 0) != (next > 0):
 		return busv1.SignalAction_SIGNAL_ACTION_FLIP
-	case positionSize == 0 && deltaSize < 0:
+	case next == 0 && prev != 0:
 		return busv1.SignalAction_SIGNAL_ACTION_CLOSE
-	case deltaSize > 0:
-		if positionSize == deltaSize || prevSide == "" {
-			return busv1.SignalAction_SIGNAL_ACTION_OPEN
-		}
+	case prev == 0:
+		return busv1.SignalAction_SIGNAL_ACTION_OPEN
+	case math.Abs(next) > math.Abs(prev):
 		return busv1.SignalAction_SIGNAL_ACTION_INCREASE
-	case deltaSize < 0:
-		if positionSize == 0 {
-			return busv1.SignalAction_SIGNAL_ACTION_CLOSE
-		}
-		return busv1.SignalAction_SIGNAL_ACTION_DECREASE
 	default:
-		return busv1.SignalAction_SIGNAL_ACTION_OPEN
+		return busv1.SignalAction_SIGNAL_ACTION_DECREASE
 	}
 }
 
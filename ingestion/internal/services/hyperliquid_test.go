@@ -0,0 +1,297 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// newRedisService returns a stub service with its cursors, sequences,
+// positions and trading modes kept in miniredis.
+func newRedisService(t *testing.T) (*miniredis.Miniredis, *HyperliquidService) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	logger := slog.New(slog.DiscardHandler)
+
+	settings, err := libconfig.NewReloader[config.Runtime](libconfig.NewRedisSource(client, "settings"), time.Minute, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := newStubService(t, http.NotFoundHandler())
+	svc.cursors = store.NewCursorStore(client, "cursors")
+	svc.sequences = store.NewSequenceStore(client, "sequences")
+	svc.positions = NewPositionBook(store.NewPositionStore(client, "positions"), nil, settings)
+	svc.modes = tradingmode.NewSwitch(client, "trading:modes", time.Minute, logger)
+	return mr, svc
+}
+
+// A fill while trading is halted is dropped without taking a sequence number
+// but still moves the cursor and the position book, so a newer fill after
+// trading resumes is the first sequenced signal and nothing replays the
+// halted one behind it.
+func TestPublishDropsHaltedFills(t *testing.T) {
+	ctx := context.Background()
+	mr, svc := newRedisService(t)
+
+	var published []*busv1.Signal
+	inf := &domain.Influencer{Address: testInfluencer}
+	p := &fillPipeline{
+		inf: inf,
+		handler: func(_ context.Context, sig *busv1.Signal) error {
+			published = append(published, sig)
+			return nil
+		},
+	}
+
+	mr.HSet("trading:modes", "influencer:"+testInfluencer, "halted")
+	if err := svc.modes.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	halted := wsFill("ETH", 1, 10, 1000, "2000", "1", "0")
+	svc.publishAggregate(ctx, p, []hl.WsOrderFill{halted}, false)
+	if len(published) != 0 {
+		t.Fatalf("published %d halted signals", len(published))
+	}
+	if !svc.alreadyPublished(ctx, inf, halted) {
+		t.Fatal("cursor did not advance past the halted fill")
+	}
+
+	mr.HDel("trading:modes", "influencer:"+testInfluencer)
+	if err := svc.modes.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	newer := wsFill("ETH", 2, 11, 2000, "2100", "1", "1")
+	svc.publishAggregate(ctx, p, []hl.WsOrderFill{newer}, false)
+	if len(published) != 1 || published[0].GetSequence() != 1 {
+		t.Fatalf("published %v, want the newer fill with the first sequence number", published)
+	}
+	// A reconnect backfill skips the halted fill instead of replaying it.
+	if !svc.alreadyPublished(ctx, inf, halted) {
+		t.Fatal("halted fill would be replayed")
+	}
+	if pos := svc.positions.Get(inf, published[0].GetMarket()); pos.Size != 2 {
+		t.Fatalf("position = %+v, want both fills applied", pos)
+	}
+}
+
+// A fill whose publish fails is retried before the next fill is processed, so
+// signals stay in order and the retried one keeps its sequence number. If the
+// stream stops while it still fails, neither it nor any later fill moves the
+// cursor, so the next backfill replays them in order.
+func TestPublishRetriesFailedFillsInOrder(t *testing.T) {
+	_, svc := newRedisService(t)
+	if err := svc.modes.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		published []string
+		attempts  int
+		failing   = 1
+	)
+	inf := &domain.Influencer{Address: testInfluencer}
+	p := &fillPipeline{
+		inf:  inf,
+		done: ctx.Done(),
+		handler: func(_ context.Context, sig *busv1.Signal) error {
+			attempts++
+			if failing > 0 {
+				failing--
+				return errors.New("outbox unavailable")
+			}
+			published = append(published, fmt.Sprintf("%s#%d", sig.GetPriceDecimal(), sig.GetSequence()))
+			return nil
+		},
+	}
+	received := func(fills ...hl.WsOrderFill) []Received[hl.WsOrderFill] {
+		out := make([]Received[hl.WsOrderFill], len(fills))
+		for i, f := range fills {
+			out[i] = Received[hl.WsOrderFill]{Value: f}
+		}
+		return out
+	}
+
+	first := wsFill("ETH", 1, 10, 1000, "2000", "1", "0")
+	second := wsFill("ETH", 2, 11, 2000, "2100", "1", "1")
+	svc.processFills(ctx, p, received(first, second), time.Now(), false)
+	if want := []string{"2000#1", "2100#2"}; !slices.Equal(published, want) {
+		t.Fatalf("published %v, want %v", published, want)
+	}
+	if attempts != 3 {
+		t.Fatalf("handler called %d times, want the first fill retried once", attempts)
+	}
+
+	// The stream stops during the first attempt of the third fill.
+	attempts = 0
+	p.handler = func(context.Context, *busv1.Signal) error {
+		attempts++
+		cancel()
+		return context.Canceled
+	}
+	third := wsFill("ETH", 3, 12, 3000, "2200", "1", "2")
+	fourth := wsFill("ETH", 4, 13, 4000, "2300", "1", "3")
+	svc.processFills(ctx, p, received(third, fourth), time.Now(), false)
+	if attempts != 1 {
+		t.Fatalf("handler called %d times, want the fourth fill held back", attempts)
+	}
+	for _, f := range []hl.WsOrderFill{third, fourth} {
+		if svc.alreadyPublished(context.Background(), inf, f) {
+			t.Fatalf("cursor moved past unpublished fill %d", f.Tid)
+		}
+	}
+	if !svc.alreadyPublished(context.Background(), inf, second) {
+		t.Fatal("cursor does not cover the published fills")
+	}
+}
+
+// Stopping a stream flushes its pending fill group while the influencer's
+// position book is still loaded, so the group updates the stored position
+// from the book and the book is released afterwards.
+func TestStopFlushesPendingGroupBeforeDroppingPositions(t *testing.T) {
+	_, svc := newRedisService(t)
+	if err := svc.modes.Reload(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	inf := &domain.Influencer{Address: testInfluencer}
+	market := svc.markets.Normalize("ETH").Market
+	if err := svc.positions.store.Set(context.Background(), inf.Address, domain.Position{Market: market, Size: 1, EntryPx: 1000}); err != nil {
+		t.Fatal(err)
+	}
+	fs := &feedServer{
+		frames: map[string]string{
+			"userFills": `{"channel":"userFills","data":{"isSnapshot":true,"user":"` + testInfluencer + `","fills":[` +
+				`{"coin":"ETH","px":"2000","sz":"1","side":"B","time":1000,"startPosition":"1","oid":7,"tid":70,"fee":"0","closedPnl":"0"}]}}`,
+		},
+		subscribed: make(chan map[string]string, 16),
+	}
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+	svc.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+	svc.aggregationWindow = time.Hour
+	raw := &rawSink{}
+	svc.raw = raw
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var published []*busv1.Signal
+	handler := func(_ context.Context, sig *busv1.Signal) error {
+		published = append(published, sig)
+		return nil
+	}
+	done := make(chan error, 1)
+	go func() { done <- svc.SubscribeAccountEvents(ctx, inf, handler, nil) }()
+
+	// The snapshot fill is recorded and buffered in its group, which stays
+	// pending until the stream stops.
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		raw.mu.Lock()
+		recorded := len(raw.events)
+		raw.mu.Unlock()
+		if recorded > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("snapshot fill was not received")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("SubscribeAccountEvents = %v", err)
+	}
+
+	if len(published) != 1 {
+		t.Fatalf("published %d signals, want the flushed group", len(published))
+	}
+	stored, err := svc.positions.store.All(context.Background(), inf.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos := stored[market]; pos.Size != 2 || pos.EntryPx != 1500 {
+		t.Fatalf("stored position = %+v, want size 2 at the averaged entry 1500", pos)
+	}
+	if len(svc.positions.books) != 0 {
+		t.Fatalf("position books %v left behind after the stream stopped", svc.positions.books)
+	}
+}
+
+// An influencer stream carries every subscription on one connection, and only
+// subscribes to order updates when they are handled.
+func TestStreamSharesOneConnection(t *testing.T) {
+	user := strings.ToLower(testInfluencer)
+	tests := []struct {
+		name   string
+		orders OrderIntentHandler
+		want   []string
+	}{
+		{
+			name: "without order updates",
+			want: []string{channelUserFills, channelUserEvents, channelClearinghouseState},
+		},
+		{
+			name:   "with order updates",
+			orders: func(context.Context, *busv1.OrderIntent) error { return nil },
+			want:   []string{channelUserFills, channelUserEvents, channelClearinghouseState, channelOrderUpdates},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := &feedServer{subscribed: make(chan map[string]string, 16)}
+			srv := httptest.NewServer(fs)
+			t.Cleanup(srv.Close)
+			svc := newStubService(t, http.NotFoundHandler())
+			svc.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+			ctx, cancel := context.WithCancel(context.Background())
+			done := make(chan error, 1)
+			go func() {
+				done <- svc.SubscribeAccountEvents(ctx, &domain.Influencer{Address: testInfluencer}, func(context.Context, *busv1.Signal) error { return nil }, tt.orders)
+			}()
+			var got []string
+			for range tt.want {
+				sub := fs.nextSubscription(t)
+				if sub["user"] != user {
+					t.Errorf("subscription %v, want user %s", sub, user)
+				}
+				got = append(got, sub["type"])
+			}
+			cancel()
+			if err := <-done; !errors.Is(err, context.Canceled) {
+				t.Fatalf("SubscribeAccountEvents = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("subscriptions = %v, want %v", got, tt.want)
+			}
+			select {
+			case sub := <-fs.subscribed:
+				t.Fatalf("unexpected subscription %v", sub)
+			default:
+			}
+			if n := fs.connections.Load(); n != 1 {
+				t.Fatalf("opened %d connections, want 1", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// Channels of the leverage feed.
+const (
+	channelClearinghouseState = "clearinghouseState"
+	channelActiveAssetData    = "activeAssetData"
+)
+
+// assetLeverage is an influencer's leverage setting on one market.
+type assetLeverage struct {
+	value int
+	mode  busv1.MarginMode
+}
+
+// leverageTracker caches an influencer's per-market leverage and margin mode
+// from the account feed: clearinghouseState pushes cover markets with an open
+// position and a per-market activeAssetData subscription, added to the feed
+// the first time a market is seen, follows changes made while flat.
+type leverageTracker struct {
+	user string
+	subs *feedSubscriptions
+
+	mu      sync.RWMutex
+	byCoin  map[string]assetLeverage
+	watched map[string]bool
+}
+
+// newLeverageTracker subscribes subs, the feed of user's account, to
+// clearinghouseState. Feed failures reconnect and leave the cached values in
+// place.
+func newLeverageTracker(user string, subs *feedSubscriptions) *leverageTracker {
+	user = strings.ToLower(user)
+	subs.add(map[string]string{"type": channelClearinghouseState, "user": user})
+	return &leverageTracker{
+		user:    user,
+		subs:    subs,
+		byCoin:  make(map[string]assetLeverage),
+		watched: make(map[string]bool),
+	}
+}
+
+// handle applies one clearinghouseState or activeAssetData frame.
+func (t *leverageTracker) handle(channel string, data json.RawMessage) error {
+	switch channel {
+	case channelClearinghouseState:
+		var msg hl.ClearinghouseStateMessage
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		t.update(msg.ClearinghouseState)
+	case channelActiveAssetData:
+		var msg hl.UserActiveAssetData
+		if err := json.Unmarshal(data, &msg); err != nil {
+			return err
+		}
+		t.set(msg.Coin, leverageFrom(msg.Leverage))
+	}
+	return nil
+}
+
+// update records the leverage of every open position in state and watches
+// those markets.
+func (t *leverageTracker) update(state hl.ClearinghouseState) {
+	for _, ap := range state.AssetPositions {
+		t.set(ap.Position.Coin, leverageFrom(ap.Position.Leverage))
+		t.watch(ap.Position.Coin)
+	}
+}
+
+// watch subscribes to activeAssetData for coin unless already subscribed.
+func (t *leverageTracker) watch(coin string) {
+	key := strings.ToUpper(coin)
+	t.mu.Lock()
+	seen := t.watched[key]
+	t.watched[key] = true
+	t.mu.Unlock()
+	if !seen {
+		t.subs.add(map[string]string{"type": channelActiveAssetData, "user": t.user, "coin": coin})
+	}
+}
+
+func (t *leverageTracker) get(coin string) (assetLeverage, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	lev, ok := t.byCoin[strings.ToUpper(coin)]
+	return lev, ok
+}
+
+func (t *leverageTracker) set(coin string, lev assetLeverage) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.byCoin[strings.ToUpper(coin)] = lev
+}
+
+// stampLeverage sets the influencer's leverage and margin mode on sig from the
+// cache and watches the market for later signals. It never blocks on the
+// exchange: a market seen for the first time leaves the fields unset, and so
+// do spot markets, which have no leverage, and backfilled signals, whose
+// leverage at the time of the fill is not known.
+func stampLeverage(t *leverageTracker, coin string, sig *busv1.Signal) {
+	if t == nil || sig.GetMarketType() == busv1.MarketType_MARKET_TYPE_SPOT {
+		return
+	}
+	t.watch(coin)
+	if sig.GetMetadata()["backfilled"] == "true" {
+		return
+	}
+	lev, ok := t.get(coin)
+	if !ok {
+		return
+	}
+	sig.Leverage = float64(lev.value)
+	sig.MarginMode = lev.mode
+}
+
+func leverageFrom(l hl.Leverage) assetLeverage {
+	mode := busv1.MarginMode_MARGIN_MODE_UNSPECIFIED
+	switch strings.ToLower(l.Type) {
+	case "cross":
+		mode = busv1.MarginMode_MARGIN_MODE_CROSS
+	case "isolated":
+		mode = busv1.MarginMode_MARGIN_MODE_ISOLATED
+	}
+	return assetLeverage{value: l.Value, mode: mode}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/gorilla/websocket"
+)
+
+// feedServer is a WebSocket endpoint that reports every subscribe request and
+// answers it with the frame registered for its type, if any.
+type feedServer struct {
+	frames      map[string]string
+	subscribed  chan map[string]string
+	connections atomic.Int32
+}
+
+func (f *feedServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	conn, err := (&websocket.Upgrader{}).Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	f.connections.Add(1)
+	for {
+		var req struct {
+			Method       string            `json:"method"`
+			Subscription map[string]string `json:"subscription"`
+		}
+		if err := conn.ReadJSON(&req); err != nil {
+			return
+		}
+		if req.Method != "subscribe" {
+			continue
+		}
+		f.subscribed <- req.Subscription
+		if frame, ok := f.frames[req.Subscription["type"]]; ok {
+			if err := conn.WriteMessage(websocket.TextMessage, []byte(frame)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (f *feedServer) nextSubscription(t *testing.T) map[string]string {
+	t.Helper()
+	select {
+	case sub := <-f.subscribed:
+		return sub
+	case <-time.After(2 * time.Second):
+		t.Fatal("no subscribe request")
+		return nil
+	}
+}
+
+func TestLeverageTrackerFollowsFeed(t *testing.T) {
+	fs := &feedServer{
+		frames: map[string]string{
+			"clearinghouseState": `{"channel":"clearinghouseState","data":{"user":"0xabc","clearinghouseState":{"assetPositions":[{"type":"oneWay","position":{"coin":"BTC","szi":"0.1","leverage":{"type":"isolated","value":3}}}]}}}`,
+			"activeAssetData":    `{"channel":"activeAssetData","data":{"user":"0xabc","coin":"ETH","leverage":{"type":"cross","value":7}}}`,
+		},
+		subscribed: make(chan map[string]string, 8),
+	}
+	srv := httptest.NewServer(fs)
+	t.Cleanup(srv.Close)
+	svc := newStubService(t, http.NotFoundHandler())
+	svc.wsURL = "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	subs := newFeedSubscriptions()
+	tracker := newLeverageTracker("0xABC", subs)
+	p := &fillPipeline{inf: &domain.Influencer{Address: "0xABC"}, leverage: tracker}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		svc.streamAccount(ctx, p, subs, nil)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	if sub := fs.nextSubscription(t); sub["type"] != "clearinghouseState" || sub["user"] != "0xabc" {
+		t.Fatalf("first subscription = %v, want clearinghouseState for the lower-cased user", sub)
+	}
+	// The open BTC position is watched from the pushed state.
+	if sub := fs.nextSubscription(t); sub["type"] != "activeAssetData" || sub["coin"] != "BTC" {
+		t.Fatalf("subscription = %v, want activeAssetData for BTC", sub)
+	}
+
+	// A market seen for the first time is not stamped, but watched.
+	sig := &busv1.Signal{MarketType: busv1.MarketType_MARKET_TYPE_PERP}
+	stampLeverage(tracker, "ETH", sig)
+	if sig.GetLeverage() != 0 {
+		t.Fatalf("unknown market stamped with leverage %v", sig.GetLeverage())
+	}
+	if sub := fs.nextSubscription(t); sub["type"] != "activeAssetData" || sub["coin"] != "ETH" || sub["user"] != "0xabc" {
+		t.Fatalf("subscription = %v, want activeAssetData for ETH", sub)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, ok := tracker.get("ETH"); ok {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("activeAssetData push was not applied")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	tests := []struct {
+		name         string
+		coin         string
+		sig          *busv1.Signal
+		wantLeverage float64
+		wantMode     busv1.MarginMode
+	}{
+		{
+			name:         "leverage from activeAssetData",
+			coin:         "ETH",
+			sig:          &busv1.Signal{MarketType: busv1.MarketType_MARKET_TYPE_PERP},
+			wantLeverage: 7,
+			wantMode:     busv1.MarginMode_MARGIN_MODE_CROSS,
+		},
+		{
+			name:         "leverage from clearinghouseState",
+			coin:         "BTC",
+			sig:          &busv1.Signal{MarketType: busv1.MarketType_MARKET_TYPE_PERP},
+			wantLeverage: 3,
+			wantMode:     busv1.MarginMode_MARGIN_MODE_ISOLATED,
+		},
+		{
+			name: "backfilled signals are not stamped",
+			coin: "ETH",
+			sig: &busv1.Signal{
+				MarketType: busv1.MarketType_MARKET_TYPE_PERP,
+				Metadata:   map[string]string{"backfilled": "true"},
+			},
+		},
+		{
+			name: "spot signals are not stamped",
+			coin: "ETH",
+			sig:  &busv1.Signal{MarketType: busv1.MarketType_MARKET_TYPE_SPOT},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stampLeverage(tracker, tt.coin, tt.sig)
+			if tt.sig.GetLeverage() != tt.wantLeverage || tt.sig.GetMarginMode() != tt.wantMode {
+				t.Fatalf("leverage/mode = %v/%v, want %v/%v", tt.sig.GetLeverage(), tt.sig.GetMarginMode(), tt.wantLeverage, tt.wantMode)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Ingestion metrics, served on GET /metrics.
+var (
+	activeStreams = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "ingestion_active_streams",
+		Help: "Influencer streams currently running on this instance.",
+	})
+	streamReconnects = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingestion_stream_reconnects_total",
+		Help: "Hyperliquid feed reconnects, by feed.",
+	}, []string{"feed"})
+	fillsReceived = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingestion_fills_received_total",
+		Help: "Fills received from Hyperliquid, by source (stream or backfill).",
+	}, []string{"source"})
+	fillsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingestion_fills_rejected_total",
+		Help: "Fills not turned into published signals, by reason.",
+	}, []string{"reason"})
+	fillRetries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingestion_fill_retries_total",
+		Help: "Failed attempts to record, dead-letter or publish a fill that were retried before later fills, by step.",
+	}, []string{"step"})
+	signalsPublished = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "ingestion_signals_published_total",
+		Help: "Signals acknowledged by Kafka, by action.",
+	}, []string{"action"})
+	publishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ingestion_kafka_publish_duration_seconds",
+		Help:    "Duration of Kafka writes, by topic.",
+		Buckets: observability.DurationBuckets,
+	}, []string{"topic"})
+	outboxPublishFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingestion_outbox_publish_failures_total",
+		Help: "Failed attempts to flush a signal outbox batch to Kafka.",
+	})
+	outboxDeadLetters = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "ingestion_outbox_dead_letters_total",
+		Help: "Corrupt signal outbox records skipped and moved to the dead-letter directory.",
+	})
+	stageLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ingestion_stage_latency_seconds",
+		Help:    "Time from the exchange event to each ingestion stage (received, normalized, appended, published).",
+		Buckets: observability.LatencyBuckets,
+	}, []string{"stage"})
+)
+
+// Fill rejection reasons.
+const (
+	rejectDuplicate      = "duplicate"
+	rejectInvalid        = "invalid"
+	rejectNormalizeError = "normalize_error"
+	rejectTradingHalted  = "trading_halted"
+	rejectUnknownMarket  = "unknown_market"
+)
+
+// Fill steps retried by HyperliquidService.retry.
+const (
+	stepRawEvent   = "raw_event"
+	stepDeadLetter = "dead_letter"
+	stepPublish    = "publish"
+)
+
+// Ingestion stages measured by stageLatency.
+const (
+	stageReceived   = "received"
+	stageNormalized = "normalized"
+	stageAppended   = "appended"
+	stagePublished  = "published"
+)
+
+func fillSource(backfilled bool) string {
+	if backfilled {
+		return "backfill"
+	}
+	return "stream"
+}
@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// OrderIntentHandler processes order intents prior to downstream distribution.
+type OrderIntentHandler func(context.Context, *busv1.OrderIntent) error
+
+const (
+	channelOrderUpdates = "orderUpdates"
+	// orderUpdatesBuffer is how many orderUpdates frames may wait for the
+	// tracker before the websocket callback blocks.
+	orderUpdatesBuffer = 256
+	// orderDetailsTimeout bounds the frontendOpenOrders request made for new
+	// or modified orders.
+	orderDetailsTimeout = 5 * time.Second
+)
+
+// orderTracker remembers an influencer's open orders so updates can be
+// classified as placed or modified, and enriches them with trigger, reduce-only
+// and TP/SL details that the orderUpdates feed does not carry.
+type orderTracker struct {
+	inf     *domain.Influencer
+	handler OrderIntentHandler
+
+	mu   sync.Mutex
+	open map[int64]openOrder
+}
+
+type openOrder struct {
+	basic   hl.WsBasicOrder
+	details *hl.FrontendOpenOrder
+}
+
+func newOrderTracker(inf *domain.Influencer, handler OrderIntentHandler) *orderTracker {
+	return &orderTracker{inf: inf, handler: handler, open: make(map[int64]openOrder)}
+}
+
+// trackOrders publishes the order updates queued on updates until ctx is done.
+// It runs apart from the websocket callback so the frontendOpenOrders request
+// for new orders does not stall the feed.
+func (s *HyperliquidService) trackOrders(ctx context.Context, t *orderTracker, updates <-chan []hl.WsOrder) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case orders := <-updates:
+			s.handleOrderUpdates(ctx, t, orders)
+		}
+	}
+}
+
+// handleOrderUpdates publishes one OrderIntent per order update. Updates that
+// fail normalization are dead-lettered.
+func (s *HyperliquidService) handleOrderUpdates(ctx context.Context, t *orderTracker, orders []hl.WsOrder) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	received := time.Now().UTC()
+	var details map[int64]hl.FrontendOpenOrder
+	for _, o := range orders {
+		status := orderIntentStatus(o.Status)
+		prev, known := t.open[o.Order.Oid]
+
+		var det *hl.FrontendOpenOrder
+		switch status {
+		case busv1.OrderIntentStatus_ORDER_INTENT_STATUS_PLACED:
+			if known {
+				if prev.basic.LimitPx == o.Order.LimitPx && prev.basic.Sz == o.Order.Sz {
+					// Re-announced without a change (e.g. after reconnect).
+					continue
+				}
+				status = busv1.OrderIntentStatus_ORDER_INTENT_STATUS_MODIFIED
+			}
+			if details == nil {
+				details = s.frontendOpenOrders(ctx, t.inf)
+			}
+			if d, ok := details[o.Order.Oid]; ok {
+				det = &d
+			} else if known {
+				det = prev.details
+			}
+			t.open[o.Order.Oid] = openOrder{basic: o.Order, details: det}
+		default:
+			if known {
+				det = prev.details
+			}
+			delete(t.open, o.Order.Oid)
+		}
+
+		sym, err := s.markets.Resolve(o.Order.Coin)
+		var intent *busv1.OrderIntent
+		if err == nil {
+			intent, err = NormalizeOrderUpdate(t.inf, o, sym, status, det, received)
+		}
+		if err != nil {
+			s.logger.WarnContext(ctx, "rejecting order update", slog.Int64("oid", o.Order.Oid), observability.Err(err))
+			if err := s.deadLetter(ctx, t.inf, busv1.RawEventType_RAW_EVENT_TYPE_ORDER_UPDATE, sym.Market, orderUpdateSourceID(o, received), orderUpdateTimestamp(o, received), received, o, "normalize", err); err != nil {
+				s.logger.ErrorContext(ctx, "dead-letter order update", slog.Int64("oid", o.Order.Oid), observability.Err(err))
+			}
+			continue
+		}
+		if err := s.recordRaw(ctx, t.inf, busv1.RawEventType_RAW_EVENT_TYPE_ORDER_UPDATE, intent.GetMarket(), intent.GetIntentId(), intent.GetTimestampMs(), received, o); err != nil {
+			s.logger.ErrorContext(ctx, "record raw order update", observability.Err(err))
+			continue
+		}
+		if err := t.handler(ctx, intent); err != nil {
+			s.logger.ErrorContext(ctx, "order intent handler error", observability.Err(err))
+		}
+	}
+}
+
+// frontendOpenOrders returns the influencer's open orders keyed by oid. Errors
+// are logged and yield no details; intents are still published without them.
+func (s *HyperliquidService) frontendOpenOrders(ctx context.Context, inf *domain.Influencer) map[int64]hl.FrontendOpenOrder {
+	ctx, cancel := context.WithTimeout(ctx, orderDetailsTimeout)
+	defer cancel()
+	orders, err := s.info.FrontendOpenOrders(ctx, inf.Address)
+	if err != nil {
+		s.logger.WarnContext(ctx, "frontendOpenOrders request failed", observability.Err(err))
+		return map[int64]hl.FrontendOpenOrder{}
+	}
+	byOid := make(map[int64]hl.FrontendOpenOrder, len(orders))
+	for _, o := range orders {
+		byOid[o.Oid] = o
+	}
+	return byOid
+}
+
+// NormalizeOrderUpdate converts a Hyperliquid order update into an OrderIntent
+// on the canonical market sym. details, when available, supplies trigger,
+// reduce-only and TP/SL attributes. Updates with a missing coin or an
+// unparsable or non-finite price or size fail with ErrInvalidOrderUpdate.
+func NormalizeOrderUpdate(
+	inf *domain.Influencer,
+	o hl.WsOrder,
+	sym markets.Symbol,
+	status busv1.OrderIntentStatus,
+	details *hl.FrontendOpenOrder,
+	receivedAt time.Time,
+) (*busv1.OrderIntent, error) {
+	if o.Order.Coin == "" {
+		return nil, fmt.Errorf("%w: missing market (coin) in order %d for influencer %s", ErrInvalidOrderUpdate, o.Order.Oid, inf.Address)
+	}
+
+	market := sym.Market
+	limitPx, err := numbers.ExtractFiniteFloat(o.Order.LimitPx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: limitPx %q in order %d: %v", ErrInvalidOrderUpdate, o.Order.LimitPx, o.Order.Oid, err)
+	}
+	size, err := numbers.ExtractFiniteFloat(o.Order.Sz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sz %q in order %d: %v", ErrInvalidOrderUpdate, o.Order.Sz, o.Order.Oid, err)
+	}
+	origSize, err := numbers.ExtractFiniteFloat(o.Order.OrigSz)
+	if err != nil {
+		return nil, fmt.Errorf("%w: origSz %q in order %d: %v", ErrInvalidOrderUpdate, o.Order.OrigSz, o.Order.Oid, err)
+	}
+	timestamp := orderUpdateTimestamp(o, receivedAt)
+
+	side := busv1.OrderIntentSide_ORDER_INTENT_SIDE_UNSPECIFIED
+	switch strings.ToUpper(strings.TrimSpace(o.Order.Side)) {
+	case "B", "BUY":
+		side = busv1.OrderIntentSide_ORDER_INTENT_SIDE_BUY
+	case "A", "S", "SELL":
+		side = busv1.OrderIntentSide_ORDER_INTENT_SIDE_SELL
+	}
+
+	sourceID := orderUpdateSourceID(o, receivedAt)
+	intent := &busv1.OrderIntent{
+		IntentId:       buildSignalID(inf.Address, market, sourceID),
+		InfluencerId:   inf.Address,
+		Exchange:       "hyperliquid",
+		Market:         market,
+		MarketType:     marketType(sym.Type),
+		Status:         status,
+		Side:           side,
+		OrderId:        o.Order.Oid,
+		LimitPrice:     limitPx,
+		Size:           size,
+		OriginalSize:   origSize,
+		ExchangeStatus: string(o.Status),
+		TimestampMs:    timestamp,
+		Metadata: map[string]string{
+			"event_type":         "order_update",
+			"influencer_address": inf.Address,
+			"coin":               o.Order.Coin,
+		},
+	}
+	if o.Order.Cloid != nil {
+		intent.ClientOrderId = *o.Order.Cloid
+	}
+	if details != nil {
+		intent.OrderType = details.OrderType
+		intent.IsTrigger = details.IsTrigger
+		intent.TriggerPrice = details.TriggerPx
+		intent.TriggerCondition = details.TriggerCondition
+		intent.ReduceOnly = details.ReduceOnly
+		intent.IsPositionTpsl = details.IsPositionTpSl
+		intent.IsTakeProfit = strings.HasPrefix(details.OrderType, "Take Profit")
+		intent.IsStopLoss = strings.HasPrefix(details.OrderType, "Stop")
+	}
+	return intent, nil
+}
+
+// orderUpdateTimestamp is the status time of o, or receivedAt when missing.
+func orderUpdateTimestamp(o hl.WsOrder, receivedAt time.Time) int64 {
+	if o.StatusTimestamp == 0 {
+		return receivedAt.UnixMilli()
+	}
+	return o.StatusTimestamp
+}
+
+// orderUpdateSourceID identifies one status transition of an order.
+func orderUpdateSourceID(o hl.WsOrder, receivedAt time.Time) string {
+	return fmt.Sprintf("oid:%d:%s:%d", o.Order.Oid, o.Status, orderUpdateTimestamp(o, receivedAt))
+}
+
+// orderIntentStatus maps Hyperliquid order statuses onto OrderIntent
+// transitions. Every "...Canceled" variant is a cancel and every
+// "...Rejected" variant a rejection.
+func orderIntentStatus(status hl.OrderStatusValue) busv1.OrderIntentStatus {
+	s := string(status)
+	switch {
+	case status == hl.OrderStatusValueOpen:
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_PLACED
+	case status == hl.OrderStatusValueFilled:
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_FILLED
+	case status == hl.OrderStatusValueTriggered:
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_TRIGGERED
+	case status == hl.OrderStatusValueRejected || strings.HasSuffix(s, "Rejected"):
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_REJECTED
+	case strings.HasSuffix(strings.ToLower(s), "canceled") || status == hl.OrderStatusValueScheduledCancel:
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_CANCELLED
+	default:
+		return busv1.OrderIntentStatus_ORDER_INTENT_STATUS_UNSPECIFIED
+	}
+}
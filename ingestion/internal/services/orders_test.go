@@ -0,0 +1,48 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+func TestNormalizeOrderUpdateRejectsInvalidNumbers(t *testing.T) {
+	valid := hl.WsBasicOrder{Coin: "ETH", Side: "B", LimitPx: "2000.5", Sz: "1.5", OrigSz: "2", Oid: 7}
+	tests := []struct {
+		name    string
+		mutate  func(*hl.WsBasicOrder)
+		wantErr bool
+	}{
+		{name: "valid", mutate: func(*hl.WsBasicOrder) {}},
+		{name: "missing coin", mutate: func(o *hl.WsBasicOrder) { o.Coin = "" }, wantErr: true},
+		{name: "unparsable limitPx", mutate: func(o *hl.WsBasicOrder) { o.LimitPx = "abc" }, wantErr: true},
+		{name: "empty sz", mutate: func(o *hl.WsBasicOrder) { o.Sz = "" }, wantErr: true},
+		{name: "non-finite origSz", mutate: func(o *hl.WsBasicOrder) { o.OrigSz = "NaN" }, wantErr: true},
+	}
+	inf := &domain.Influencer{Address: testInfluencer}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			basic := valid
+			tt.mutate(&basic)
+			o := hl.WsOrder{Order: basic, Status: hl.OrderStatusValueOpen, StatusTimestamp: 1000}
+			intent, err := NormalizeOrderUpdate(inf, o, markets.Symbol{Market: "ETH-PERP"}, busv1.OrderIntentStatus_ORDER_INTENT_STATUS_PLACED, nil, time.UnixMilli(2000))
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidOrderUpdate) {
+					t.Fatalf("err = %v, want ErrInvalidOrderUpdate", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NormalizeOrderUpdate: %v", err)
+			}
+			if intent.GetLimitPrice() != 2000.5 || intent.GetSize() != 1.5 || intent.GetOriginalSize() != 2 {
+				t.Fatalf("intent numbers = %v/%v/%v", intent.GetLimitPrice(), intent.GetSize(), intent.GetOriginalSize())
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/kafka"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	outboxBatchSize    = 100
+	outboxPollInterval = time.Second
+	outboxMaxBackoff   = 30 * time.Second
+)
+
+// OutboxFlusher drains the signal outbox to Kafka in append order. A batch is
+// acknowledged (and its segments truncated) only after Kafka accepted it, and
+// failed batches are retried with exponential backoff, so signals survive a
+// broker outage or a restart. Delivery is at least once: a crash between
+// publish and acknowledgement republishes the batch.
+type OutboxFlusher struct {
+	outbox    *store.SignalOutbox
+	publisher *kafka.SignalPublisher
+	settings  *libconfig.Reloader[config.Runtime]
+	logger    *slog.Logger
+}
+
+// NewOutboxFlusher builds an OutboxFlusher. Batches are published within the
+// outbox_publish_timeout runtime setting.
+func NewOutboxFlusher(outbox *store.SignalOutbox, publisher *kafka.SignalPublisher, settings *libconfig.Reloader[config.Runtime], logger *slog.Logger) *OutboxFlusher {
+	return &OutboxFlusher{outbox: outbox, publisher: publisher, settings: settings, logger: logger}
+}
+
+// Run flushes until ctx is done. Pending signals stay on disk for the next run.
+func (f *OutboxFlusher) Run(ctx context.Context) error {
+	backoff := time.Second
+	for {
+		flushed, err := f.flush(ctx)
+		if err != nil {
+			outboxPublishFailures.Inc()
+			f.logger.ErrorContext(ctx, "flush signal outbox", slog.Duration("retry_in", backoff), observability.Err(err))
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, outboxMaxBackoff)
+			continue
+		}
+		backoff = time.Second
+		if flushed {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-f.outbox.Notify():
+		case <-time.After(outboxPollInterval):
+		}
+	}
+}
+
+// flush publishes and acknowledges one batch. It reports whether a batch was
+// found. Each signal gets a producer span continuing the trace it was
+// appended in. Corrupt records the outbox dead-lettered are acknowledged with
+// the batch.
+func (f *OutboxFlusher) flush(ctx context.Context) (bool, error) {
+	batch, err := f.outbox.Pending(outboxBatchSize)
+	if err != nil {
+		return false, err
+	}
+	recs := batch.Records
+	if len(recs) == 0 && len(batch.DeadLetters) == 0 {
+		return false, nil
+	}
+	if len(recs) == 0 {
+		return true, f.ack(ctx, batch)
+	}
+
+	msgs := make([]messaging.Traced[*busv1.Signal], len(recs))
+	spans := make([]trace.Span, len(recs))
+	for i, rec := range recs {
+		parent := ctx
+		if rec.Trace.IsValid() {
+			parent = trace.ContextWithRemoteSpanContext(ctx, rec.Trace)
+		}
+		spanCtx, span := tracer.Start(parent, f.publisher.Topic+" publish",
+			trace.WithSpanKind(trace.SpanKindProducer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", f.publisher.Topic),
+				attribute.String("signal.id", rec.Signal.GetSignalId()),
+			))
+		msgs[i] = messaging.Traced[*busv1.Signal]{Message: rec.Signal, Trace: trace.SpanContextFromContext(spanCtx)}
+		spans[i] = span
+	}
+
+	ctxPub, cancel := context.WithTimeout(ctx, f.settings.Current().OutboxPublishTimeout)
+	defer cancel()
+	started := time.Now()
+	err = f.publisher.PublishBatch(ctxPub, msgs)
+	publishDuration.WithLabelValues(f.publisher.Topic).Observe(time.Since(started).Seconds())
+	for _, span := range spans {
+		observability.EndSpan(span, err)
+	}
+	if err != nil {
+		return false, err
+	}
+	now := time.Now()
+	for _, rec := range recs {
+		signalsPublished.WithLabelValues(observability.EnumLabel(rec.Signal.GetAction().String(), "SIGNAL_ACTION_")).Inc()
+		if rec.Signal.GetMetadata()["backfilled"] != "true" {
+			observability.ObserveSinceMillis(stageLatency.WithLabelValues(stagePublished), rec.Signal.GetTimestampMs(), now)
+		}
+	}
+	if err := f.ack(ctx, batch); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (f *OutboxFlusher) ack(ctx context.Context, batch store.OutboxBatch) error {
+	if err := f.outbox.Ack(batch.Next); err != nil {
+		return err
+	}
+	for _, dl := range batch.DeadLetters {
+		outboxDeadLetters.Inc()
+		f.logger.ErrorContext(ctx, "signal outbox record dead-lettered",
+			slog.Int("segment", dl.Position.Segment),
+			slog.Int64("offset", dl.Position.Offset),
+			slog.Int64("bytes", dl.Bytes),
+			slog.String("path", dl.Path),
+			slog.String("reason", dl.Reason),
+		)
+	}
+	return nil
+}
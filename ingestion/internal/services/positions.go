@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+)
+
+// PositionBook is the authoritative view of influencer positions built from
+// published signals. It is held in memory for the influencers streamed by this
+// instance and written through to Redis so it survives restarts and handoffs.
+type PositionBook struct {
+	store    *store.PositionStore
+	handler  PositionHandler
+	settings *libconfig.Reloader[config.Runtime]
+
+	mu    sync.RWMutex
+	books map[string]map[string]domain.Position
+}
+
+// PositionHandler receives the position snapshot after every change.
+type PositionHandler func(context.Context, *busv1.InfluencerPosition) error
+
+// NewPositionBook builds a book persisted to store. handler may be nil;
+// settings supplies the timeout of each handler call.
+func NewPositionBook(store *store.PositionStore, handler PositionHandler, settings *libconfig.Reloader[config.Runtime]) *PositionBook {
+	return &PositionBook{store: store, handler: handler, settings: settings, books: make(map[string]map[string]domain.Position)}
+}
+
+// Load seeds the in-memory book of inf from Redis and reports whether anything
+// was stored for it.
+func (b *PositionBook) Load(ctx context.Context, inf *domain.Influencer) (bool, error) {
+	stored, err := b.store.All(ctx, inf.Address)
+	if err != nil {
+		return false, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.books[strings.ToLower(inf.Address)] = stored
+	return len(stored) > 0, nil
+}
+
+// Drop releases the in-memory book of inf once its stream stops.
+func (b *PositionBook) Drop(inf *domain.Influencer) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.books, strings.ToLower(inf.Address))
+}
+
+// Get returns the position of inf on market; flat when unknown.
+func (b *PositionBook) Get(inf *domain.Influencer, market string) domain.Position {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if pos, ok := b.books[strings.ToLower(inf.Address)][market]; ok {
+		return pos
+	}
+	return domain.Position{Market: market}
+}
+
+// Positions returns the open positions of inf ordered by market.
+func (b *PositionBook) Positions(inf *domain.Influencer) []domain.Position {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	book := b.books[strings.ToLower(inf.Address)]
+	out := make([]domain.Position, 0, len(book))
+	for _, pos := range book {
+		out = append(out, pos)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Market < out[j].Market })
+	return out
+}
+
+// Apply moves the position of inf to the state described by sig and returns
+// it. The entry price is the size-weighted average while the position grows,
+// unchanged while it shrinks and reset when it opens or flips.
+func (b *PositionBook) Apply(ctx context.Context, inf *domain.Influencer, sig *busv1.Signal) (domain.Position, error) {
+	prev := b.Get(inf, sig.GetMarket())
+	next := domain.Position{
+		Market:    sig.GetMarket(),
+		Size:      signedSize(sig),
+		UpdatedMs: sig.GetTimestampMs(),
+	}
+	switch {
+	case next.Size == 0:
+	case prev.Size == 0 || (prev.Size > 0) != (next.Size > 0):
+		next.EntryPx = sig.GetPrice()
+	case math.Abs(next.Size) > math.Abs(prev.Size):
+		added := math.Abs(next.Size) - math.Abs(prev.Size)
+		next.EntryPx = (math.Abs(prev.Size)*prev.EntryPx + added*sig.GetPrice()) / math.Abs(next.Size)
+	default:
+		next.EntryPx = prev.EntryPx
+	}
+	return next, b.Set(ctx, inf, next)
+}
+
+// Set overwrites the position of inf on pos.Market and hands the resulting
+// snapshot to the handler, bounded by the publish_timeout runtime setting so a
+// Kafka outage does not stall the stream. A snapshot that fails to publish is
+// superseded by the next one for the market on the compacted topic.
+func (b *PositionBook) Set(ctx context.Context, inf *domain.Influencer, pos domain.Position) error {
+	b.mu.Lock()
+	addr := strings.ToLower(inf.Address)
+	book, ok := b.books[addr]
+	if !ok {
+		book = make(map[string]domain.Position)
+		b.books[addr] = book
+	}
+	if pos.Size == 0 {
+		delete(book, pos.Market)
+	} else {
+		book[pos.Market] = pos
+	}
+	b.mu.Unlock()
+
+	if err := b.store.Set(ctx, inf.Address, pos); err != nil {
+		return err
+	}
+	if b.handler == nil {
+		return nil
+	}
+	ctxPub, cancel := context.WithTimeout(ctx, b.settings.Current().PublishTimeout)
+	defer cancel()
+	if err := b.handler(ctxPub, NewPositionSnapshot(inf, pos)); err != nil {
+		return fmt.Errorf("publish position: %w", err)
+	}
+	return nil
+}
+
+// NewPositionSnapshot converts a book position into its bus representation.
+// Flat positions are kept as FLAT snapshots so compacted consumers see closes.
+func NewPositionSnapshot(inf *domain.Influencer, pos domain.Position) *busv1.InfluencerPosition {
+	return &busv1.InfluencerPosition{
+		InfluencerId: inf.Address,
+		Exchange:     "hyperliquid",
+		Market:       pos.Market,
+		Side:         normalizeSignalSide(positionSideFromSize(pos.Size)),
+		Size:         math.Abs(pos.Size),
+		EntryPrice:   pos.EntryPx,
+		UpdatedMs:    pos.UpdatedMs,
+	}
+}
+
+func signedSize(sig *busv1.Signal) float64 {
+	if sig.GetSide() == busv1.SignalSide_SIGNAL_SIDE_SHORT {
+		return -sig.GetSize()
+	}
+	if sig.GetSide() == busv1.SignalSide_SIGNAL_SIDE_LONG {
+		return sig.GetSize()
+	}
+	return 0
+}
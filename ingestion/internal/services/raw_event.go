@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+)
+
+// RawEventSink durably records untouched Hyperliquid payloads before they are
+// normalized and published. Implementations: kafka.RawEventPublisher and
+// store.RawEventFileStore.
+type RawEventSink interface {
+	Append(ctx context.Context, ev *busv1.RawEvent) error
+	Close() error
+}
+
+// NewRawEvent wraps a Hyperliquid payload in a RawEvent envelope. A
+// json.RawMessage payload holds the exchange's bytes and is stored verbatim;
+// anything else is JSON encoded as decoded by the exchange client.
+func NewRawEvent(
+	inf *domain.Influencer,
+	eventType busv1.RawEventType,
+	market, sourceEventID string,
+	exchangeTimeMs int64,
+	ingestedAt time.Time,
+	payload any,
+) (*busv1.RawEvent, error) {
+	raw, ok := payload.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("marshal raw payload: %w", err)
+		}
+	}
+	return &busv1.RawEvent{
+		EventId:             buildSignalID(inf.Address, eventType.String(), sourceEventID),
+		InfluencerAddress:   inf.Address,
+		Exchange:            "hyperliquid",
+		Market:              market,
+		EventType:           eventType,
+		SourceEventId:       sourceEventID,
+		ExchangeTimestampMs: exchangeTimeMs,
+		IngestedAtMs:        ingestedAt.UnixMilli(),
+		Payload:             raw,
+	}, nil
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// reconcilePositions periodically compares the position book of p.inf with a
+// clearinghouseState snapshot until ctx is done.
+func (s *HyperliquidService) reconcilePositions(ctx context.Context, p *fillPipeline) {
+	ticker := time.NewTicker(s.reconcileInterval)
+	defer ticker.Stop()
+	// Drift must be seen on two consecutive snapshots before it is corrected,
+	// so fills still in flight when a snapshot is taken do not trigger it.
+	pending := make(map[string]float64)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if err := s.reconcile(ctx, p, pending); err != nil && ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "reconcile positions", observability.Err(err))
+		}
+	}
+}
+
+func (s *HyperliquidService) reconcile(ctx context.Context, p *fillPipeline, pending map[string]float64) error {
+	inf := p.inf
+	requested := time.Now().UTC()
+	state, err := s.info.UserState(ctx, inf.Address)
+	if err != nil {
+		return fmt.Errorf("clearinghouseState: %w", err)
+	}
+
+	snapshot := make(map[string]domain.Position, len(state.AssetPositions))
+	raw := make(map[string]hl.AssetPosition, len(state.AssetPositions))
+	markPx := make(map[string]float64, len(state.AssetPositions))
+	for _, ap := range state.AssetPositions {
+		pos, err := s.positionFromSnapshot(ap.Position, requested)
+		if err != nil {
+			return fmt.Errorf("position %s: %w", ap.Position.Coin, err)
+		}
+		snapshot[pos.Market] = pos
+		raw[pos.Market] = ap
+		markPx[pos.Market] = snapshotMarkPx(ap.Position)
+	}
+	// Positions closed on the exchange have no snapshot entry to price them
+	// from; take their mid prices before fill processing is held off.
+	if slices.ContainsFunc(s.positions.Positions(inf), func(pos domain.Position) bool {
+		_, ok := snapshot[pos.Market]
+		return !ok && reconcilable(pos.Market)
+	}) {
+		mids, err := s.info.AllMids(ctx)
+		if err != nil {
+			s.logger.WarnContext(ctx, "allMids request failed", observability.Err(err))
+		}
+		for coin, mid := range mids {
+			if px, err := numbers.ExtractFloat(mid); err == nil && px > 0 {
+				markPx[coin+"-PERP"] = px
+			}
+		}
+	}
+
+	// Hold off fill processing so the book cannot move while it is compared.
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	markets := make(map[string]struct{}, len(snapshot))
+	for m := range snapshot {
+		markets[m] = struct{}{}
+	}
+	for _, pos := range s.positions.Positions(inf) {
+		if reconcilable(pos.Market) {
+			markets[pos.Market] = struct{}{}
+		}
+	}
+
+	for market := range markets {
+		book := s.positions.Get(inf, market)
+		snap, ok := snapshot[market]
+		if !ok {
+			snap = domain.Position{Market: market, UpdatedMs: requested.UnixMilli()}
+		}
+		if sameSize(book.Size, snap.Size) || book.UpdatedMs >= requested.UnixMilli() {
+			// In sync, or updated by a fill the snapshot may not include yet.
+			delete(pending, market)
+			continue
+		}
+		if seen, ok := pending[market]; !ok || !sameSize(seen, snap.Size) {
+			pending[market] = snap.Size
+			continue
+		}
+		delete(pending, market)
+
+		s.logger.WarnContext(ctx, "position drift", slog.String("market", market), slog.Float64("book_size", book.Size), slog.Float64("exchange_size", snap.Size))
+		sig := NewReconciliationSignal(inf, book, snap, markPx[market])
+		if err := s.recordRaw(ctx, inf, busv1.RawEventType_RAW_EVENT_TYPE_POSITION_SNAPSHOT, market, sig.GetSourceEventId(), sig.GetTimestampMs(), requested, raw[market]); err != nil {
+			s.logger.ErrorContext(ctx, "record raw position snapshot", observability.Err(err))
+			continue
+		}
+		coin := market
+		if ap, ok := raw[market]; ok {
+			coin = ap.Position.Coin
+		}
+		if err := s.publish(ctx, p, coin, sig); err != nil {
+			continue
+		}
+		if err := s.positions.Set(ctx, inf, snap); err != nil {
+			s.logger.ErrorContext(ctx, "store reconciled position", slog.String("market", market), observability.Err(err))
+		}
+	}
+	return nil
+}
+
+// seedPositions fills the book of inf from a clearinghouseState snapshot
+// without publishing signals.
+func (s *HyperliquidService) seedPositions(ctx context.Context, inf *domain.Influencer) error {
+	requested := time.Now().UTC()
+	state, err := s.info.UserState(ctx, inf.Address)
+	if err != nil {
+		return fmt.Errorf("clearinghouseState: %w", err)
+	}
+	for _, ap := range state.AssetPositions {
+		pos, err := s.positionFromSnapshot(ap.Position, requested)
+		if err != nil {
+			return fmt.Errorf("position %s: %w", ap.Position.Coin, err)
+		}
+		if err := s.positions.Set(ctx, inf, pos); err != nil {
+			return fmt.Errorf("store position %s: %w", pos.Market, err)
+		}
+	}
+	return nil
+}
+
+// NewReconciliationSignal builds the corrective signal that moves followers
+// from the book position to the exchange snapshot position. Only default-dex
+// perps are reconciled. It is priced at markPx, the price followers would
+// trade at now, falling back to the entry prices when it is unknown. It is
+// flagged with metadata["reconciliation"]="true".
+func NewReconciliationSignal(inf *domain.Influencer, book, snap domain.Position, markPx float64) *busv1.Signal {
+	market := snap.Market
+	sourceID := fmt.Sprintf("reconcile:%s:%d", market, snap.UpdatedMs)
+	price := markPx
+	if price == 0 {
+		price = snap.EntryPx
+	}
+	if price == 0 {
+		price = book.EntryPx
+	}
+	// Book values are float64 parsed from exchange strings; their shortest
+	// representations recover those strings, so the delta is exact.
+	snapSize, _ := numbers.DecimalFromFloat(snap.Size)
+	bookSize, _ := numbers.DecimalFromFloat(book.Size)
+	delta := snapSize.Sub(bookSize)
+	px, _ := numbers.DecimalFromFloat(price)
+	return &busv1.Signal{
+		SignalId:         buildSignalID(inf.Address, market, sourceID),
+		InfluencerId:     inf.Address,
+		Exchange:         "hyperliquid",
+		Market:           market,
+		MarketType:       busv1.MarketType_MARKET_TYPE_PERP,
+		Action:           deriveSignalAction(book.Size, snap.Size),
+		Side:             normalizeSignalSide(positionSideFromSize(snap.Size)),
+		Size:             math.Abs(snap.Size),
+		DeltaSize:        delta.Float64(),
+		Price:            price,
+		SizeDecimal:      snapSize.Abs().String(),
+		DeltaSizeDecimal: delta.String(),
+		PriceDecimal:     px.String(),
+		TimestampMs:      snap.UpdatedMs,
+		SourceEventId:    sourceID,
+		Metadata: map[string]string{
+			"event_type":         "reconciliation",
+			"source_event_id":    sourceID,
+			"influencer_address": inf.Address,
+			"reconciliation":     "true",
+		},
+	}
+}
+
+func (s *HyperliquidService) positionFromSnapshot(pos hl.Position, at time.Time) (domain.Position, error) {
+	size, err := numbers.ExtractFloat(pos.Szi)
+	if err != nil {
+		return domain.Position{}, fmt.Errorf("szi: %w", err)
+	}
+	var entry float64
+	if pos.EntryPx != nil {
+		entry, _ = numbers.ExtractFloat(*pos.EntryPx)
+	}
+	return domain.Position{
+		Market:    s.markets.Normalize(pos.Coin).Market,
+		Size:      size,
+		EntryPx:   entry,
+		UpdatedMs: at.UnixMilli(),
+	}, nil
+}
+
+// snapshotMarkPx is the mark price implied by a clearinghouseState position,
+// its notional value over its size; zero when either is missing.
+func snapshotMarkPx(pos hl.Position) float64 {
+	value, err := numbers.ExtractFloat(pos.PositionValue)
+	if err != nil {
+		return 0
+	}
+	size, err := numbers.ExtractFloat(pos.Szi)
+	if err != nil || size == 0 {
+		return 0
+	}
+	return math.Abs(value / size)
+}
+
+// reconcilable reports whether market is covered by the default-dex
+// clearinghouseState snapshot: spot and HIP-3 ("dex:COIN-PERP") positions are
+// not, and would otherwise always look like drift.
+func reconcilable(market string) bool {
+	return strings.HasSuffix(market, "-PERP") && !strings.Contains(market, ":")
+}
+
+// sameSize compares position sizes with a tolerance for float accumulation.
+func sameSize(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(1, math.Abs(a), math.Abs(b))
+}
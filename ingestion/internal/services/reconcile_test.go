@@ -0,0 +1,95 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+func TestSnapshotMarkPx(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  hl.Position
+		want float64
+	}{
+		{name: "long", pos: hl.Position{Szi: "2", PositionValue: "4100"}, want: 2050},
+		{name: "short", pos: hl.Position{Szi: "-0.5", PositionValue: "1025"}, want: 2050},
+		{name: "flat", pos: hl.Position{Szi: "0", PositionValue: "0"}},
+		{name: "missing value", pos: hl.Position{Szi: "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := snapshotMarkPx(tt.pos); got != tt.want {
+				t.Fatalf("snapshotMarkPx = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewReconciliationSignal(t *testing.T) {
+	inf := &domain.Influencer{Address: testInfluencer}
+	tests := []struct {
+		name       string
+		book, snap domain.Position
+		markPx     float64
+		wantPx     string
+		wantDelta  string
+		wantAction busv1.SignalAction
+		wantSide   busv1.SignalSide
+	}{
+		{
+			name:       "missed increase is priced at the mark",
+			book:       domain.Position{Market: "ETH-PERP", Size: 1, EntryPx: 1500},
+			snap:       domain.Position{Market: "ETH-PERP", Size: 3, EntryPx: 1800, UpdatedMs: 1000},
+			markPx:     2050,
+			wantPx:     "2050",
+			wantDelta:  "2",
+			wantAction: busv1.SignalAction_SIGNAL_ACTION_INCREASE,
+			wantSide:   busv1.SignalSide_SIGNAL_SIDE_LONG,
+		},
+		{
+			name:       "missed close",
+			book:       domain.Position{Market: "ETH-PERP", Size: -1.5, EntryPx: 1500},
+			snap:       domain.Position{Market: "ETH-PERP", UpdatedMs: 1000},
+			markPx:     2050,
+			wantPx:     "2050",
+			wantDelta:  "1.5",
+			wantAction: busv1.SignalAction_SIGNAL_ACTION_CLOSE,
+			wantSide:   busv1.SignalSide_SIGNAL_SIDE_FLAT,
+		},
+		{
+			name:       "unknown mark falls back to the snapshot entry",
+			book:       domain.Position{Market: "ETH-PERP", Size: 1, EntryPx: 1500},
+			snap:       domain.Position{Market: "ETH-PERP", Size: 0.5, EntryPx: 1800, UpdatedMs: 1000},
+			wantPx:     "1800",
+			wantDelta:  "-0.5",
+			wantAction: busv1.SignalAction_SIGNAL_ACTION_DECREASE,
+			wantSide:   busv1.SignalSide_SIGNAL_SIDE_LONG,
+		},
+		{
+			name:       "unknown mark on a closed position falls back to the book entry",
+			book:       domain.Position{Market: "ETH-PERP", Size: 1, EntryPx: 1500},
+			snap:       domain.Position{Market: "ETH-PERP", UpdatedMs: 1000},
+			wantPx:     "1500",
+			wantDelta:  "-1",
+			wantAction: busv1.SignalAction_SIGNAL_ACTION_CLOSE,
+			wantSide:   busv1.SignalSide_SIGNAL_SIDE_FLAT,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sig := NewReconciliationSignal(inf, tt.book, tt.snap, tt.markPx)
+			if sig.GetPriceDecimal() != tt.wantPx || sig.GetDeltaSizeDecimal() != tt.wantDelta {
+				t.Errorf("price/delta = %s/%s, want %s/%s", sig.GetPriceDecimal(), sig.GetDeltaSizeDecimal(), tt.wantPx, tt.wantDelta)
+			}
+			if sig.GetAction() != tt.wantAction || sig.GetSide() != tt.wantSide {
+				t.Errorf("action/side = %v/%v, want %v/%v", sig.GetAction(), sig.GetSide(), tt.wantAction, tt.wantSide)
+			}
+			if sig.GetMetadata()["reconciliation"] != "true" || sig.GetSourceEventId() != "reconcile:ETH-PERP:1000" {
+				t.Errorf("unexpected reconciliation envelope: %v", sig)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/hashring"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+)
+
+// Membership is the live instance registry shards are hashed over.
+// Implementation: store.MembershipStore.
+type Membership interface {
+	InstanceID() string
+	Heartbeat(ctx context.Context) error
+	Members(ctx context.Context) ([]string, error)
+	Leave(ctx context.Context) error
+}
+
+// ShardCoordinator deterministically assigns influencers to ingestion instances
+// by consistent hashing of the influencer address over the live membership
+// registry. It is the alternative to the work-queue style InfluencerStore.Acquire.
+//
+// When ownership of an influencer moves away from this instance, its stream is
+// kept alive for a handoff overlap window so the new owner has time to connect
+// before the old stream stops; duplicates during the overlap are absorbed by the
+// deterministic signal IDs downstream.
+type ShardCoordinator struct {
+	influencers *store.InfluencerStore
+	membership  Membership
+	logger      *slog.Logger
+
+	interval     time.Duration
+	overlap      time.Duration
+	virtualNodes int
+
+	members   []string
+	releasing map[string]time.Time
+}
+
+func NewShardCoordinator(cfg config.Config, influencers *store.InfluencerStore, membership Membership, logger *slog.Logger) *ShardCoordinator {
+	return &ShardCoordinator{
+		influencers:  influencers,
+		membership:   membership,
+		logger:       logger,
+		interval:     cfg.ShardRebalance,
+		overlap:      cfg.ShardHandoffOverlap,
+		virtualNodes: cfg.ShardVirtualNodes,
+		releasing:    make(map[string]time.Time),
+	}
+}
+
+// Interval returns how often Rebalance should be invoked.
+func (c *ShardCoordinator) Interval() time.Duration {
+	if c.interval <= 0 {
+		return 5 * time.Second
+	}
+	return c.interval
+}
+
+// Rebalance refreshes this instance's registration, recomputes ownership and
+// returns the influencers that should start streaming plus the stream IDs
+// (influencer addresses) whose handoff window has elapsed and should stop.
+// running lists the stream IDs currently active on this instance. A positive
+// limit caps how many streams run here, streams still handing off included;
+// owned influencers beyond it are started by a later rebalance once there is
+// room.
+func (c *ShardCoordinator) Rebalance(ctx context.Context, running []string, limit int) ([]*domain.Influencer, []string, error) {
+	if err := c.membership.Heartbeat(ctx); err != nil {
+		return nil, nil, fmt.Errorf("heartbeat: %w", err)
+	}
+	members, err := c.membership.Members(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list members: %w", err)
+	}
+	slices.Sort(members)
+	infs, err := c.influencers.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("list influencers: %w", err)
+	}
+	if !slices.Equal(c.members, members) {
+		c.logger.InfoContext(ctx, "ingestion membership changed", slog.Any("from", c.members), slog.Any("to", members))
+		c.members = members
+	}
+
+	ring := hashring.New(c.virtualNodes, members...)
+	self := c.membership.InstanceID()
+
+	owned := make(map[string]struct{}, len(infs))
+	isRunning := make(map[string]struct{}, len(running))
+	for _, id := range running {
+		isRunning[id] = struct{}{}
+	}
+
+	// Sorted so that, at the limit, every rebalance starts the same influencers.
+	slices.SortFunc(infs, func(a, b domain.Influencer) int { return strings.Compare(a.Address, b.Address) })
+	var (
+		start    []*domain.Influencer
+		deferred int
+	)
+	for i := range infs {
+		inf := infs[i]
+		if ring.Owner(strings.ToLower(inf.Address)) != self {
+			continue
+		}
+		owned[inf.Address] = struct{}{}
+		if _, ok := c.releasing[inf.Address]; ok {
+			c.logger.InfoContext(ctx, "influencer reassigned back to this instance, cancelling handoff", slog.String(observability.LogKeyInfluencerID, inf.Address))
+			delete(c.releasing, inf.Address)
+		}
+		if _, ok := isRunning[inf.Address]; ok {
+			continue
+		}
+		if limit > 0 && len(running)+len(start) >= limit {
+			deferred++
+			continue
+		}
+		start = append(start, &inf)
+	}
+	if deferred > 0 {
+		c.logger.WarnContext(ctx, "max_streams reached, owned influencers not streamed", slog.Int("limit", limit), slog.Int("deferred", deferred))
+	}
+
+	now := time.Now()
+	var stop []string
+	for _, id := range running {
+		if _, ok := owned[id]; ok {
+			continue
+		}
+		deadline, ok := c.releasing[id]
+		if !ok {
+			c.logger.InfoContext(ctx, "influencer moved to another instance, handing off", slog.String(observability.LogKeyInfluencerID, id), slog.Duration("overlap", c.overlap))
+			c.releasing[id] = now.Add(c.overlap)
+			continue
+		}
+		if !now.Before(deadline) {
+			stop = append(stop, id)
+			delete(c.releasing, id)
+		}
+	}
+	// Drop release deadlines for streams that already terminated on their own.
+	for id := range c.releasing {
+		if _, ok := isRunning[id]; !ok {
+			delete(c.releasing, id)
+		}
+	}
+
+	return start, stop, nil
+}
+
+// Leave deregisters this instance so peers pick up its influencers immediately.
+func (c *ShardCoordinator) Leave(ctx context.Context) error {
+	return c.membership.Leave(ctx)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/hashring"
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// fakeMembership is a registry whose live members the test sets directly.
+type fakeMembership struct {
+	self    string
+	members []string
+	err     error
+}
+
+func (m *fakeMembership) InstanceID() string { return m.self }
+
+func (m *fakeMembership) Heartbeat(context.Context) error { return m.err }
+
+func (m *fakeMembership) Members(context.Context) ([]string, error) {
+	return slices.Clone(m.members), nil
+}
+
+func (m *fakeMembership) Leave(context.Context) error { return nil }
+
+// newTestCoordinator returns a coordinator for instance "a" over addrs, with
+// no handoff overlap so a released stream stops on the next rebalance.
+func newTestCoordinator(t *testing.T, addrs []string) (*fakeMembership, *ShardCoordinator) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	influencers := store.NewInfluencerStore(client, "influencers")
+	for _, addr := range addrs {
+		if err := influencers.Add(context.Background(), domain.Influencer{Address: addr}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	membership := &fakeMembership{self: "a", members: []string{"a"}}
+	cfg := config.Config{ShardVirtualNodes: hashring.DefaultReplicas}
+	return membership, NewShardCoordinator(cfg, influencers, membership, slog.New(slog.DiscardHandler))
+}
+
+// testAddresses returns sorted influencer addresses split by whether they stay
+// with "a" or move to "b" once "b" joins.
+func testAddresses(t *testing.T) (all, kept, moved []string) {
+	t.Helper()
+	ring := hashring.New(hashring.DefaultReplicas, "a", "b")
+	for i := range 8 {
+		addr := fmt.Sprintf("0x%040x", i)
+		all = append(all, addr)
+		if ring.Owner(strings.ToLower(addr)) == "a" {
+			kept = append(kept, addr)
+		} else {
+			moved = append(moved, addr)
+		}
+	}
+	if len(kept) == 0 || len(moved) == 0 {
+		t.Fatalf("addresses split %v / %v, want some on each member", kept, moved)
+	}
+	return all, kept, moved
+}
+
+func addresses(infs []*domain.Influencer) []string {
+	out := make([]string, len(infs))
+	for i, inf := range infs {
+		out[i] = inf.Address
+	}
+	return out
+}
+
+func TestRebalance(t *testing.T) {
+	all, kept, moved := testAddresses(t)
+	type step struct {
+		name      string
+		members   []string
+		running   []string
+		limit     int
+		wantStart []string
+		wantStop  []string
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "acquire and release",
+			steps: []step{
+				{name: "sole member starts every influencer", members: []string{"a"}, wantStart: all},
+				{name: "peer joins, moved streams hand off", members: []string{"a", "b"}, running: all},
+				{name: "handoff elapsed, moved streams stop", members: []string{"a", "b"}, running: all, wantStop: moved},
+				{name: "nothing left to do", members: []string{"a", "b"}, running: kept},
+			},
+		},
+		{
+			name: "reassigned back during handoff",
+			steps: []step{
+				{name: "peer joins", members: []string{"a", "b"}, running: all},
+				{name: "peer leaves before the handoff elapses", members: []string{"a"}, running: all},
+				{name: "peer rejoins, handoff starts over", members: []string{"a", "b"}, running: all},
+				{name: "handoff elapsed", members: []string{"a", "b"}, running: all, wantStop: moved},
+			},
+		},
+		{
+			name: "released stream ended on its own",
+			steps: []step{
+				{name: "peer joins", members: []string{"a", "b"}, running: all},
+				{name: "moved streams ended", members: []string{"a", "b"}, running: kept},
+				{name: "restarted moved streams hand off again", members: []string{"a", "b"}, running: all},
+			},
+		},
+		{
+			name: "max_streams",
+			steps: []step{
+				{name: "starts up to the limit", members: []string{"a"}, limit: 3, wantStart: all[:3]},
+				{name: "at the limit", members: []string{"a"}, running: all[:3], limit: 3},
+				{name: "limit raised", members: []string{"a"}, running: all[:3], limit: 5, wantStart: all[3:5]},
+				{name: "handoffs count toward the limit", members: []string{"a", "b"}, running: moved, limit: len(moved)},
+				{name: "unlimited", members: []string{"a"}, running: all[:5], wantStart: all[5:]},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			membership, c := newTestCoordinator(t, all)
+			for _, s := range tt.steps {
+				membership.members = s.members
+				start, stop, err := c.Rebalance(context.Background(), s.running, s.limit)
+				if err != nil {
+					t.Fatalf("%s: Rebalance: %v", s.name, err)
+				}
+				if got := addresses(start); !slices.Equal(got, s.wantStart) {
+					t.Fatalf("%s: start %v, want %v", s.name, got, s.wantStart)
+				}
+				slices.Sort(stop)
+				if !slices.Equal(stop, s.wantStop) {
+					t.Fatalf("%s: stop %v, want %v", s.name, stop, s.wantStop)
+				}
+			}
+		})
+	}
+}
+
+func TestRebalanceHeartbeatFailure(t *testing.T) {
+	all, _, _ := testAddresses(t)
+	membership, c := newTestCoordinator(t, all)
+	membership.err = errors.New("registry unavailable")
+	start, stop, err := c.Rebalance(context.Background(), nil, 0)
+	if err == nil || len(start) != 0 || len(stop) != 0 {
+		t.Fatalf("Rebalance = %v, %v, %v; want the heartbeat error and no changes", start, stop, err)
+	}
+}
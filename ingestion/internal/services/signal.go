@@ -2,14 +2,21 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/kafka"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/store"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
 	"github.com/0xRichardL/vibe-copy-trading/libs/go/routine"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
 )
 
 const (
@@ -21,17 +28,37 @@ type SignalService struct {
 	store       *store.InfluencerStore
 	hyperliquid *HyperliquidService
 	publisher   *kafka.SignalPublisher
+	outbox      *store.SignalOutbox
+	orders      *kafka.OrderIntentPublisher
+	shards      *ShardCoordinator
+	settings    *libconfig.Reloader[config.Runtime]
+	modes       *tradingmode.Switch
+	logger      *slog.Logger
 
 	once         sync.Once
 	manager      *routine.Manager
 	pollInterval time.Duration
 }
 
-func NewSignalService(store *store.InfluencerStore, hyperliquid *HyperliquidService, publisher *kafka.SignalPublisher) *SignalService {
+// NewSignalService builds a SignalService. When shards is nil influencers are
+// acquired from the shared Redis work queue; otherwise they are assigned by
+// consistent hashing across live ingestion instances. Signals are appended to
+// outbox and published by an OutboxFlusher; with a nil outbox they are
+// published directly. orders may be nil to skip order-intent publishing.
+// settings supplies the stream cap and publish timeouts as they change. Order
+// intents of an influencer or market whose trading mode is halted are dropped;
+// halted signals are held back by the HyperliquidService.
+func NewSignalService(store *store.InfluencerStore, hyperliquid *HyperliquidService, publisher *kafka.SignalPublisher, outbox *store.SignalOutbox, orders *kafka.OrderIntentPublisher, shards *ShardCoordinator, settings *libconfig.Reloader[config.Runtime], modes *tradingmode.Switch, logger *slog.Logger) *SignalService {
 	return &SignalService{
 		store:        store,
 		hyperliquid:  hyperliquid,
 		publisher:    publisher,
+		outbox:       outbox,
+		orders:       orders,
+		shards:       shards,
+		settings:     settings,
+		modes:        modes,
+		logger:       logger,
 		pollInterval: defaultPollInterval,
 	}
 }
@@ -42,6 +69,9 @@ func (s *SignalService) Start(ctx context.Context) error {
 	s.once.Do(func() {
 		s.manager = routine.NewManager(ctx)
 	})
+	if s.shards != nil {
+		return s.startSharded(ctx)
+	}
 	for {
 		select {
 		case <-ctx.Done():
@@ -49,6 +79,16 @@ func (s *SignalService) Start(ctx context.Context) error {
 		default:
 		}
 
+		// At the MAX_STREAMS cap, wait for a stream to end or the cap to rise.
+		if limit := s.settings.Current().MaxStreams; limit > 0 && s.manager.TaskCount() >= limit {
+			select {
+			case <-ctx.Done():
+				return s.manager.ShutdownAll()
+			case <-time.After(s.pollInterval):
+			}
+			continue
+		}
+
 		inf, putBack, err := s.store.Acquire(ctx)
 		if err != nil {
 			if err == store.ErrNoInfluencers {
@@ -62,35 +102,122 @@ func (s *SignalService) Start(ctx context.Context) error {
 			return fmt.Errorf("acquire influencer: %w", err)
 		}
 
-		err = s.manager.RunTask(&routine.Task{
-			ID: inf.Address,
-			Handler: func(taskCtx context.Context) error {
-				if err := s.hyperliquid.SubscribeAccountEvents(taskCtx, inf, s.handleSignal); err != nil {
-					return fmt.Errorf("subscribe to hyperliquid events: %w", err)
-				}
-				return nil
-			},
-			OnDone: func(id string) {
-				if err := putBack(); err != nil {
-					fmt.Printf("put back influencer %s: %v\n", inf.Address, err)
-				}
-			},
-		})
+		task := s.streamTask(inf)
+		onDone := task.OnDone
+		task.OnDone = func(id string) {
+			onDone(id)
+			if err := putBack(); err != nil {
+				s.logger.Error("put back influencer", slog.String(observability.LogKeyInfluencerID, id), observability.Err(err))
+			}
+		}
+		err = s.manager.RunTask(task)
 		if err != nil {
 			if err := putBack(); err != nil {
-				fmt.Printf("put back influencer %s: %v\n", inf.Address, err)
+				s.logger.Error("put back influencer", slog.String(observability.LogKeyInfluencerID, inf.Address), observability.Err(err))
 			}
 			return fmt.Errorf("run task: %w", err)
 		}
 	}
 }
 
+// startSharded periodically rebalances consistent-hash ownership and starts or
+// stops influencer streams accordingly. Influencers stay in the Redis set.
+func (s *SignalService) startSharded(ctx context.Context) error {
+	defer func() {
+		leaveCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.shards.Leave(leaveCtx); err != nil {
+			s.logger.Error("leave ingestion membership", observability.Err(err))
+		}
+	}()
+
+	ticker := time.NewTicker(s.shards.Interval())
+	defer ticker.Stop()
+	for {
+		start, stop, err := s.shards.Rebalance(ctx, s.manager.TaskIDs(), s.settings.Current().MaxStreams)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "rebalance influencer shards", observability.Err(err))
+		}
+		for _, id := range stop {
+			if err := s.manager.Shutdown(id); err != nil && !errors.Is(err, routine.ErrRoutineNotFound) {
+				s.logger.ErrorContext(ctx, "stop influencer", slog.String(observability.LogKeyInfluencerID, id), observability.Err(err))
+			}
+		}
+		for _, inf := range start {
+			if err := s.manager.RunTask(s.streamTask(inf)); err != nil && !errors.Is(err, routine.ErrRoutineExists) {
+				return fmt.Errorf("run task: %w", err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return s.manager.ShutdownAll()
+		case <-ticker.C:
+		}
+	}
+}
+
+// streamTask runs inf's account event stream; its lifecycle hooks log stream
+// starts, stops and failures.
+func (s *SignalService) streamTask(inf *domain.Influencer) *routine.Task {
+	logger := s.logger.With(slog.String(observability.LogKeyInfluencerID, inf.Address))
+	return &routine.Task{
+		ID: inf.Address,
+		OnStart: func(string) {
+			logger.Info("influencer stream started")
+		},
+		OnDone: func(string) {
+			logger.Info("influencer stream stopped")
+		},
+		OnError: func(_ string, err error) {
+			logger.Error("influencer stream failed", observability.Err(err))
+		},
+		Handler: func(taskCtx context.Context) error {
+			activeStreams.Inc()
+			defer activeStreams.Dec()
+			var orders OrderIntentHandler
+			if s.orders != nil {
+				orders = s.handleOrderIntent
+			}
+			if err := s.hyperliquid.SubscribeAccountEvents(taskCtx, inf, s.handleSignal, orders); err != nil {
+				return fmt.Errorf("subscribe to hyperliquid events: %w", err)
+			}
+			return nil
+		},
+	}
+}
+
 func (s *SignalService) handleSignal(ctx context.Context, sig *busv1.Signal) error {
 	if sig == nil {
 		return nil
 	}
+	if s.outbox != nil {
+		if err := s.outbox.Append(ctx, sig); err != nil {
+			return err
+		}
+		if sig.GetMetadata()["backfilled"] != "true" {
+			observability.ObserveSinceMillis(stageLatency.WithLabelValues(stageAppended), sig.GetTimestampMs(), time.Now())
+		}
+		return nil
+	}
 
-	ctxPub, cancel := context.WithTimeout(ctx, 5*time.Second)
+	ctxPub, cancel := context.WithTimeout(ctx, s.settings.Current().PublishTimeout)
 	defer cancel()
 	return s.publisher.Publish(ctxPub, sig)
 }
+
+func (s *SignalService) handleOrderIntent(ctx context.Context, intent *busv1.OrderIntent) error {
+	if intent == nil || s.halted(intent.GetInfluencerId(), intent.GetMarket()) {
+		return nil
+	}
+
+	ctxPub, cancel := context.WithTimeout(ctx, s.settings.Current().PublishTimeout)
+	defer cancel()
+	return s.orders.Publish(ctxPub, intent)
+}
+
+// halted reports whether the trading kill switch halts the influencer or
+// market.
+func (s *SignalService) halted(influencer, market string) bool {
+	return s.modes.Resolve(tradingmode.Target{Influencer: influencer, Market: market}) == tradingmode.Halted
+}
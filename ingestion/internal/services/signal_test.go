@@ -0,0 +1,73 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/markets"
+)
+
+func TestDeriveSignalAction(t *testing.T) {
+	tests := []struct {
+		name       string
+		prev, next float64
+		want       busv1.SignalAction
+	}{
+		{name: "flat to long", prev: 0, next: 1, want: busv1.SignalAction_SIGNAL_ACTION_OPEN},
+		{name: "flat to short", prev: 0, next: -1, want: busv1.SignalAction_SIGNAL_ACTION_OPEN},
+		{name: "long to flat", prev: 1, next: 0, want: busv1.SignalAction_SIGNAL_ACTION_CLOSE},
+		{name: "short to flat", prev: -1, next: 0, want: busv1.SignalAction_SIGNAL_ACTION_CLOSE},
+		{name: "long grows", prev: 1, next: 2, want: busv1.SignalAction_SIGNAL_ACTION_INCREASE},
+		{name: "short grows", prev: -1, next: -2, want: busv1.SignalAction_SIGNAL_ACTION_INCREASE},
+		{name: "long shrinks", prev: 2, next: 1, want: busv1.SignalAction_SIGNAL_ACTION_DECREASE},
+		{name: "short shrinks", prev: -2, next: -1, want: busv1.SignalAction_SIGNAL_ACTION_DECREASE},
+		{name: "long to short", prev: 1, next: -1, want: busv1.SignalAction_SIGNAL_ACTION_FLIP},
+		{name: "short to long", prev: -1, next: 3, want: busv1.SignalAction_SIGNAL_ACTION_FLIP},
+		{name: "stays flat", prev: 0, next: 0, want: busv1.SignalAction_SIGNAL_ACTION_UNSPECIFIED},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := deriveSignalAction(tt.prev, tt.next); got != tt.want {
+				t.Fatalf("deriveSignalAction(%v, %v) = %v, want %v", tt.prev, tt.next, got, tt.want)
+			}
+		})
+	}
+}
+
+// Fills carry the position before the fill; the action and side follow from
+// it and the fill direction.
+func TestNormalizeEventToSignalAction(t *testing.T) {
+	inf := &domain.Influencer{Address: testInfluencer}
+	sym := markets.Symbol{Coin: "ETH", Market: "ETH-PERP", Type: markets.TypePerp}
+	tests := []struct {
+		name       string
+		side       string
+		startPos   string
+		sz         string
+		wantAction busv1.SignalAction
+		wantSide   busv1.SignalSide
+		wantDelta  string
+	}{
+		{name: "buy from flat opens a long", side: "B", startPos: "0", sz: "1", wantAction: busv1.SignalAction_SIGNAL_ACTION_OPEN, wantSide: busv1.SignalSide_SIGNAL_SIDE_LONG, wantDelta: "1"},
+		{name: "sell from flat opens a short", side: "A", startPos: "0", sz: "1", wantAction: busv1.SignalAction_SIGNAL_ACTION_OPEN, wantSide: busv1.SignalSide_SIGNAL_SIDE_SHORT, wantDelta: "-1"},
+		{name: "buy closes a short", side: "B", startPos: "-1", sz: "1", wantAction: busv1.SignalAction_SIGNAL_ACTION_CLOSE, wantSide: busv1.SignalSide_SIGNAL_SIDE_FLAT, wantDelta: "1"},
+		{name: "sell closes a long", side: "A", startPos: "2", sz: "2", wantAction: busv1.SignalAction_SIGNAL_ACTION_CLOSE, wantSide: busv1.SignalSide_SIGNAL_SIDE_FLAT, wantDelta: "-2"},
+		{name: "buy past a short flips", side: "B", startPos: "-1", sz: "3", wantAction: busv1.SignalAction_SIGNAL_ACTION_FLIP, wantSide: busv1.SignalSide_SIGNAL_SIDE_LONG, wantDelta: "3"},
+		{name: "sell adds to a short", side: "A", startPos: "-1", sz: "0.5", wantAction: busv1.SignalAction_SIGNAL_ACTION_INCREASE, wantSide: busv1.SignalSide_SIGNAL_SIDE_SHORT, wantDelta: "-0.5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fill := wsFill("ETH", 1, 10, 1_700_000_000_000, "2000", tt.sz, tt.startPos)
+			fill.Side = tt.side
+			sig, err := NormalizeEventToSignal(inf, fill, sym, time.UnixMilli(1_700_000_000_100))
+			if err != nil {
+				t.Fatalf("NormalizeEventToSignal: %v", err)
+			}
+			if sig.GetAction() != tt.wantAction || sig.GetSide() != tt.wantSide || sig.GetDeltaSizeDecimal() != tt.wantDelta {
+				t.Fatalf("action/side/delta = %v/%v/%s, want %v/%v/%s", sig.GetAction(), sig.GetSide(), sig.GetDeltaSizeDecimal(), tt.wantAction, tt.wantSide, tt.wantDelta)
+			}
+		})
+	}
+}
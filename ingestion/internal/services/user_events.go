@@ -0,0 +1,40 @@
+package services
+
+import (
+	"encoding/json"
+
+	hl "github.com/sonirico/go-hyperliquid"
+)
+
+// Channels of the userEvents subscription, whose data Hyperliquid delivers on
+// the "user" channel.
+const (
+	channelUserEvents = "userEvents"
+	channelUser       = "user"
+)
+
+// UserEvent is one frame of the Hyperliquid userEvents channel. Exactly one of
+// the fields is set per frame.
+type UserEvent struct {
+	Fills         []Received[hl.WsOrderFill] `json:"fills,omitempty"`
+	Funding       json.RawMessage            `json:"funding,omitempty"`
+	Liquidation   *Received[UserLiquidation] `json:"liquidation,omitempty"`
+	NonUserCancel []Received[NonUserCancel]  `json:"nonUserCancel,omitempty"`
+}
+
+// UserLiquidation is the account-level liquidation notice. The per-market
+// position changes arrive as fills carrying a liquidation block.
+type UserLiquidation struct {
+	Lid                    int64  `json:"lid"`
+	Liquidator             string `json:"liquidator"`
+	LiquidatedUser         string `json:"liquidated_user"`
+	LiquidatedNtlPos       string `json:"liquidated_ntl_pos"`
+	LiquidatedAccountValue string `json:"liquidated_account_value"`
+}
+
+// NonUserCancel is an order cancelled by the exchange rather than the user
+// (e.g. margin or reduce-only violations).
+type NonUserCancel struct {
+	Coin string `json:"coin"`
+	Oid  int64  `json:"oid"`
+}
@@ -0,0 +1,13 @@
+package services
+
+import hl "github.com/sonirico/go-hyperliquid"
+
+const channelUserFills = "userFills"
+
+// UserFills is one frame of the Hyperliquid userFills channel. Every
+// (re)subscription starts with a snapshot frame of recent fills.
+type UserFills struct {
+	IsSnapshot bool                       `json:"isSnapshot"`
+	User       string                     `json:"user"`
+	Fills      []Received[hl.WsOrderFill] `json:"fills"`
+}
@@ -0,0 +1,148 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// advanceCursorScript only moves the cursor forward so concurrent listeners
+// (double listeners, shard handoff overlap) can never rewind it. Tids
+// published within the cursor's millisecond are kept so a lower tid delivered
+// later in that millisecond is still published.
+var advanceCursorScript = redis.NewScript(`
+local cur = redis.call('HGET', KEYS[1], ARGV[1])
+local t, ts, tids = nil, nil, {}
+if cur then
+  local sep = string.find(cur, ':', 1, true)
+  ts = string.sub(cur, 1, sep - 1)
+  t = tonumber(ts)
+  for id in string.gmatch(string.sub(cur, sep + 1), '[^,]+') do
+    tids[#tids + 1] = id
+  end
+end
+local moved = 0
+for i = 2, #ARGV, 2 do
+  local nts, nid = ARGV[i], ARGV[i + 1]
+  local nt = tonumber(nts)
+  if not t or nt > t then
+    t, ts, tids = nt, nts, {nid}
+    moved = 1
+  elseif nt == t then
+    local seen = false
+    for _, id in ipairs(tids) do
+      if id == nid then
+        seen = true
+        break
+      end
+    end
+    if not seen then
+      tids[#tids + 1] = nid
+      moved = 1
+    end
+  end
+end
+if moved == 1 then
+  redis.call('HSET', KEYS[1], ARGV[1], ts .. ':' .. table.concat(tids, ','))
+end
+return moved
+`)
+
+// CursorStore persists the last published fill per influencer+market in Redis.
+// Each influencer has a hash keyed by market whose values are
+// "time:tid[,tid...]", listing every tid published at that time.
+type CursorStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewCursorStore(client *redis.Client, prefix string) *CursorStore {
+	return &CursorStore{client: client, prefix: prefix}
+}
+
+// Get returns the cursor for an influencer+market and whether one exists.
+func (s *CursorStore) Get(ctx context.Context, influencer, market string) (domain.Cursor, bool, error) {
+	key := s.key(influencer)
+	raw, err := s.client.HGet(ctx, key, market).Result()
+	if err == redis.Nil {
+		return domain.Cursor{}, false, nil
+	}
+	if err != nil {
+		return domain.Cursor{}, false, fmt.Errorf("redis HGET %s: %w", key, err)
+	}
+	cur, err := parseCursor(raw)
+	if err != nil {
+		return domain.Cursor{}, false, fmt.Errorf("parse cursor %s/%s: %w", key, market, err)
+	}
+	return cur, true, nil
+}
+
+// Latest returns the most recent cursor across all markets of an influencer,
+// i.e. the last point in time ingestion is known to have been live for it.
+func (s *CursorStore) Latest(ctx context.Context, influencer string) (domain.Cursor, bool, error) {
+	key := s.key(influencer)
+	all, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return domain.Cursor{}, false, fmt.Errorf("redis HGETALL %s: %w", key, err)
+	}
+	var (
+		latest domain.Cursor
+		found  bool
+	)
+	for market, raw := range all {
+		cur, err := parseCursor(raw)
+		if err != nil {
+			return domain.Cursor{}, false, fmt.Errorf("parse cursor %s/%s: %w", key, market, err)
+		}
+		if !found || cur.TimeMs > latest.TimeMs || (cur.TimeMs == latest.TimeMs && cur.Tid > latest.Tid) {
+			latest = cur
+			found = true
+		}
+	}
+	return latest, found, nil
+}
+
+// Advance moves the cursor forward to the given fills. It reports false when
+// the stored cursor already covers all of them.
+func (s *CursorStore) Advance(ctx context.Context, influencer, market string, fills ...domain.Cursor) (bool, error) {
+	key := s.key(influencer)
+	args := make([]any, 0, 1+2*len(fills))
+	args = append(args, market)
+	for _, c := range fills {
+		args = append(args, c.TimeMs, c.Tid)
+	}
+	res, err := advanceCursorScript.Run(ctx, s.client, []string{key}, args...).Int()
+	if err != nil {
+		return false, fmt.Errorf("advance cursor %s/%s: %w", key, market, err)
+	}
+	return res == 1, nil
+}
+
+func (s *CursorStore) key(influencer string) string {
+	return s.prefix + ":" + strings.ToLower(influencer)
+}
+
+func parseCursor(raw string) (domain.Cursor, error) {
+	timePart, tidPart, ok := strings.Cut(raw, ":")
+	if !ok {
+		return domain.Cursor{}, fmt.Errorf("malformed cursor %q", raw)
+	}
+	t, err := strconv.ParseInt(timePart, 10, 64)
+	if err != nil {
+		return domain.Cursor{}, fmt.Errorf("cursor time: %w", err)
+	}
+	cur := domain.Cursor{TimeMs: t}
+	for part := range strings.SplitSeq(tidPart, ",") {
+		tid, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			return domain.Cursor{}, fmt.Errorf("cursor tid: %w", err)
+		}
+		cur.Tids = append(cur.Tids, tid)
+		cur.Tid = max(cur.Tid, tid)
+	}
+	return cur, nil
+}
@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const testInfluencer = "0x1111111111111111111111111111111111111111"
+
+func newTestRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, client
+}
+
+func TestCursorStoreAdvanceIsForwardOnly(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	cursors := NewCursorStore(client, "cursors")
+
+	steps := []struct {
+		cursor domain.Cursor
+		moved  bool
+	}{
+		{domain.Cursor{TimeMs: 1000, Tid: 5}, true},
+		{domain.Cursor{TimeMs: 900, Tid: 9}, false},
+		{domain.Cursor{TimeMs: 1000, Tid: 5}, false},
+		{domain.Cursor{TimeMs: 2000, Tid: 1}, true},
+	}
+	for i, step := range steps {
+		moved, err := cursors.Advance(ctx, testInfluencer, "ETH", step.cursor)
+		if err != nil {
+			t.Fatalf("step %d: Advance: %v", i, err)
+		}
+		if moved != step.moved {
+			t.Fatalf("step %d: moved = %v, want %v", i, moved, step.moved)
+		}
+	}
+	cur, ok, err := cursors.Get(ctx, testInfluencer, "ETH")
+	if err != nil || !ok {
+		t.Fatalf("Get = %v, %v, %v", cur, ok, err)
+	}
+	if cur.TimeMs != 2000 || cur.Tid != 1 {
+		t.Fatalf("cursor = %+v, want 2000:1", cur)
+	}
+}
+
+// Fills of one millisecond can arrive out of tid order; a lower tid delivered
+// after a higher one must not be treated as already published.
+func TestCursorStoreSameMillisecondOutOfOrderTids(t *testing.T) {
+	ctx := context.Background()
+	_, client := newTestRedis(t)
+	cursors := NewCursorStore(client, "cursors")
+
+	if _, err := cursors.Advance(ctx, testInfluencer, "ETH", domain.Cursor{TimeMs: 1000, Tid: 7}); err != nil {
+		t.Fatalf("Advance: %v", err)
+	}
+	cur, _, err := cursors.Get(ctx, testInfluencer, "ETH")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if cur.Covers(1000, 5) {
+		t.Fatal("tid 5 covered before it was published")
+	}
+	if !cur.Covers(1000, 7) || !cur.Covers(999, 100) {
+		t.Fatalf("cursor %+v does not cover published or earlier fills", cur)
+	}
+
+	moved, err := cursors.Advance(ctx, testInfluencer, "ETH", domain.Cursor{TimeMs: 1000, Tid: 5})
+	if err != nil || !moved {
+		t.Fatalf("Advance tid 5 = %v, %v; want true", moved, err)
+	}
+	cur, _, err = cursors.Get(ctx, testInfluencer, "ETH")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !cur.Covers(1000, 5) || !cur.Covers(1000, 7) || cur.Tid != 7 {
+		t.Fatalf("cursor = %+v, want tids 5 and 7 covered with Tid 7", cur)
+	}
+}
+
+func TestCursorStoreReadsLegacyCursor(t *testing.T) {
+	ctx := context.Background()
+	mr, client := newTestRedis(t)
+	cursors := NewCursorStore(client, "cursors")
+
+	mr.HSet("cursors:"+testInfluencer, "ETH", "1000:7")
+	cur, ok, err := cursors.Get(ctx, testInfluencer, "ETH")
+	if err != nil || !ok {
+		t.Fatalf("Get = %v, %v", ok, err)
+	}
+	if cur.TimeMs != 1000 || cur.Tid != 7 || !cur.Covers(1000, 7) {
+		t.Fatalf("cursor = %+v, want 1000:7", cur)
+	}
+	latest, ok, err := cursors.Latest(ctx, testInfluencer)
+	if err != nil || !ok || latest.TimeMs != 1000 {
+		t.Fatalf("Latest = %+v, %v, %v", latest, ok, err)
+	}
+}
@@ -0,0 +1,69 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// MembershipStore tracks live ingestion instances in a Redis sorted set.
+// Each member is scored by the unix millis at which its registration expires,
+// so instances that stop heartbeating fall out of the registry on their own.
+type MembershipStore struct {
+	client     *redis.Client
+	key        string
+	instanceID string
+	ttl        time.Duration
+}
+
+func NewMembershipStore(client *redis.Client, key, instanceID string, ttl time.Duration) *MembershipStore {
+	return &MembershipStore{client: client, key: key, instanceID: instanceID, ttl: ttl}
+}
+
+// InstanceID returns the identifier this store registers under.
+func (s *MembershipStore) InstanceID() string {
+	return s.instanceID
+}
+
+// Heartbeat registers (or refreshes) this instance in the registry.
+func (s *MembershipStore) Heartbeat(ctx context.Context) error {
+	if s.key == "" {
+		return fmt.Errorf("instance registry key is not configured")
+	}
+	expiresAt := time.Now().Add(s.ttl).UnixMilli()
+	if err := s.client.ZAdd(ctx, s.key, redis.Z{Score: float64(expiresAt), Member: s.instanceID}).Err(); err != nil {
+		return fmt.Errorf("redis ZADD %s: %w", s.key, err)
+	}
+	return nil
+}
+
+// Members evicts expired registrations and returns the live instance IDs.
+func (s *MembershipStore) Members(ctx context.Context) ([]string, error) {
+	if s.key == "" {
+		return nil, fmt.Errorf("instance registry key is not configured")
+	}
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+	if err := s.client.ZRemRangeByScore(ctx, s.key, "-inf", "("+now).Err(); err != nil {
+		return nil, fmt.Errorf("redis ZREMRANGEBYSCORE %s: %w", s.key, err)
+	}
+	members, err := s.client.ZRange(ctx, s.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis ZRANGE %s: %w", s.key, err)
+	}
+	return members, nil
+}
+
+// Leave removes this instance from the registry so peers can take over its
+// influencers without waiting for the registration to expire.
+func (s *MembershipStore) Leave(ctx context.Context) error {
+	if s.key == "" {
+		return fmt.Errorf("instance registry key is not configured")
+	}
+	if err := s.client.ZRem(ctx, s.key, s.instanceID).Err(); err != nil {
+		return fmt.Errorf("redis ZREM %s: %w", s.key, err)
+	}
+	return nil
+}
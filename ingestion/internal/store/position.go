@@ -0,0 +1,102 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/domain"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// PositionStore persists the influencer position book in Redis. Each
+// influencer has a hash keyed by market whose values are JSON positions, and
+// each market has an index hash keyed by influencer address with the same
+// values so market-wide views do not need to scan every influencer.
+type PositionStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewPositionStore(client *redis.Client, prefix string) *PositionStore {
+	return &PositionStore{client: client, prefix: prefix}
+}
+
+// All returns every stored position of an influencer keyed by market.
+func (s *PositionStore) All(ctx context.Context, influencer string) (map[string]domain.Position, error) {
+	return s.hash(ctx, s.key(influencer))
+}
+
+// ByMarket returns every open position on market keyed by influencer address.
+func (s *PositionStore) ByMarket(ctx context.Context, market string) (map[string]domain.Position, error) {
+	return s.hash(ctx, s.marketKey(market))
+}
+
+// Markets lists the markets with at least one open position.
+func (s *PositionStore) Markets(ctx context.Context) ([]string, error) {
+	prefix := s.marketKey("")
+	var markets []string
+	iter := s.client.Scan(ctx, 0, prefix+"*", 0).Iterator()
+	for iter.Next(ctx) {
+		markets = append(markets, strings.TrimPrefix(iter.Val(), prefix))
+	}
+	if err := iter.Err(); err != nil {
+		return nil, fmt.Errorf("redis SCAN %s*: %w", prefix, err)
+	}
+	return markets, nil
+}
+
+// Set stores pos, removing the market once the position is flat.
+func (s *PositionStore) Set(ctx context.Context, influencer string, pos domain.Position) error {
+	key, marketKey := s.key(influencer), s.marketKey(pos.Market)
+	addr := strings.ToLower(influencer)
+	if pos.Size == 0 {
+		_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, key, pos.Market)
+			pipe.HDel(ctx, marketKey, addr)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("redis HDEL %s: %w", key, err)
+		}
+		return nil
+	}
+	data, err := json.Marshal(pos)
+	if err != nil {
+		return fmt.Errorf("marshal position: %w", err)
+	}
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, pos.Market, data)
+		pipe.HSet(ctx, marketKey, addr, data)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("redis HSET %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *PositionStore) hash(ctx context.Context, key string) (map[string]domain.Position, error) {
+	raw, err := s.client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis HGETALL %s: %w", key, err)
+	}
+	out := make(map[string]domain.Position, len(raw))
+	for field, data := range raw {
+		var pos domain.Position
+		if err := json.Unmarshal([]byte(data), &pos); err != nil {
+			return nil, fmt.Errorf("unmarshal position %s/%s: %w", key, field, err)
+		}
+		out[field] = pos
+	}
+	return out, nil
+}
+
+func (s *PositionStore) key(influencer string) string {
+	return s.prefix + ":" + strings.ToLower(influencer)
+}
+
+func (s *PositionStore) marketKey(market string) string {
+	return s.prefix + ":market:" + market
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+const rawSegmentSuffix = ".seg"
+
+// RawEventFileStore appends raw events to size-bounded segment files in a local
+// directory. Each record is a length-delimited RawEvent proto. Segments are
+// append-only: once full, a new segment with the next index is started.
+type RawEventFileStore struct {
+	dir             string
+	maxSegmentBytes int64
+
+	mu    sync.Mutex
+	file  *os.File
+	index int
+	size  int64
+}
+
+// NewRawEventFileStore opens (or creates) the segment directory and resumes
+// appending to the newest segment.
+func NewRawEventFileStore(dir string, maxSegmentBytes int64) (*RawEventFileStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("raw event directory is not configured")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create raw event directory %s: %w", dir, err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read raw event directory %s: %w", dir, err)
+	}
+
+	s := &RawEventFileStore{dir: dir, maxSegmentBytes: maxSegmentBytes}
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), rawSegmentSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if idx, err := strconv.Atoi(name); err == nil && idx > s.index {
+			s.index = idx
+		}
+	}
+	if err := s.openSegment(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Append durably writes ev to the current segment, rolling over first when the
+// record would push the segment past its size bound.
+func (s *RawEventFileStore) Append(_ context.Context, ev *busv1.RawEvent) error {
+	var buf bytes.Buffer
+	if _, err := protodelim.MarshalTo(&buf, ev); err != nil {
+		return fmt.Errorf("marshal raw event proto: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return fmt.Errorf("raw event store is closed")
+	}
+	if s.maxSegmentBytes > 0 && s.size > 0 && s.size+int64(buf.Len()) > s.maxSegmentBytes {
+		if err := s.file.Close(); err != nil {
+			return fmt.Errorf("close segment %s: %w", s.file.Name(), err)
+		}
+		s.index++
+		if err := s.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	n, err := s.file.Write(buf.Bytes())
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("write segment %s: %w", s.file.Name(), err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("sync segment %s: %w", s.file.Name(), err)
+	}
+	return nil
+}
+
+func (s *RawEventFileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+func (s *RawEventFileStore) openSegment() error {
+	path := filepath.Join(s.dir, fmt.Sprintf("%08d%s", s.index, rawSegmentSuffix))
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open segment %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat segment %s: %w", path, err)
+	}
+	s.file = f
+	s.size = info.Size()
+	return nil
+}
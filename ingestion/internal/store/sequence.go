@@ -0,0 +1,31 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	redis "github.com/redis/go-redis/v9"
+)
+
+// SequenceStore hands out signal sequence numbers per influencer+market. Each
+// pair has its own counter key incremented with INCR, so numbers start at 1
+// and stay monotonic across restarts and shard handoffs.
+type SequenceStore struct {
+	client *redis.Client
+	prefix string
+}
+
+func NewSequenceStore(client *redis.Client, prefix string) *SequenceStore {
+	return &SequenceStore{client: client, prefix: prefix}
+}
+
+// Next returns the next sequence number for influencer+market.
+func (s *SequenceStore) Next(ctx context.Context, influencer, market string) (int64, error) {
+	key := s.prefix + ":" + strings.ToLower(influencer) + ":" + market
+	seq, err := s.client.Incr(ctx, key).Result()
+	if err != nil {
+		return 0, fmt.Errorf("redis INCR %s: %w", key, err)
+	}
+	return seq, nil
+}
@@ -0,0 +1,565 @@
+package store
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	outboxSegmentSuffix  = ".wal"
+	outboxCheckpointFile = "checkpoint"
+	outboxDeadLetterDir  = "dead-letters"
+
+	// outboxFrameHeader is the marker, format version, body length and
+	// checksum in front of every record.
+	outboxFrameHeader = 13
+	// outboxRecordVersion is the format of the record bodies written.
+	outboxRecordVersion = 1
+	// outboxMaxTraceField bounds the traceparent and tracestate of a record.
+	outboxMaxTraceField = 8 << 10
+	// outboxMaxRecord bounds the body length accepted when decoding, so a
+	// corrupt length is not allocated.
+	outboxMaxRecord = 4 << 20
+	// outboxScanChunk is how much of a segment is searched at a time for the
+	// next record marker after corruption.
+	outboxScanChunk = 64 << 10
+)
+
+// outboxMarker starts every record. Bytes at a record boundary that do not
+// start with it are corrupt.
+var outboxMarker = []byte{0xfe, 0xed, 0xfa, 0xce}
+
+var outboxChecksum = crc32.MakeTable(crc32.Castagnoli)
+
+// OutboxPosition addresses a record boundary in the outbox: a byte offset
+// within a segment.
+type OutboxPosition struct {
+	Segment int
+	Offset  int64
+}
+
+func (p OutboxPosition) before(o OutboxPosition) bool {
+	return p.Segment < o.Segment || (p.Segment == o.Segment && p.Offset < o.Offset)
+}
+
+// OutboxRecord is a pending signal and the trace it was appended in.
+type OutboxRecord struct {
+	Signal *busv1.Signal
+	Trace  trace.SpanContext
+}
+
+// OutboxDeadLetter is a corrupt range of a segment that Pending skipped and
+// copied to Path.
+type OutboxDeadLetter struct {
+	Position OutboxPosition
+	Bytes    int64
+	Path     string
+	Reason   string
+}
+
+// OutboxBatch is a run of pending records in append order. Next is the
+// position just past them and any dead letters, to be passed to Ack once the
+// records are delivered.
+type OutboxBatch struct {
+	Records     []OutboxRecord
+	DeadLetters []OutboxDeadLetter
+	Next        OutboxPosition
+}
+
+// SignalOutbox is a write-ahead log of signals awaiting delivery to Kafka.
+// Signals are appended to size-bounded segment files and synced before Append
+// returns. Each record is framed by a marker, the format version, its length
+// and a CRC-32C; the frame lets a reader find the next intact record after a
+// corrupt one. A version 1 body holds the length-prefixed traceparent and
+// tracestate of the appending span (empty without one) followed by the
+// length-delimited Signal proto. A checkpoint file records the position up to
+// which signals were acknowledged; segments wholly before it are deleted.
+type SignalOutbox struct {
+	dir             string
+	maxSegmentBytes int64
+	notify          chan struct{}
+
+	mu    sync.Mutex
+	file  *os.File
+	index int
+	size  int64
+	acked OutboxPosition
+}
+
+// NewSignalOutbox opens (or creates) the outbox directory, drops a torn record
+// left at the end of the newest segment by a crash and resumes appending to
+// it. Unacknowledged signals from a previous run stay pending; corruption
+// followed by intact records is left for Pending to dead-letter.
+func NewSignalOutbox(dir string, maxSegmentBytes int64) (*SignalOutbox, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("signal outbox directory is not configured")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create signal outbox directory %s: %w", dir, err)
+	}
+	segments, err := outboxSegments(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	o := &SignalOutbox{dir: dir, maxSegmentBytes: maxSegmentBytes, notify: make(chan struct{}, 1)}
+	if len(segments) > 0 {
+		o.index = segments[len(segments)-1]
+		if err := repairSegment(o.segmentPath(o.index)); err != nil {
+			return nil, err
+		}
+	}
+	o.acked, err = o.readCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+	if len(segments) > 0 && o.acked.Segment < segments[0] {
+		o.acked = OutboxPosition{Segment: segments[0]}
+	}
+	if err := o.openSegment(); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// Append durably writes sig with the trace of ctx to the outbox, rolling over
+// to a new segment when the record would push the current one past its size
+// bound.
+func (o *SignalOutbox) Append(ctx context.Context, sig *busv1.Signal) error {
+	traceparent, tracestate := messaging.EncodeTrace(trace.SpanContextFromContext(ctx))
+	var body bytes.Buffer
+	body.Write(binary.AppendUvarint(nil, uint64(len(traceparent))))
+	body.WriteString(traceparent)
+	body.Write(binary.AppendUvarint(nil, uint64(len(tracestate))))
+	body.WriteString(tracestate)
+	if _, err := protodelim.MarshalTo(&body, sig); err != nil {
+		return fmt.Errorf("marshal signal proto: %w", err)
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, outboxFrameHeader+body.Len()))
+	buf.Write(outboxMarker)
+	buf.WriteByte(outboxRecordVersion)
+	buf.Write(binary.BigEndian.AppendUint32(nil, uint32(body.Len())))
+	sum := crc32.Update(crc32.Checksum([]byte{outboxRecordVersion}, outboxChecksum), outboxChecksum, body.Bytes())
+	buf.Write(binary.BigEndian.AppendUint32(nil, sum))
+	buf.Write(body.Bytes())
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.file == nil {
+		return fmt.Errorf("signal outbox is closed")
+	}
+	if o.maxSegmentBytes > 0 && o.size > 0 && o.size+int64(buf.Len()) > o.maxSegmentBytes {
+		if err := o.file.Close(); err != nil {
+			return fmt.Errorf("close segment %s: %w", o.file.Name(), err)
+		}
+		o.index++
+		if err := o.openSegment(); err != nil {
+			return err
+		}
+	}
+
+	if _, err := o.file.Write(buf.Bytes()); err != nil {
+		// Drop a partial record so later appends and reads stay aligned.
+		_ = o.file.Truncate(o.size)
+		return fmt.Errorf("write segment %s: %w", o.file.Name(), err)
+	}
+	o.size += int64(buf.Len())
+	if err := o.file.Sync(); err != nil {
+		return fmt.Errorf("sync segment %s: %w", o.file.Name(), err)
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Notify returns a channel that receives after signals are appended.
+func (o *SignalOutbox) Notify() <-chan struct{} {
+	return o.notify
+}
+
+// Pending reads up to max unacknowledged records in append order. Corrupt
+// ranges met on the way, such as a record whose checksum or proto does not
+// verify, are copied to the dead-letter directory and skipped, so they cannot
+// stall delivery. It returns an empty batch when the outbox is drained.
+func (o *SignalOutbox) Pending(max int) (OutboxBatch, error) {
+	o.mu.Lock()
+	pos, head := o.acked, OutboxPosition{Segment: o.index, Offset: o.size}
+	o.mu.Unlock()
+
+	var batch OutboxBatch
+	for len(batch.Records) < max && pos.before(head) {
+		limit := head.Offset
+		if pos.Segment < head.Segment {
+			limit = -1
+		}
+		recs, dead, next, err := o.readSegment(pos, limit, max-len(batch.Records))
+		if err != nil {
+			return OutboxBatch{}, err
+		}
+		batch.Records = append(batch.Records, recs...)
+		batch.DeadLetters = append(batch.DeadLetters, dead...)
+		if next == pos && len(recs) == 0 {
+			// End of a rolled-over segment.
+			next = OutboxPosition{Segment: pos.Segment + 1}
+		}
+		pos = next
+	}
+	batch.Next = pos
+	return batch, nil
+}
+
+// Ack records that every signal before pos was delivered and deletes the
+// segments that no longer hold pending signals.
+func (o *SignalOutbox) Ack(pos OutboxPosition) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if !o.acked.before(pos) {
+		return nil
+	}
+	if err := o.writeCheckpoint(pos); err != nil {
+		return err
+	}
+	for seg := o.acked.Segment; seg < pos.Segment; seg++ {
+		if err := os.Remove(o.segmentPath(seg)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove segment %d: %w", seg, err)
+		}
+	}
+	o.acked = pos
+	return nil
+}
+
+func (o *SignalOutbox) Close() error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.file == nil {
+		return nil
+	}
+	err := o.file.Close()
+	o.file = nil
+	return err
+}
+
+// readSegment decodes up to max records of segment pos.Segment starting at
+// pos.Offset, stopping at limit bytes (-1 reads to the end of the file).
+// Corrupt ranges are dead-lettered and skipped up to the next intact record.
+func (o *SignalOutbox) readSegment(pos OutboxPosition, limit int64, max int) ([]OutboxRecord, []OutboxDeadLetter, OutboxPosition, error) {
+	f, err := os.Open(o.segmentPath(pos.Segment))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) && limit < 0 {
+			return nil, nil, pos, nil
+		}
+		return nil, nil, pos, fmt.Errorf("open segment %d: %w", pos.Segment, err)
+	}
+	defer f.Close()
+	if limit < 0 {
+		info, err := f.Stat()
+		if err != nil {
+			return nil, nil, pos, fmt.Errorf("stat segment %d: %w", pos.Segment, err)
+		}
+		limit = info.Size()
+	}
+
+	var (
+		recs []OutboxRecord
+		dead []OutboxDeadLetter
+	)
+	for len(recs) < max && pos.Offset < limit {
+		rec, n, err := decodeOutboxRecord(f, pos.Offset, limit)
+		if err == nil {
+			recs = append(recs, rec)
+			pos.Offset += n
+			continue
+		}
+		next, serr := resyncOutbox(f, pos.Offset+1, limit)
+		if serr != nil {
+			return nil, nil, pos, fmt.Errorf("scan segment %d: %w", pos.Segment, serr)
+		}
+		dl, derr := o.deadLetter(f, pos, next, err)
+		if derr != nil {
+			return nil, nil, pos, derr
+		}
+		dead = append(dead, dl)
+		pos.Offset = next
+	}
+	return recs, dead, pos, nil
+}
+
+// deadLetter copies the segment bytes from pos up to end to a file of the
+// dead-letter directory named after pos, replacing an earlier copy.
+func (o *SignalOutbox) deadLetter(r io.ReaderAt, pos OutboxPosition, end int64, reason error) (OutboxDeadLetter, error) {
+	dir := filepath.Join(o.dir, outboxDeadLetterDir)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return OutboxDeadLetter{}, fmt.Errorf("create outbox dead-letter directory: %w", err)
+	}
+	dl := OutboxDeadLetter{
+		Position: pos,
+		Bytes:    end - pos.Offset,
+		Path:     filepath.Join(dir, fmt.Sprintf("%08d-%d.rec", pos.Segment, pos.Offset)),
+		Reason:   reason.Error(),
+	}
+	f, err := os.Create(dl.Path)
+	if err != nil {
+		return OutboxDeadLetter{}, fmt.Errorf("write outbox dead letter: %w", err)
+	}
+	if _, err := io.Copy(f, io.NewSectionReader(r, pos.Offset, dl.Bytes)); err != nil {
+		_ = f.Close()
+		return OutboxDeadLetter{}, fmt.Errorf("write outbox dead letter: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return OutboxDeadLetter{}, fmt.Errorf("sync outbox dead letter: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return OutboxDeadLetter{}, fmt.Errorf("close outbox dead letter: %w", err)
+	}
+	return dl, nil
+}
+
+func (o *SignalOutbox) segmentPath(index int) string {
+	return filepath.Join(o.dir, fmt.Sprintf("%08d%s", index, outboxSegmentSuffix))
+}
+
+func (o *SignalOutbox) openSegment() error {
+	path := o.segmentPath(o.index)
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("open segment %s: %w", path, err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return fmt.Errorf("stat segment %s: %w", path, err)
+	}
+	o.file = f
+	o.size = info.Size()
+	return nil
+}
+
+func (o *SignalOutbox) readCheckpoint() (OutboxPosition, error) {
+	raw, err := os.ReadFile(filepath.Join(o.dir, outboxCheckpointFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return OutboxPosition{}, nil
+	}
+	if err != nil {
+		return OutboxPosition{}, fmt.Errorf("read outbox checkpoint: %w", err)
+	}
+	segRaw, offRaw, ok := strings.Cut(strings.TrimSpace(string(raw)), " ")
+	seg, segErr := strconv.Atoi(segRaw)
+	off, offErr := strconv.ParseInt(offRaw, 10, 64)
+	if !ok || segErr != nil || offErr != nil {
+		return OutboxPosition{}, fmt.Errorf("invalid outbox checkpoint %q", raw)
+	}
+	return OutboxPosition{Segment: seg, Offset: off}, nil
+}
+
+// writeCheckpoint replaces the checkpoint file atomically.
+func (o *SignalOutbox) writeCheckpoint(pos OutboxPosition) error {
+	path := filepath.Join(o.dir, outboxCheckpointFile)
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return fmt.Errorf("write outbox checkpoint: %w", err)
+	}
+	if _, err := fmt.Fprintf(f, "%d %d\n", pos.Segment, pos.Offset); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("write outbox checkpoint: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return fmt.Errorf("sync outbox checkpoint: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close outbox checkpoint: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("replace outbox checkpoint: %w", err)
+	}
+	return nil
+}
+
+// outboxSegments lists the segment indexes in dir in ascending order.
+func outboxSegments(dir string) ([]int, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read signal outbox directory %s: %w", dir, err)
+	}
+	var out []int
+	for _, e := range entries {
+		name, ok := strings.CutSuffix(e.Name(), outboxSegmentSuffix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		if idx, err := strconv.Atoi(name); err == nil {
+			out = append(out, idx)
+		}
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+// repairSegment truncates path after its last intact record. Corruption
+// followed by intact records is not a torn append and is kept.
+func repairSegment(path string) error {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("open segment %s: %w", path, err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("stat segment %s: %w", path, err)
+	}
+
+	size := info.Size()
+	var off, valid int64
+	for off < size {
+		_, n, err := decodeOutboxRecord(f, off, size)
+		if err == nil {
+			off += n
+			valid = off
+			continue
+		}
+		if off, err = resyncOutbox(f, off+1, size); err != nil {
+			return fmt.Errorf("scan segment %s: %w", path, err)
+		}
+	}
+	if valid == size {
+		return nil
+	}
+	if err := f.Truncate(valid); err != nil {
+		return fmt.Errorf("truncate torn record in %s: %w", path, err)
+	}
+	return f.Sync()
+}
+
+// decodeOutboxRecord decodes the record at off, which must end by limit, and
+// returns its length.
+func decodeOutboxRecord(r io.ReaderAt, off, limit int64) (OutboxRecord, int64, error) {
+	header := make([]byte, min(outboxFrameHeader, limit-off))
+	if _, err := r.ReadAt(header, off); err != nil {
+		return OutboxRecord{}, 0, err
+	}
+	if !bytes.HasPrefix(header, outboxMarker) {
+		return OutboxRecord{}, 0, errors.New("missing record marker")
+	}
+	if len(header) < outboxFrameHeader {
+		return OutboxRecord{}, 0, io.ErrUnexpectedEOF
+	}
+	version := header[4]
+	size := int64(binary.BigEndian.Uint32(header[5:9]))
+	if size > outboxMaxRecord {
+		return OutboxRecord{}, 0, fmt.Errorf("record of %d bytes", size)
+	}
+	if off+outboxFrameHeader+size > limit {
+		return OutboxRecord{}, 0, io.ErrUnexpectedEOF
+	}
+	body := make([]byte, size)
+	if _, err := r.ReadAt(body, off+outboxFrameHeader); err != nil {
+		return OutboxRecord{}, 0, err
+	}
+	sum := crc32.Update(crc32.Checksum(header[4:5], outboxChecksum), outboxChecksum, body)
+	if sum != binary.BigEndian.Uint32(header[9:13]) {
+		return OutboxRecord{}, 0, errors.New("record checksum mismatch")
+	}
+	if version != outboxRecordVersion {
+		return OutboxRecord{}, 0, fmt.Errorf("unsupported record version %d", version)
+	}
+	rec, err := decodeOutboxBody(body)
+	if err != nil {
+		return OutboxRecord{}, 0, err
+	}
+	return rec, outboxFrameHeader + size, nil
+}
+
+// decodeOutboxBody decodes a version 1 record body.
+func decodeOutboxBody(b []byte) (OutboxRecord, error) {
+	traceparent, b, err := outboxField(b, outboxMaxTraceField)
+	if err != nil {
+		return OutboxRecord{}, fmt.Errorf("traceparent: %w", err)
+	}
+	tracestate, b, err := outboxField(b, outboxMaxTraceField)
+	if err != nil {
+		return OutboxRecord{}, fmt.Errorf("tracestate: %w", err)
+	}
+	sig, b, err := outboxField(b, outboxMaxRecord)
+	if err != nil {
+		return OutboxRecord{}, fmt.Errorf("signal: %w", err)
+	}
+	if len(b) > 0 {
+		return OutboxRecord{}, fmt.Errorf("%d trailing bytes in record", len(b))
+	}
+	rec := OutboxRecord{Signal: &busv1.Signal{}}
+	if err := proto.Unmarshal(sig, rec.Signal); err != nil {
+		return OutboxRecord{}, fmt.Errorf("unmarshal signal proto: %w", err)
+	}
+	// A malformed traceparent only loses the trace link.
+	rec.Trace = messaging.DecodeTrace(string(traceparent), string(tracestate))
+	return rec, nil
+}
+
+// outboxField splits the uvarint length-prefixed field at the start of b,
+// of at most max bytes, from the rest.
+func outboxField(b []byte, max uint64) (field, rest []byte, err error) {
+	n, k := binary.Uvarint(b)
+	if k <= 0 {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	if n > max {
+		return nil, nil, fmt.Errorf("field of %d bytes", n)
+	}
+	if uint64(len(b)-k) < n {
+		return nil, nil, io.ErrUnexpectedEOF
+	}
+	return b[k : k+int(n)], b[k+int(n):], nil
+}
+
+// resyncOutbox returns the offset of the first intact framed record at or
+// after from, or limit when there is none.
+func resyncOutbox(r io.ReaderAt, from, limit int64) (int64, error) {
+	buf := make([]byte, outboxScanChunk+len(outboxMarker)-1)
+	for from < limit {
+		chunk := buf[:min(int64(len(buf)), limit-from)]
+		if _, err := r.ReadAt(chunk, from); err != nil {
+			return 0, err
+		}
+		for i := 0; ; {
+			j := bytes.Index(chunk[i:], outboxMarker)
+			if j < 0 {
+				break
+			}
+			candidate := from + int64(i+j)
+			if _, _, err := decodeOutboxRecord(r, candidate, limit); err == nil {
+				return candidate, nil
+			}
+			i += j + 1
+		}
+		if int64(len(chunk)) < int64(len(buf)) {
+			break
+		}
+		// Overlap the chunks so a marker across their boundary is found.
+		from += int64(len(chunk) - len(outboxMarker) + 1)
+	}
+	return limit, nil
+}
@@ -0,0 +1,267 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func openTestOutbox(t *testing.T, dir string, maxSegmentBytes int64) *SignalOutbox {
+	t.Helper()
+	o, err := NewSignalOutbox(dir, maxSegmentBytes)
+	if err != nil {
+		t.Fatalf("NewSignalOutbox: %v", err)
+	}
+	t.Cleanup(func() { _ = o.Close() })
+	return o
+}
+
+func appendSignals(t *testing.T, o *SignalOutbox, ids ...string) {
+	t.Helper()
+	for _, id := range ids {
+		if err := o.Append(context.Background(), &busv1.Signal{SignalId: id}); err != nil {
+			t.Fatalf("Append %s: %v", id, err)
+		}
+	}
+}
+
+func pendingIDs(t *testing.T, o *SignalOutbox, max int) ([]string, OutboxBatch) {
+	t.Helper()
+	batch, err := o.Pending(max)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	ids := make([]string, len(batch.Records))
+	for i, rec := range batch.Records {
+		ids[i] = rec.Signal.GetSignalId()
+	}
+	return ids, batch
+}
+
+func TestSignalOutboxAppendAndPending(t *testing.T) {
+	o := openTestOutbox(t, t.TempDir(), 0)
+	state, err := trace.ParseTraceState("vendor=value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{TraceID: trace.TraceID{1}, SpanID: trace.SpanID{2}, TraceFlags: trace.FlagsSampled, TraceState: state})
+	if err := o.Append(trace.ContextWithSpanContext(context.Background(), sc), &busv1.Signal{SignalId: "sig-1"}); err != nil {
+		t.Fatalf("Append: %v", err)
+	}
+	appendSignals(t, o, "sig-2", "sig-3")
+
+	select {
+	case <-o.Notify():
+	default:
+		t.Fatal("Append did not notify")
+	}
+
+	ids, batch := pendingIDs(t, o, 2)
+	if !slices.Equal(ids, []string{"sig-1", "sig-2"}) {
+		t.Fatalf("pending = %v, want the first two signals", ids)
+	}
+	if got := batch.Records[0].Trace; !got.Equal(sc.WithRemote(true)) || batch.Records[1].Trace.IsValid() {
+		t.Fatalf("traces = %v, %v; want the appending span only on the first", batch.Records[0].Trace, batch.Records[1].Trace)
+	}
+	// Pending does not consume: the same batch is returned until acknowledged.
+	if again, _ := pendingIDs(t, o, 2); !slices.Equal(again, ids) {
+		t.Fatalf("pending again = %v, want %v", again, ids)
+	}
+}
+
+func TestSignalOutboxRolloverAndAck(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 40)
+	appendSignals(t, o, "sig-1", "sig-2", "sig-3", "sig-4", "sig-5")
+	segments, err := outboxSegments(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(segments) < 3 {
+		t.Fatalf("segments = %v, want a rollover every couple of records", segments)
+	}
+
+	ids, batch := pendingIDs(t, o, 3)
+	if !slices.Equal(ids, []string{"sig-1", "sig-2", "sig-3"}) {
+		t.Fatalf("pending across segments = %v", ids)
+	}
+	if err := o.Ack(batch.Next); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if remaining, _ := outboxSegments(dir); remaining[0] != batch.Next.Segment {
+		t.Fatalf("segments after ack = %v, want those before segment %d removed", remaining, batch.Next.Segment)
+	}
+	if ids, _ := pendingIDs(t, o, 10); !slices.Equal(ids, []string{"sig-4", "sig-5"}) {
+		t.Fatalf("pending after ack = %v", ids)
+	}
+}
+
+func TestSignalOutboxResumesAfterRestart(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 0)
+	appendSignals(t, o, "sig-1", "sig-2")
+	_, batch := pendingIDs(t, o, 1)
+	if err := o.Ack(batch.Next); err != nil {
+		t.Fatalf("Ack: %v", err)
+	}
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openTestOutbox(t, dir, 0)
+	appendSignals(t, reopened, "sig-3")
+	if ids, _ := pendingIDs(t, reopened, 10); !slices.Equal(ids, []string{"sig-2", "sig-3"}) {
+		t.Fatalf("pending after restart = %v, want the unacknowledged signal and the new one", ids)
+	}
+}
+
+func TestSignalOutboxRepairsTornTail(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 0)
+	appendSignals(t, o, "sig-1", "sig-2")
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+	path := o.segmentPath(0)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A crash mid-append leaves part of a record behind.
+	appendBytes(t, path, append(append([]byte{}, outboxMarker...), outboxRecordVersion, 0, 0, 0, 40, 1, 2))
+
+	reopened := openTestOutbox(t, dir, 0)
+	if after, _ := os.Stat(path); after.Size() != info.Size() {
+		t.Fatalf("segment is %d bytes after repair, want %d", after.Size(), info.Size())
+	}
+	appendSignals(t, reopened, "sig-3")
+	ids, batch := pendingIDs(t, reopened, 10)
+	if !slices.Equal(ids, []string{"sig-1", "sig-2", "sig-3"}) || len(batch.DeadLetters) != 0 {
+		t.Fatalf("pending after repair = %v, dead letters %v", ids, batch.DeadLetters)
+	}
+}
+
+func TestSignalOutboxDeadLettersCorruptRecords(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 0)
+	appendSignals(t, o, "sig-1", "sig-2", "sig-3")
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Flip a byte in the body of the second record.
+	path := o.segmentPath(0)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := recordOffsets(t, raw)[1]
+	raw[second+outboxFrameHeader+2] ^= 0xff
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The corruption is followed by an intact record, so it is not a torn
+	// tail and startup keeps it.
+	reopened := openTestOutbox(t, dir, 0)
+	if after, _ := os.Stat(path); after.Size() != int64(len(raw)) {
+		t.Fatalf("startup truncated the segment to %d bytes", after.Size())
+	}
+	ids, batch := pendingIDs(t, reopened, 10)
+	if !slices.Equal(ids, []string{"sig-1", "sig-3"}) {
+		t.Fatalf("pending = %v, want the records around the corrupt one", ids)
+	}
+	if len(batch.DeadLetters) != 1 || batch.DeadLetters[0].Position.Offset != int64(second) {
+		t.Fatalf("dead letters = %v, want the record at offset %d", batch.DeadLetters, second)
+	}
+	dead, err := os.ReadFile(batch.DeadLetters[0].Path)
+	if err != nil || int64(len(dead)) != batch.DeadLetters[0].Bytes {
+		t.Fatalf("dead-letter file: %d bytes, %v", len(dead), err)
+	}
+	if err := reopened.Ack(batch.Next); err != nil {
+		t.Fatal(err)
+	}
+	if ids, batch := pendingIDs(t, reopened, 10); len(ids) != 0 || len(batch.DeadLetters) != 0 {
+		t.Fatalf("pending after ack = %v, %v", ids, batch.DeadLetters)
+	}
+}
+
+func TestSignalOutboxDeadLettersUnknownVersions(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 0)
+	appendSignals(t, o, "sig-1", "sig-2")
+	if err := o.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Rewrite the first record as a version this build does not know, with a
+	// matching checksum.
+	path := o.segmentPath(0)
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := recordOffsets(t, raw)[1]
+	raw[4] = outboxRecordVersion + 1
+	sum := crc32.Update(crc32.Checksum(raw[4:5], outboxChecksum), outboxChecksum, raw[outboxFrameHeader:second])
+	binary.BigEndian.PutUint32(raw[9:13], sum)
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened := openTestOutbox(t, dir, 0)
+	ids, batch := pendingIDs(t, reopened, 10)
+	if !slices.Equal(ids, []string{"sig-2"}) || len(batch.DeadLetters) != 1 || !strings.Contains(batch.DeadLetters[0].Reason, "version") {
+		t.Fatalf("pending = %v, dead letters %v; want the unknown version dead-lettered", ids, batch.DeadLetters)
+	}
+}
+
+func TestSignalOutboxSkipsPoisonInRolledSegment(t *testing.T) {
+	dir := t.TempDir()
+	o := openTestOutbox(t, dir, 40)
+	appendSignals(t, o, "sig-1", "sig-2", "sig-3")
+	// Garbage that does not even frame a record, in a segment no longer
+	// appended to.
+	if err := os.WriteFile(o.segmentPath(0), []byte("not a record"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ids, batch := pendingIDs(t, o, 10)
+	if slices.Contains(ids, "sig-1") || !slices.Contains(ids, "sig-3") {
+		t.Fatalf("pending = %v, want every record after the poison segment", ids)
+	}
+	if len(batch.DeadLetters) != 1 || batch.DeadLetters[0].Position != (OutboxPosition{}) {
+		t.Fatalf("dead letters = %v", batch.DeadLetters)
+	}
+}
+
+// recordOffsets returns the offsets of the framed records in a segment.
+func recordOffsets(t *testing.T, raw []byte) []int {
+	t.Helper()
+	var out []int
+	for off := 0; off < len(raw); {
+		if len(raw)-off < outboxFrameHeader || string(raw[off:off+4]) != string(outboxMarker) {
+			t.Fatalf("no record frame at offset %d", off)
+		}
+		out = append(out, off)
+		off += outboxFrameHeader + int(binary.BigEndian.Uint32(raw[off+5:off+9]))
+	}
+	return out
+}
+
+func appendBytes(t *testing.T, path string, b []byte) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write(b); err != nil {
+		t.Fatal(err)
+	}
+}
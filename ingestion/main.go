@@ -2,29 +2,56 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
 
 	service "github.com/0xRichardL/vibe-copy-trading/ingestion/internal"
 	"github.com/0xRichardL/vibe-copy-trading/ingestion/internal/config"
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
 )
 
 func main() {
-	logger := log.New(os.Stdout, "ingestion ", log.LstdFlags|log.Lmicroseconds|log.Lshortfile)
-
 	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
 	defer cancel()
 
 	cfg, err := config.LoadConfig()
+	if errors.Is(err, libconfig.ErrHelp) {
+		return
+	}
+	if err != nil {
+		fatal(slog.Default(), "failed to load config", err)
+	}
+
+	level := new(slog.LevelVar)
+	logger, err := observability.NewLogger(os.Stdout, observability.LoggingConfig{
+		ServiceName:     "ingestion",
+		ServiceInstance: cfg.InstanceID,
+		Level:           cfg.LogLevel,
+		LevelVar:        level,
+	})
 	if err != nil {
-		logger.Fatalf("failed to load config: %v", err)
+		fatal(slog.Default(), "failed to build logger", err)
+	}
+	slog.SetDefault(logger)
+	if effective, err := libconfig.Redacted(&cfg); err == nil {
+		logger.Info("effective config", slog.Any("config", effective))
 	}
 
-	app := service.NewApp(cfg, logger)
+	app, err := service.NewApp(cfg, logger, level)
+	if err != nil {
+		fatal(logger, "failed to build app", err)
+	}
 
 	if err := app.Run(ctx); err != nil {
-		logger.Fatalf("service exited with error: %v", err)
+		fatal(logger, "service exited with error", err)
 	}
 }
+
+func fatal(logger *slog.Logger, msg string, err error) {
+	logger.Error(msg, observability.Err(err))
+	os.Exit(1)
+}
@@ -0,0 +1,200 @@
+// Package config loads service configuration into tagged structs. Values are
+// layered, later sources overriding earlier ones:
+//
+//  1. `default:"..."` struct tags,
+//  2. a YAML file (-config flag, CONFIG_FILE, or Options.File),
+//  3. environment variables,
+//  4. command-line flags.
+//
+// Every field with an `env:"NAME"` tag is configurable. Its YAML key is the
+// lowercased name (REDIS_ADDR → redis_addr) and its flag the lowercased name
+// with dashes (--redis-addr). Fields tagged `secret:"true"` may also be read
+// from the file named by NAME_FILE and are redacted by Redacted. After
+// loading, `validate:"..."` rules are checked and all failures are returned
+// together as a *ValidationError.
+//
+// Supported field types are string, bool, int, int64, float64,
+// time.Duration and []string (comma-separated in env and flags). Untagged
+// struct fields are descended into.
+//
+// Settings that may change without a restart go in a separate struct kept
+// current by a Reloader from a FileSource or RedisSource. Their fields are
+// named with a `key:"name"` tag instead of env; Decode applies the same
+// default and validate tags to a key/value map.
+package config
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ErrHelp is returned by Load when the arguments ask for help (-h or -help).
+// The flag usage has been printed by then, so callers should exit cleanly.
+var ErrHelp = flag.ErrHelp
+
+// FileEnv and FileFlag name the YAML file to load.
+const (
+	FileEnv  = "CONFIG_FILE"
+	FileFlag = "config"
+)
+
+// Options configures Load.
+type Options struct {
+	// File is the YAML file loaded when neither the -config flag nor
+	// CONFIG_FILE is set. Empty skips the file layer.
+	File string
+	// Args are the command-line arguments, usually os.Args[1:]. Nil skips the
+	// flag layer.
+	Args []string
+	// LookupEnv reads environment variables; defaults to os.LookupEnv.
+	LookupEnv func(string) (string, bool)
+	// Output receives the flag usage printed for -h and flag errors; defaults
+	// to os.Stderr.
+	Output io.Writer
+}
+
+// Load fills the struct dst points to from its tag defaults, the YAML file,
+// the environment and flags, then validates it. It returns ErrHelp, after
+// printing the flag usage, when the arguments ask for help.
+func Load(dst any, opts Options) error {
+	fields, err := collectFields(dst)
+	if err != nil {
+		return err
+	}
+	lookup := opts.LookupEnv
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	flagValues, file, err := parseFlags(fields, opts.Args, opts.Output)
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		file, _ = lookup(FileEnv)
+	}
+	if file == "" {
+		file = opts.File
+	}
+
+	for _, f := range fields {
+		if def, ok := f.field.Tag.Lookup("default"); ok {
+			if err := f.set(def); err != nil {
+				return fmt.Errorf("default for %s: %w", f.env, err)
+			}
+		}
+	}
+	if file != "" {
+		if err := loadFile(fields, file); err != nil {
+			return err
+		}
+	}
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if raw, ok := lookup(f.env); ok && raw != "" {
+			if err := f.set(raw); err != nil {
+				return fmt.Errorf("env %s: %w", f.env, err)
+			}
+		}
+		if !f.secret {
+			continue
+		}
+		if path, ok := lookup(f.env + "_FILE"); ok && path != "" {
+			secret, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read %s_FILE: %w", f.env, err)
+			}
+			if err := f.set(strings.TrimSpace(string(secret))); err != nil {
+				return fmt.Errorf("%s_FILE: %w", f.env, err)
+			}
+		}
+	}
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		if raw, ok := flagValues[f.flagName()]; ok {
+			if err := f.set(raw); err != nil {
+				return fmt.Errorf("flag --%s: %w", f.flagName(), err)
+			}
+		}
+	}
+
+	return validate(fields)
+}
+
+// parseFlags returns the explicitly set flags by name and the -config value.
+// Usage and parse errors are printed to output.
+func parseFlags(fields []field, args []string, output io.Writer) (map[string]string, string, error) {
+	if args == nil {
+		return nil, "", nil
+	}
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	if output != nil {
+		fs.SetOutput(output)
+	}
+	file := fs.String(FileFlag, "", "YAML config file")
+	for _, f := range fields {
+		if f.env == "" {
+			continue
+		}
+		usage := f.env
+		if def, ok := f.field.Tag.Lookup("default"); ok {
+			usage += " (default " + def + ")"
+		}
+		fs.String(f.flagName(), "", usage)
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, "", ErrHelp
+		}
+		return nil, "", fmt.Errorf("parse flags: %w", err)
+	}
+	values := make(map[string]string)
+	fs.Visit(func(fl *flag.Flag) {
+		if fl.Name != FileFlag {
+			values[fl.Name] = fl.Value.String()
+		}
+	})
+	return values, *file, nil
+}
+
+// loadFile applies the keys of a flat YAML mapping. Unknown keys are errors so
+// typos do not go unnoticed.
+func loadFile(fields []field, path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	byKey := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byKey[f.yamlKey()] = f
+	}
+	var errs []error
+	for key, node := range doc {
+		f, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", key))
+			continue
+		}
+		if err := f.decode(&node); err != nil {
+			errs = append(errs, fmt.Errorf("key %s: %w", key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("config file %s: %w", path, errors.Join(errs...))
+	}
+	return nil
+}
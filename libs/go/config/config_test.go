@@ -0,0 +1,297 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testConfig struct {
+	Addr     string        `env:"TEST_ADDR" default:"localhost:8080" validate:"required"`
+	Password string        `env:"TEST_PASSWORD" secret:"true"`
+	Token    string        `env:"TEST_TOKEN"`
+	Mode     string        `env:"TEST_MODE" default:"queue" validate:"oneof=queue hash"`
+	Workers  int           `env:"TEST_WORKERS" default:"4" validate:"min=1,max=64"`
+	Timeout  time.Duration `env:"TEST_TIMEOUT" default:"5s" validate:"min=100ms"`
+	Brokers  []string      `env:"TEST_BROKERS" default:"a:9092" validate:"min=1"`
+	Ratio    float64       `env:"TEST_RATIO" default:"0.5" validate:"max=1"`
+	Debug    bool          `env:"TEST_DEBUG"`
+	Derived  int           `env:"-"`
+	Nested   struct {
+		Region string `env:"TEST_REGION" default:"eu"`
+	}
+}
+
+func envOf(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", "test_addr: file:1\ntest_workers: 8\ntest_timeout: 30s\ntest_brokers: [b:9092, c:9092]\n")
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		opts Options
+		want func(*testConfig) bool
+	}{
+		{
+			name: "defaults",
+			want: func(c *testConfig) bool {
+				return c.Addr == "localhost:8080" && c.Workers == 4 && c.Timeout == 5*time.Second &&
+					slices.Equal(c.Brokers, []string{"a:9092"}) && c.Nested.Region == "eu"
+			},
+		},
+		{
+			name: "file over defaults",
+			opts: Options{File: file},
+			want: func(c *testConfig) bool {
+				return c.Addr == "file:1" && c.Workers == 8 && c.Timeout == 30*time.Second &&
+					slices.Equal(c.Brokers, []string{"b:9092", "c:9092"}) && c.Mode == "queue"
+			},
+		},
+		{
+			name: "env over file",
+			opts: Options{File: file},
+			env:  map[string]string{"TEST_ADDR": "env:1", "TEST_BROKERS": "d:9092, e:9092", "TEST_REGION": "us"},
+			want: func(c *testConfig) bool {
+				return c.Addr == "env:1" && c.Workers == 8 && slices.Equal(c.Brokers, []string{"d:9092", "e:9092"}) && c.Nested.Region == "us"
+			},
+		},
+		{
+			name: "empty env values are ignored",
+			opts: Options{File: file},
+			env:  map[string]string{"TEST_ADDR": ""},
+			want: func(c *testConfig) bool { return c.Addr == "file:1" },
+		},
+		{
+			name: "flags over env",
+			opts: Options{File: file},
+			env:  map[string]string{"TEST_ADDR": "env:1", "TEST_WORKERS": "16"},
+			args: []string{"--test-addr", "flag:1", "--test-debug=true"},
+			want: func(c *testConfig) bool { return c.Addr == "flag:1" && c.Workers == 16 && c.Debug },
+		},
+		{
+			name: "CONFIG_FILE over the default file",
+			opts: Options{File: "/does/not/exist.yaml"},
+			env:  map[string]string{FileEnv: file},
+			want: func(c *testConfig) bool { return c.Addr == "file:1" },
+		},
+		{
+			name: "-config over CONFIG_FILE",
+			env:  map[string]string{FileEnv: "/does/not/exist.yaml"},
+			args: []string{"-config", file},
+			want: func(c *testConfig) bool { return c.Addr == "file:1" },
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.LookupEnv = envOf(tt.env)
+			opts.Args = tt.args
+			var cfg testConfig
+			if err := Load(&cfg, opts); err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if !tt.want(&cfg) {
+				t.Fatalf("unexpected config: %+v", cfg)
+			}
+		})
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	secret := writeFile(t, "password", "  s3cret\n")
+
+	var cfg testConfig
+	err := Load(&cfg, Options{LookupEnv: envOf(map[string]string{
+		"TEST_PASSWORD":      "from-env",
+		"TEST_PASSWORD_FILE": secret,
+		// Only secret fields read a _FILE variant.
+		"TEST_TOKEN_FILE": secret,
+	})})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Password != "s3cret" {
+		t.Errorf("password = %q, want the trimmed file content over the env value", cfg.Password)
+	}
+	if cfg.Token != "" {
+		t.Errorf("token = %q, want non-secret fields to ignore _FILE", cfg.Token)
+	}
+
+	redacted, err := Redacted(&cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if redacted["test_password"] != RedactedValue || redacted["test_addr"] != "localhost:8080" {
+		t.Errorf("Redacted = %v", redacted)
+	}
+
+	err = Load(&testConfig{}, Options{LookupEnv: envOf(map[string]string{"TEST_PASSWORD_FILE": filepath.Join(t.TempDir(), "missing")})})
+	if err == nil || !strings.Contains(err.Error(), "TEST_PASSWORD_FILE") {
+		t.Fatalf("missing secret file: err = %v", err)
+	}
+}
+
+func TestLoadRejectsUnknownFileKeys(t *testing.T) {
+	file := writeFile(t, "config.yaml", "test_addr: file:1\ntest_adr: typo\nother: 1\n")
+	err := Load(&testConfig{}, Options{File: file, LookupEnv: envOf(nil)})
+	if err == nil {
+		t.Fatal("Load accepted unknown keys")
+	}
+	for _, key := range []string{`"test_adr"`, `"other"`} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("err = %v, want it to name %s", err, key)
+		}
+	}
+
+	bad := writeFile(t, "bad.yaml", "test_workers: many\n")
+	if err := Load(&testConfig{}, Options{File: bad, LookupEnv: envOf(nil)}); err == nil || !strings.Contains(err.Error(), "test_workers") {
+		t.Fatalf("unparsable value: err = %v", err)
+	}
+}
+
+func TestLoadHelp(t *testing.T) {
+	for _, arg := range []string{"-h", "--help"} {
+		var usage strings.Builder
+		var cfg testConfig
+		err := Load(&cfg, Options{Args: []string{arg}, LookupEnv: envOf(nil), Output: &usage})
+		if !errors.Is(err, ErrHelp) || err.Error() != ErrHelp.Error() {
+			t.Fatalf("Load(%s) = %v, want ErrHelp", arg, err)
+		}
+		if !strings.Contains(usage.String(), "-test-addr") || !strings.Contains(usage.String(), "TEST_ADDR (default localhost:8080)") {
+			t.Fatalf("Load(%s) printed %q, want the flag usage", arg, usage.String())
+		}
+	}
+}
+
+func TestLoadValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        map[string]string
+		wantFields []string
+	}{
+		{name: "valid", env: map[string]string{"TEST_MODE": "HASH", "TEST_WORKERS": "64"}},
+		{name: "oneof", env: map[string]string{"TEST_MODE": "random"}, wantFields: []string{"TEST_MODE"}},
+		{name: "int min", env: map[string]string{"TEST_WORKERS": "0"}, wantFields: []string{"TEST_WORKERS"}},
+		{name: "int max", env: map[string]string{"TEST_WORKERS": "65"}, wantFields: []string{"TEST_WORKERS"}},
+		{name: "duration min", env: map[string]string{"TEST_TIMEOUT": "10ms"}, wantFields: []string{"TEST_TIMEOUT"}},
+		{name: "float max", env: map[string]string{"TEST_RATIO": "1.5"}, wantFields: []string{"TEST_RATIO"}},
+		{
+			name:       "every failure is reported",
+			env:        map[string]string{"TEST_MODE": "random", "TEST_WORKERS": "0", "TEST_TIMEOUT": "1ms"},
+			wantFields: []string{"TEST_MODE", "TEST_WORKERS", "TEST_TIMEOUT"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var cfg testConfig
+			err := Load(&cfg, Options{LookupEnv: envOf(tt.env)})
+			if len(tt.wantFields) == 0 {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				return
+			}
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("err = %v, want a *ValidationError", err)
+			}
+			var got []string
+			for _, fe := range verr.Errors {
+				got = append(got, fe.Field)
+			}
+			if !slices.Equal(got, tt.wantFields) {
+				t.Fatalf("failed fields = %v, want %v (%v)", got, tt.wantFields, err)
+			}
+		})
+	}
+
+	// oneof takes the option's spelling.
+	var cfg testConfig
+	if err := Load(&cfg, Options{LookupEnv: envOf(map[string]string{"TEST_MODE": "HASH"})}); err != nil || cfg.Mode != "hash" {
+		t.Fatalf("mode = %q, %v; want hash", cfg.Mode, err)
+	}
+
+	// A required field left empty by every layer fails.
+	var required struct {
+		Name string `env:"TEST_NAME" validate:"required"`
+	}
+	var verr *ValidationError
+	if err := Load(&required, Options{LookupEnv: envOf(nil)}); !errors.As(err, &verr) || verr.Errors[0].Field != "TEST_NAME" {
+		t.Fatalf("required: err = %v", err)
+	}
+}
+
+type testRuntime struct {
+	MaxStreams int           `key:"max_streams" default:"0" validate:"min=0"`
+	Timeout    time.Duration `key:"publish_timeout" default:"5s" validate:"min=100ms"`
+	LogLevel   string        `key:"log_level" validate:"oneof=debug info warn error"`
+}
+
+func TestDecode(t *testing.T) {
+	var rt testRuntime
+	if err := Decode(&rt, map[string]string{"max_streams": "3", "log_level": "DEBUG"}); err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if rt.MaxStreams != 3 || rt.Timeout != 5*time.Second || rt.LogLevel != "debug" {
+		t.Fatalf("decoded %+v", rt)
+	}
+	if err := Decode(&testRuntime{}, map[string]string{"max_stream": "3"}); err == nil || !strings.Contains(err.Error(), `"max_stream"`) {
+		t.Fatalf("unknown key: err = %v", err)
+	}
+	var verr *ValidationError
+	if err := Decode(&testRuntime{}, map[string]string{"publish_timeout": "1ms"}); !errors.As(err, &verr) || verr.Errors[0].Field != "publish_timeout" {
+		t.Fatalf("invalid value: err = %v", err)
+	}
+}
+
+type mapSource map[string]string
+
+func (s mapSource) Load(context.Context) (map[string]string, error) { return s, nil }
+
+func TestReloaderKeepsPreviousSettingsWhenInvalid(t *testing.T) {
+	src := mapSource{"max_streams": "2"}
+	r, err := NewReloader[testRuntime](src, time.Minute, slog.New(slog.DiscardHandler))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Current().Timeout != 5*time.Second {
+		t.Fatalf("initial settings = %+v, want the defaults", r.Current())
+	}
+	var changes int
+	r.OnChange(func(old, new testRuntime) { changes++ })
+
+	if err := r.Reload(context.Background()); err != nil || r.Current().MaxStreams != 2 || changes != 1 {
+		t.Fatalf("reload = %v, settings %+v, %d changes", err, r.Current(), changes)
+	}
+	if err := r.Reload(context.Background()); err != nil || changes != 1 {
+		t.Fatalf("unchanged reload = %v, %d changes", err, changes)
+	}
+	src["publish_timeout"] = "1ms"
+	if err := r.Reload(context.Background()); err == nil {
+		t.Fatal("invalid settings were accepted")
+	}
+	if r.Current().MaxStreams != 2 || r.Current().Timeout != 5*time.Second || changes != 1 {
+		t.Fatalf("settings after a rejected reload = %+v", r.Current())
+	}
+}
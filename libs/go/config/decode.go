@@ -0,0 +1,71 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Decode sets the fields of the struct dst points to from their defaults and
+// then from values keyed by YAML key, and validates the result. Unknown keys
+// are errors. It is how runtime settings are read from a key/value source.
+func Decode(dst any, values map[string]string) error {
+	fields, err := collectFields(dst)
+	if err != nil {
+		return err
+	}
+	byKey := make(map[string]field, len(fields))
+	for _, f := range fields {
+		byKey[f.yamlKey()] = f
+		if def, ok := f.field.Tag.Lookup("default"); ok {
+			if err := f.set(def); err != nil {
+				return fmt.Errorf("default for %s: %w", f.yamlKey(), err)
+			}
+		}
+	}
+	var errs []error
+	for key, raw := range values {
+		f, ok := byKey[key]
+		if !ok {
+			errs = append(errs, fmt.Errorf("unknown key %q", key))
+			continue
+		}
+		if err := f.set(raw); err != nil {
+			errs = append(errs, fmt.Errorf("key %s: %w", key, err))
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return validate(fields)
+}
+
+// Change is one setting whose value differs between two configs. Secrets
+// are redacted.
+type Change struct {
+	Key string
+	Old string
+	New string
+}
+
+// Changes lists the settings that differ between the structs old and new
+// point to, sorted by key.
+func Changes(old, new any) ([]Change, error) {
+	before, err := Redacted(old)
+	if err != nil {
+		return nil, err
+	}
+	after, err := Redacted(new)
+	if err != nil {
+		return nil, err
+	}
+	var changes []Change
+	for key, val := range after {
+		if before[key] != val {
+			changes = append(changes, Change{Key: key, Old: before[key], New: val})
+		}
+	}
+	slices.SortFunc(changes, func(a, b Change) int { return strings.Compare(a.Key, b.Key) })
+	return changes, nil
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+var durationType = reflect.TypeFor[time.Duration]()
+
+// field is one configurable struct field.
+type field struct {
+	field  reflect.StructField
+	value  reflect.Value
+	env    string
+	key    string
+	secret bool
+}
+
+// yamlKey is the key tag, or the lowercased env name.
+func (f field) yamlKey() string {
+	if f.key != "" {
+		return f.key
+	}
+	return strings.ToLower(f.env)
+}
+
+// name identifies the field in errors: its env name, or its key.
+func (f field) name() string {
+	if f.env != "" {
+		return f.env
+	}
+	return f.key
+}
+
+func (f field) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(f.env), "_", "-")
+}
+
+// collectFields returns the env- or key-tagged fields of the struct dst points
+// to, descending into untagged struct fields.
+func collectFields(dst any) ([]field, error) {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("config: want a pointer to a struct, got %T", dst)
+	}
+	var fields []field
+	if err := appendFields(&fields, v.Elem()); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+func appendFields(fields *[]field, v reflect.Value) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		env, hasEnv := sf.Tag.Lookup("env")
+		key := sf.Tag.Get("key")
+		if env == "-" || (!hasEnv && key == "") {
+			if env != "-" && sf.Type.Kind() == reflect.Struct && sf.Type != durationType {
+				if err := appendFields(fields, v.Field(i)); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !supported(sf.Type) {
+			return fmt.Errorf("config: field %s has unsupported type %s", sf.Name, sf.Type)
+		}
+		*fields = append(*fields, field{field: sf, value: v.Field(i), env: env, key: key, secret: sf.Tag.Get("secret") == "true"})
+	}
+	return nil
+}
+
+func supported(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Int, reflect.Int64, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return t.Elem().Kind() == reflect.String
+	}
+	return false
+}
+
+// set parses raw into the field. Lists are comma-separated.
+func (f field) set(raw string) error {
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(d))
+	case v.Kind() == reflect.String:
+		v.SetString(raw)
+	case v.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case v.Kind() == reflect.Float64:
+		x, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+	case v.Kind() == reflect.Slice:
+		parts := strings.Split(raw, ",")
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+		}
+		v.Set(reflect.ValueOf(parts))
+	default:
+		return errors.ErrUnsupported
+	}
+	return nil
+}
+
+// decode sets the field from a YAML node. Scalars go through set so YAML and
+// env accept the same spellings (e.g. "30s" durations).
+func (f field) decode(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		return f.set(node.Value)
+	}
+	if node.Kind == yaml.SequenceNode && f.value.Kind() == reflect.Slice {
+		var items []string
+		if err := node.Decode(&items); err != nil {
+			return err
+		}
+		f.value.Set(reflect.ValueOf(items))
+		return nil
+	}
+	return fmt.Errorf("unsupported YAML value at line %d", node.Line)
+}
+
+// String formats the field's current value the way set parses it.
+func (f field) String() string {
+	v := f.value
+	switch {
+	case v.Type() == durationType:
+		return time.Duration(v.Int()).String()
+	case v.Kind() == reflect.Slice:
+		return strings.Join(v.Interface().([]string), ",")
+	default:
+		return fmt.Sprint(v.Interface())
+	}
+}
@@ -0,0 +1,24 @@
+package config
+
+// RedactedValue replaces non-empty secrets in Redacted output.
+const RedactedValue = "[REDACTED]"
+
+// Redacted returns the effective configuration of the struct cfg points to,
+// keyed by YAML key and formatted as the env layer would accept it, with
+// secret fields replaced by RedactedValue. It is meant to be logged at
+// startup.
+func Redacted(cfg any) (map[string]string, error) {
+	fields, err := collectFields(cfg)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]string, len(fields))
+	for _, f := range fields {
+		val := f.String()
+		if f.secret && val != "" {
+			val = RedactedValue
+		}
+		out[f.yamlKey()] = val
+	}
+	return out, nil
+}
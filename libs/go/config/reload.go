@@ -0,0 +1,120 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+)
+
+// Source provides runtime settings keyed by YAML key.
+type Source interface {
+	Load(ctx context.Context) (map[string]string, error)
+}
+
+// Notifier is implemented by sources that can push change notifications, so
+// updates apply before the next poll.
+type Notifier interface {
+	Notify(ctx context.Context) <-chan struct{}
+}
+
+// Reloader keeps a settings struct of type T in sync with a Source. T uses
+// the same tags as Load; its fields are keyed by YAML key in the source.
+type Reloader[T any] struct {
+	source   Source
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu        sync.RWMutex
+	current   T
+	listeners []func(old, new T)
+}
+
+// NewReloader returns a Reloader holding T's defaults until the first
+// Reload. interval is how often the source is polled.
+func NewReloader[T any](source Source, interval time.Duration, logger *slog.Logger) (*Reloader[T], error) {
+	r := &Reloader[T]{source: source, interval: interval, logger: logger}
+	if err := Decode(&r.current, nil); err != nil {
+		return nil, fmt.Errorf("runtime settings defaults: %w", err)
+	}
+	return r, nil
+}
+
+// Current returns the settings in effect.
+func (r *Reloader[T]) Current() T {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.current
+}
+
+// OnChange registers fn to run after every reload that changes a setting.
+func (r *Reloader[T]) OnChange(fn func(old, new T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, fn)
+}
+
+// Redacted returns the settings in effect as Redacted formats them.
+func (r *Reloader[T]) Redacted() map[string]string {
+	current := r.Current()
+	out, _ := Redacted(&current)
+	return out
+}
+
+// Reload reads the source and applies it. Invalid settings are rejected as a
+// whole and the previous ones stay in effect.
+func (r *Reloader[T]) Reload(ctx context.Context) error {
+	values, err := r.source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load runtime settings: %w", err)
+	}
+	var next T
+	if err := Decode(&next, values); err != nil {
+		return fmt.Errorf("decode runtime settings: %w", err)
+	}
+
+	r.mu.Lock()
+	old := r.current
+	changes, err := Changes(&old, &next)
+	if err != nil || len(changes) == 0 {
+		r.mu.Unlock()
+		return err
+	}
+	r.current = next
+	listeners := slices.Clone(r.listeners)
+	r.mu.Unlock()
+
+	for _, c := range changes {
+		r.logger.InfoContext(ctx, "runtime setting changed", slog.String("key", c.Key), slog.String("old", c.Old), slog.String("new", c.New))
+	}
+	for _, fn := range listeners {
+		fn(old, next)
+	}
+	return nil
+}
+
+// Run reloads on every poll interval and source notification until ctx is
+// done. Reload errors are logged and retried on the next trigger.
+func (r *Reloader[T]) Run(ctx context.Context) error {
+	var notify <-chan struct{}
+	if n, ok := r.source.(Notifier); ok {
+		notify = n.Notify(ctx)
+	}
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-notify:
+		}
+		if err := r.Reload(ctx); err != nil && ctx.Err() == nil {
+			r.logger.ErrorContext(ctx, "reload runtime settings", observability.Err(err))
+		}
+	}
+}
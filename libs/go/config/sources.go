@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+
+	redis "github.com/redis/go-redis/v9"
+	"gopkg.in/yaml.v3"
+)
+
+// FileSource reads runtime settings from a flat YAML file. A missing file
+// means no overrides. Changes are picked up by polling.
+type FileSource struct {
+	Path string
+}
+
+func (s FileSource) Load(context.Context) (map[string]string, error) {
+	data, err := os.ReadFile(s.Path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var doc map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", s.Path, err)
+	}
+	values := make(map[string]string, len(doc))
+	for key, node := range doc {
+		if node.Kind != yaml.ScalarNode {
+			return nil, fmt.Errorf("%s: key %s must be a scalar", s.Path, key)
+		}
+		values[key] = node.Value
+	}
+	return values, nil
+}
+
+// RedisSource reads runtime settings from a Redis hash. Writers publish on a
+// channel named after the hash so readers reload immediately; Set does both.
+type RedisSource struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// NewRedisSource returns a source backed by the hash at key.
+func NewRedisSource(client redis.UniversalClient, key string) *RedisSource {
+	return &RedisSource{client: client, key: key}
+}
+
+func (s *RedisSource) Load(ctx context.Context) (map[string]string, error) {
+	values, err := s.client.HGetAll(ctx, s.key).Result()
+	if err != nil {
+		return nil, fmt.Errorf("redis HGETALL %s: %w", s.key, err)
+	}
+	return values, nil
+}
+
+// Set stores values in the hash and notifies readers. An empty value removes
+// the key, reverting it to its default.
+func (s *RedisSource) Set(ctx context.Context, values map[string]string) error {
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		for key, val := range values {
+			if val == "" {
+				pipe.HDel(ctx, s.key, key)
+			} else {
+				pipe.HSet(ctx, s.key, key, val)
+			}
+		}
+		pipe.Publish(ctx, s.key, "changed")
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("update runtime settings %s: %w", s.key, err)
+	}
+	return nil
+}
+
+// Notify signals every message published on the hash's channel until ctx is
+// done. Missed notifications are covered by polling.
+func (s *RedisSource) Notify(ctx context.Context) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	sub := s.client.Subscribe(ctx, s.key)
+	go func() {
+		defer sub.Close()
+		msgs := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-msgs:
+				if !ok {
+					return
+				}
+				select {
+				case out <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return out
+}
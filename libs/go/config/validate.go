@@ -0,0 +1,115 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is one failed validation rule.
+type FieldError struct {
+	// Field is the env name of the field, or its key for runtime settings.
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationError lists every field that failed validation.
+type ValidationError struct {
+	Errors []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, fe := range e.Errors {
+		msgs[i] = fe.Error()
+	}
+	return "invalid config: " + strings.Join(msgs, "; ")
+}
+
+// validate checks the comma-separated rules of each field's validate tag:
+//
+//   - required: the value is not empty or zero.
+//   - oneof=a b c: a non-empty value is one of the options, compared
+//     case-insensitively; the field takes the option's spelling.
+//   - min=N, max=N: numeric bounds (durations as "30s", lists by length).
+func validate(fields []field) error {
+	var errs []FieldError
+	for _, f := range fields {
+		rules := f.field.Tag.Get("validate")
+		if rules == "" {
+			continue
+		}
+		for _, rule := range strings.Split(rules, ",") {
+			name, arg, _ := strings.Cut(rule, "=")
+			if msg := check(f, name, arg); msg != "" {
+				errs = append(errs, FieldError{Field: f.name(), Message: msg})
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return &ValidationError{Errors: errs}
+	}
+	return nil
+}
+
+func check(f field, rule, arg string) string {
+	v := f.value
+	switch rule {
+	case "required":
+		if v.IsZero() || (v.Kind() == reflect.Slice && v.Len() == 0) {
+			return "is required"
+		}
+	case "oneof":
+		if v.String() == "" {
+			return ""
+		}
+		options := strings.Fields(arg)
+		for _, opt := range options {
+			if strings.EqualFold(v.String(), opt) {
+				v.SetString(opt)
+				return ""
+			}
+		}
+		return fmt.Sprintf("%q is not one of %s", v.String(), strings.Join(options, ", "))
+	case "min", "max":
+		bound, val, err := bounds(v, arg)
+		if err != nil {
+			return fmt.Sprintf("bad %s rule: %v", rule, err)
+		}
+		if rule == "min" && val < bound {
+			return fmt.Sprintf("%s is below the minimum %s", f, arg)
+		}
+		if rule == "max" && val > bound {
+			return fmt.Sprintf("%s is above the maximum %s", f, arg)
+		}
+	default:
+		return fmt.Sprintf("unknown validation rule %q", rule)
+	}
+	return ""
+}
+
+// bounds parses a min/max argument for v and returns it with v's comparable
+// value.
+func bounds(v reflect.Value, arg string) (float64, float64, error) {
+	switch {
+	case v.Type() == durationType:
+		d, err := time.ParseDuration(arg)
+		return float64(d), float64(v.Int()), err
+	case v.Kind() == reflect.Int || v.Kind() == reflect.Int64:
+		n, err := strconv.ParseFloat(arg, 64)
+		return n, float64(v.Int()), err
+	case v.Kind() == reflect.Float64:
+		n, err := strconv.ParseFloat(arg, 64)
+		return n, v.Float(), err
+	case v.Kind() == reflect.Slice || v.Kind() == reflect.String:
+		n, err := strconv.ParseFloat(arg, 64)
+		return n, float64(v.Len()), err
+	}
+	return 0, 0, fmt.Errorf("not supported for %s", v.Type())
+}
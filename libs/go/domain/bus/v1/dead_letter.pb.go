@@ -0,0 +1,160 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: bus/v1/dead_letter.proto
+
+package busv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// DeadLetter carries an event that ingestion rejected instead of publishing,
+// together with the reason. It is published to the ingestion_dead_letters
+// topic, keyed by influencer, for inspection and manual replay.
+type DeadLetter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The rejected event, including the original Hyperliquid payload.
+	Event *RawEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+	// Processing stage that rejected the event, e.g. "normalize".
+	Stage string `protobuf:"bytes,2,opt,name=stage,proto3" json:"stage,omitempty"`
+	// Human-readable rejection reason.
+	Reason string `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	// Time ingestion rejected the event in Unix millis.
+	RejectedAtMs  int64 `protobuf:"varint,4,opt,name=rejected_at_ms,json=rejectedAtMs,proto3" json:"rejected_at_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeadLetter) Reset() {
+	*x = DeadLetter{}
+	mi := &file_bus_v1_dead_letter_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeadLetter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeadLetter) ProtoMessage() {}
+
+func (x *DeadLetter) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_dead_letter_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeadLetter.ProtoReflect.Descriptor instead.
+func (*DeadLetter) Descriptor() ([]byte, []int) {
+	return file_bus_v1_dead_letter_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DeadLetter) GetEvent() *RawEvent {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *DeadLetter) GetStage() string {
+	if x != nil {
+		return x.Stage
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *DeadLetter) GetRejectedAtMs() int64 {
+	if x != nil {
+		return x.RejectedAtMs
+	}
+	return 0
+}
+
+var File_bus_v1_dead_letter_proto protoreflect.FileDescriptor
+
+const file_bus_v1_dead_letter_proto_rawDesc = "" +
+	"\n" +
+	"\x18bus/v1/dead_letter.proto\x12\x06bus.v1\x1a\x16bus/v1/raw_event.proto\"\x88\x01\n" +
+	"\n" +
+	"DeadLetter\x12&\n" +
+	"\x05event\x18\x01 \x01(\v2\x10.bus.v1.RawEventR\x05event\x12\x14\n" +
+	"\x05stage\x18\x02 \x01(\tR\x05stage\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12$\n" +
+	"\x0erejected_at_ms\x18\x04 \x01(\x03R\frejectedAtMsBEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
+
+var (
+	file_bus_v1_dead_letter_proto_rawDescOnce sync.Once
+	file_bus_v1_dead_letter_proto_rawDescData []byte
+)
+
+func file_bus_v1_dead_letter_proto_rawDescGZIP() []byte {
+	file_bus_v1_dead_letter_proto_rawDescOnce.Do(func() {
+		file_bus_v1_dead_letter_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bus_v1_dead_letter_proto_rawDesc), len(file_bus_v1_dead_letter_proto_rawDesc)))
+	})
+	return file_bus_v1_dead_letter_proto_rawDescData
+}
+
+var file_bus_v1_dead_letter_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_bus_v1_dead_letter_proto_goTypes = []any{
+	(*DeadLetter)(nil), // 0: bus.v1.DeadLetter
+	(*RawEvent)(nil),   // 1: bus.v1.RawEvent
+}
+var file_bus_v1_dead_letter_proto_depIdxs = []int32{
+	1, // 0: bus.v1.DeadLetter.event:type_name -> bus.v1.RawEvent
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_bus_v1_dead_letter_proto_init() }
+func file_bus_v1_dead_letter_proto_init() {
+	if File_bus_v1_dead_letter_proto != nil {
+		return
+	}
+	file_bus_v1_raw_event_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_dead_letter_proto_rawDesc), len(file_bus_v1_dead_letter_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bus_v1_dead_letter_proto_goTypes,
+		DependencyIndexes: file_bus_v1_dead_letter_proto_depIdxs,
+		MessageInfos:      file_bus_v1_dead_letter_proto_msgTypes,
+	}.Build()
+	File_bus_v1_dead_letter_proto = out.File
+	file_bus_v1_dead_letter_proto_goTypes = nil
+	file_bus_v1_dead_letter_proto_depIdxs = nil
+}
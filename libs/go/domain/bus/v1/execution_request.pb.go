@@ -239,19 +239,36 @@ type ExecutionRequest struct {
 	Market             string                 `protobuf:"bytes,6,opt,name=market,proto3" json:"market,omitempty"`
 	Side               OrderSide              `protobuf:"varint,7,opt,name=side,proto3,enum=bus.v1.OrderSide" json:"side,omitempty"`
 	OrderType          OrderType              `protobuf:"varint,8,opt,name=order_type,json=orderType,proto3,enum=bus.v1.OrderType" json:"order_type,omitempty"`
-	Quantity           float64                `protobuf:"fixed64,9,opt,name=quantity,proto3" json:"quantity,omitempty"`
-	Notional           float64                `protobuf:"fixed64,10,opt,name=notional,proto3" json:"notional,omitempty"`
-	Price              float64                `protobuf:"fixed64,11,opt,name=price,proto3" json:"price,omitempty"`
-	Leverage           float64                `protobuf:"fixed64,12,opt,name=leverage,proto3" json:"leverage,omitempty"`
-	TimeInForce        TimeInForce            `protobuf:"varint,13,opt,name=time_in_force,json=timeInForce,proto3,enum=bus.v1.TimeInForce" json:"time_in_force,omitempty"`
-	RiskChecksPassed   bool                   `protobuf:"varint,14,opt,name=risk_checks_passed,json=riskChecksPassed,proto3" json:"risk_checks_passed,omitempty"`
-	RejectionReason    string                 `protobuf:"bytes,15,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
-	Source             ExecutionRequestSource `protobuf:"varint,16,opt,name=source,proto3,enum=bus.v1.ExecutionRequestSource" json:"source,omitempty"`
-	CreatedAt          *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	TraceId            string                 `protobuf:"bytes,18,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
-	CorrelationId      string                 `protobuf:"bytes,19,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
-	unknownFields      protoimpl.UnknownFields
-	sizeCache          protoimpl.SizeCache
+	// Superseded by quantity_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
+	Quantity float64 `protobuf:"fixed64,9,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	// Superseded by notional_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
+	Notional float64 `protobuf:"fixed64,10,opt,name=notional,proto3" json:"notional,omitempty"`
+	// Superseded by price_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
+	Price            float64                `protobuf:"fixed64,11,opt,name=price,proto3" json:"price,omitempty"`
+	Leverage         float64                `protobuf:"fixed64,12,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	TimeInForce      TimeInForce            `protobuf:"varint,13,opt,name=time_in_force,json=timeInForce,proto3,enum=bus.v1.TimeInForce" json:"time_in_force,omitempty"`
+	RiskChecksPassed bool                   `protobuf:"varint,14,opt,name=risk_checks_passed,json=riskChecksPassed,proto3" json:"risk_checks_passed,omitempty"`
+	RejectionReason  string                 `protobuf:"bytes,15,opt,name=rejection_reason,json=rejectionReason,proto3" json:"rejection_reason,omitempty"`
+	Source           ExecutionRequestSource `protobuf:"varint,16,opt,name=source,proto3,enum=bus.v1.ExecutionRequestSource" json:"source,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	TraceId          string                 `protobuf:"bytes,18,opt,name=trace_id,json=traceId,proto3" json:"trace_id,omitempty"`
+	CorrelationId    string                 `protobuf:"bytes,19,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	// Margin mode to apply with leverage; unspecified keeps the account setting.
+	MarginMode MarginMode `protobuf:"varint,20,opt,name=margin_mode,json=marginMode,proto3,enum=bus.v1.MarginMode" json:"margin_mode,omitempty"`
+	// Exact decimal strings of quantity, notional and price (see
+	// numbers.Decimal). quantity_decimal is truncated to the market's lot size
+	// when the signal carries market metadata.
+	QuantityDecimal string `protobuf:"bytes,21,opt,name=quantity_decimal,json=quantityDecimal,proto3" json:"quantity_decimal,omitempty"`
+	NotionalDecimal string `protobuf:"bytes,22,opt,name=notional_decimal,json=notionalDecimal,proto3" json:"notional_decimal,omitempty"`
+	PriceDecimal    string `protobuf:"bytes,23,opt,name=price_decimal,json=priceDecimal,proto3" json:"price_decimal,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *ExecutionRequest) Reset() {
@@ -340,6 +357,7 @@ func (x *ExecutionRequest) GetOrderType() OrderType {
 	return OrderType_ORDER_TYPE_UNSPECIFIED
 }
 
+// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
 func (x *ExecutionRequest) GetQuantity() float64 {
 	if x != nil {
 		return x.Quantity
@@ -347,6 +365,7 @@ func (x *ExecutionRequest) GetQuantity() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
 func (x *ExecutionRequest) GetNotional() float64 {
 	if x != nil {
 		return x.Notional
@@ -354,6 +373,7 @@ func (x *ExecutionRequest) GetNotional() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in bus/v1/execution_request.proto.
 func (x *ExecutionRequest) GetPrice() float64 {
 	if x != nil {
 		return x.Price
@@ -417,11 +437,39 @@ func (x *ExecutionRequest) GetCorrelationId() string {
 	return ""
 }
 
+func (x *ExecutionRequest) GetMarginMode() MarginMode {
+	if x != nil {
+		return x.MarginMode
+	}
+	return MarginMode_MARGIN_MODE_UNSPECIFIED
+}
+
+func (x *ExecutionRequest) GetQuantityDecimal() string {
+	if x != nil {
+		return x.QuantityDecimal
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetNotionalDecimal() string {
+	if x != nil {
+		return x.NotionalDecimal
+	}
+	return ""
+}
+
+func (x *ExecutionRequest) GetPriceDecimal() string {
+	if x != nil {
+		return x.PriceDecimal
+	}
+	return ""
+}
+
 var File_bus_v1_execution_request_proto protoreflect.FileDescriptor
 
 const file_bus_v1_execution_request_proto_rawDesc = "" +
 	"\n" +
-	"\x1ebus/v1/execution_request.proto\x12\x06bus.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xf6\x05\n" +
+	"\x1ebus/v1/execution_request.proto\x12\x06bus.v1\x1a\x13bus/v1/signal.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xb2\a\n" +
 	"\x10ExecutionRequest\x120\n" +
 	"\x14execution_request_id\x18\x01 \x01(\tR\x12executionRequestId\x12\x1b\n" +
 	"\tsignal_id\x18\x02 \x01(\tR\bsignalId\x12#\n" +
@@ -431,11 +479,11 @@ const file_bus_v1_execution_request_proto_rawDesc = "" +
 	"\x06market\x18\x06 \x01(\tR\x06market\x12%\n" +
 	"\x04side\x18\a \x01(\x0e2\x11.bus.v1.OrderSideR\x04side\x120\n" +
 	"\n" +
-	"order_type\x18\b \x01(\x0e2\x11.bus.v1.OrderTypeR\torderType\x12\x1a\n" +
-	"\bquantity\x18\t \x01(\x01R\bquantity\x12\x1a\n" +
+	"order_type\x18\b \x01(\x0e2\x11.bus.v1.OrderTypeR\torderType\x12\x1e\n" +
+	"\bquantity\x18\t \x01(\x01B\x02\x18\x01R\bquantity\x12\x1e\n" +
 	"\bnotional\x18\n" +
-	" \x01(\x01R\bnotional\x12\x14\n" +
-	"\x05price\x18\v \x01(\x01R\x05price\x12\x1a\n" +
+	" \x01(\x01B\x02\x18\x01R\bnotional\x12\x18\n" +
+	"\x05price\x18\v \x01(\x01B\x02\x18\x01R\x05price\x12\x1a\n" +
 	"\bleverage\x18\f \x01(\x01R\bleverage\x127\n" +
 	"\rtime_in_force\x18\r \x01(\x0e2\x13.bus.v1.TimeInForceR\vtimeInForce\x12,\n" +
 	"\x12risk_checks_passed\x18\x0e \x01(\bR\x10riskChecksPassed\x12)\n" +
@@ -444,7 +492,12 @@ const file_bus_v1_execution_request_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12\x19\n" +
 	"\btrace_id\x18\x12 \x01(\tR\atraceId\x12%\n" +
-	"\x0ecorrelation_id\x18\x13 \x01(\tR\rcorrelationId*{\n" +
+	"\x0ecorrelation_id\x18\x13 \x01(\tR\rcorrelationId\x123\n" +
+	"\vmargin_mode\x18\x14 \x01(\x0e2\x12.bus.v1.MarginModeR\n" +
+	"marginMode\x12)\n" +
+	"\x10quantity_decimal\x18\x15 \x01(\tR\x0fquantityDecimal\x12)\n" +
+	"\x10notional_decimal\x18\x16 \x01(\tR\x0fnotionalDecimal\x12#\n" +
+	"\rprice_decimal\x18\x17 \x01(\tR\fpriceDecimal*{\n" +
 	"\tOrderSide\x12\x1a\n" +
 	"\x16ORDER_SIDE_UNSPECIFIED\x10\x00\x12\x12\n" +
 	"\x0eORDER_SIDE_BUY\x10\x01\x12\x13\n" +
@@ -485,6 +538,7 @@ var file_bus_v1_execution_request_proto_goTypes = []any{
 	(ExecutionRequestSource)(0),   // 3: bus.v1.ExecutionRequestSource
 	(*ExecutionRequest)(nil),      // 4: bus.v1.ExecutionRequest
 	(*timestamppb.Timestamp)(nil), // 5: google.protobuf.Timestamp
+	(MarginMode)(0),               // 6: bus.v1.MarginMode
 }
 var file_bus_v1_execution_request_proto_depIdxs = []int32{
 	0, // 0: bus.v1.ExecutionRequest.side:type_name -> bus.v1.OrderSide
@@ -492,11 +546,12 @@ var file_bus_v1_execution_request_proto_depIdxs = []int32{
 	2, // 2: bus.v1.ExecutionRequest.time_in_force:type_name -> bus.v1.TimeInForce
 	3, // 3: bus.v1.ExecutionRequest.source:type_name -> bus.v1.ExecutionRequestSource
 	5, // 4: bus.v1.ExecutionRequest.created_at:type_name -> google.protobuf.Timestamp
-	5, // [5:5] is the sub-list for method output_type
-	5, // [5:5] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	6, // 5: bus.v1.ExecutionRequest.margin_mode:type_name -> bus.v1.MarginMode
+	6, // [6:6] is the sub-list for method output_type
+	6, // [6:6] is the sub-list for method input_type
+	6, // [6:6] is the sub-list for extension type_name
+	6, // [6:6] is the sub-list for extension extendee
+	0, // [0:6] is the sub-list for field type_name
 }
 
 func init() { file_bus_v1_execution_request_proto_init() }
@@ -504,6 +559,7 @@ func file_bus_v1_execution_request_proto_init() {
 	if File_bus_v1_execution_request_proto != nil {
 		return
 	}
+	file_bus_v1_signal_proto_init()
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
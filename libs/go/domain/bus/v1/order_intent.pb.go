@@ -0,0 +1,491 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: bus/v1/order_intent.proto
+
+package busv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// OrderIntentStatus is the lifecycle transition an order update represents.
+type OrderIntentStatus int32
+
+const (
+	OrderIntentStatus_ORDER_INTENT_STATUS_UNSPECIFIED OrderIntentStatus = 0
+	OrderIntentStatus_ORDER_INTENT_STATUS_PLACED      OrderIntentStatus = 1
+	OrderIntentStatus_ORDER_INTENT_STATUS_MODIFIED    OrderIntentStatus = 2
+	OrderIntentStatus_ORDER_INTENT_STATUS_CANCELLED   OrderIntentStatus = 3
+	OrderIntentStatus_ORDER_INTENT_STATUS_FILLED      OrderIntentStatus = 4
+	OrderIntentStatus_ORDER_INTENT_STATUS_TRIGGERED   OrderIntentStatus = 5
+	OrderIntentStatus_ORDER_INTENT_STATUS_REJECTED    OrderIntentStatus = 6
+)
+
+// Enum value maps for OrderIntentStatus.
+var (
+	OrderIntentStatus_name = map[int32]string{
+		0: "ORDER_INTENT_STATUS_UNSPECIFIED",
+		1: "ORDER_INTENT_STATUS_PLACED",
+		2: "ORDER_INTENT_STATUS_MODIFIED",
+		3: "ORDER_INTENT_STATUS_CANCELLED",
+		4: "ORDER_INTENT_STATUS_FILLED",
+		5: "ORDER_INTENT_STATUS_TRIGGERED",
+		6: "ORDER_INTENT_STATUS_REJECTED",
+	}
+	OrderIntentStatus_value = map[string]int32{
+		"ORDER_INTENT_STATUS_UNSPECIFIED": 0,
+		"ORDER_INTENT_STATUS_PLACED":      1,
+		"ORDER_INTENT_STATUS_MODIFIED":    2,
+		"ORDER_INTENT_STATUS_CANCELLED":   3,
+		"ORDER_INTENT_STATUS_FILLED":      4,
+		"ORDER_INTENT_STATUS_TRIGGERED":   5,
+		"ORDER_INTENT_STATUS_REJECTED":    6,
+	}
+)
+
+func (x OrderIntentStatus) Enum() *OrderIntentStatus {
+	p := new(OrderIntentStatus)
+	*p = x
+	return p
+}
+
+func (x OrderIntentStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderIntentStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_order_intent_proto_enumTypes[0].Descriptor()
+}
+
+func (OrderIntentStatus) Type() protoreflect.EnumType {
+	return &file_bus_v1_order_intent_proto_enumTypes[0]
+}
+
+func (x OrderIntentStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderIntentStatus.Descriptor instead.
+func (OrderIntentStatus) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_order_intent_proto_rawDescGZIP(), []int{0}
+}
+
+// OrderIntentSide is the direction of the resting order.
+type OrderIntentSide int32
+
+const (
+	OrderIntentSide_ORDER_INTENT_SIDE_UNSPECIFIED OrderIntentSide = 0
+	OrderIntentSide_ORDER_INTENT_SIDE_BUY         OrderIntentSide = 1
+	OrderIntentSide_ORDER_INTENT_SIDE_SELL        OrderIntentSide = 2
+)
+
+// Enum value maps for OrderIntentSide.
+var (
+	OrderIntentSide_name = map[int32]string{
+		0: "ORDER_INTENT_SIDE_UNSPECIFIED",
+		1: "ORDER_INTENT_SIDE_BUY",
+		2: "ORDER_INTENT_SIDE_SELL",
+	}
+	OrderIntentSide_value = map[string]int32{
+		"ORDER_INTENT_SIDE_UNSPECIFIED": 0,
+		"ORDER_INTENT_SIDE_BUY":         1,
+		"ORDER_INTENT_SIDE_SELL":        2,
+	}
+)
+
+func (x OrderIntentSide) Enum() *OrderIntentSide {
+	p := new(OrderIntentSide)
+	*p = x
+	return p
+}
+
+func (x OrderIntentSide) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (OrderIntentSide) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_order_intent_proto_enumTypes[1].Descriptor()
+}
+
+func (OrderIntentSide) Type() protoreflect.EnumType {
+	return &file_bus_v1_order_intent_proto_enumTypes[1]
+}
+
+func (x OrderIntentSide) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use OrderIntentSide.Descriptor instead.
+func (OrderIntentSide) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_order_intent_proto_rawDescGZIP(), []int{1}
+}
+
+// OrderIntent describes a change to an influencer's open (resting or trigger)
+// order, published to the influencer_orders topic so followers can mirror
+// limit, take-profit and stop-loss orders before they fill.
+type OrderIntent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic ID for the update (influencer + order ID + status + status time).
+	IntentId string `protobuf:"bytes,1,opt,name=intent_id,json=intentId,proto3" json:"intent_id,omitempty"`
+	// Internal ID or reference for the influencer (not necessarily the raw Hyperliquid address).
+	InfluencerId string `protobuf:"bytes,2,opt,name=influencer_id,json=influencerId,proto3" json:"influencer_id,omitempty"`
+	// Exchange identifier, e.g. "hyperliquid".
+	Exchange string `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	// Canonical market as normalized by ingestion (e.g., "ETH-PERP").
+	Market string `protobuf:"bytes,4,opt,name=market,proto3" json:"market,omitempty"`
+	// Lifecycle transition: PLACED, MODIFIED, CANCELLED, FILLED, TRIGGERED, REJECTED.
+	Status OrderIntentStatus `protobuf:"varint,5,opt,name=status,proto3,enum=bus.v1.OrderIntentStatus" json:"status,omitempty"`
+	// Order direction: BUY or SELL.
+	Side OrderIntentSide `protobuf:"varint,6,opt,name=side,proto3,enum=bus.v1.OrderIntentSide" json:"side,omitempty"`
+	// Exchange order ID (Hyperliquid oid).
+	OrderId int64 `protobuf:"varint,7,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	// Client order ID, if the influencer set one.
+	ClientOrderId string `protobuf:"bytes,8,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	// Limit price of the order.
+	LimitPrice float64 `protobuf:"fixed64,9,opt,name=limit_price,json=limitPrice,proto3" json:"limit_price,omitempty"`
+	// Remaining (unfilled) size in base units.
+	Size float64 `protobuf:"fixed64,10,opt,name=size,proto3" json:"size,omitempty"`
+	// Original size in base units.
+	OriginalSize float64 `protobuf:"fixed64,11,opt,name=original_size,json=originalSize,proto3" json:"original_size,omitempty"`
+	// Exchange order type, e.g. "Limit", "Stop Market", "Take Profit Limit".
+	OrderType string `protobuf:"bytes,12,opt,name=order_type,json=orderType,proto3" json:"order_type,omitempty"`
+	// Whether the order only activates once trigger_price is crossed.
+	IsTrigger bool `protobuf:"varint,13,opt,name=is_trigger,json=isTrigger,proto3" json:"is_trigger,omitempty"`
+	// Trigger price for stop/take-profit orders; 0 when not a trigger order.
+	TriggerPrice float64 `protobuf:"fixed64,14,opt,name=trigger_price,json=triggerPrice,proto3" json:"trigger_price,omitempty"`
+	// Human-readable trigger condition as reported by the exchange.
+	TriggerCondition string `protobuf:"bytes,15,opt,name=trigger_condition,json=triggerCondition,proto3" json:"trigger_condition,omitempty"`
+	// Whether the order can only reduce the position.
+	ReduceOnly bool `protobuf:"varint,16,opt,name=reduce_only,json=reduceOnly,proto3" json:"reduce_only,omitempty"`
+	// Whether the order is a take-profit.
+	IsTakeProfit bool `protobuf:"varint,17,opt,name=is_take_profit,json=isTakeProfit,proto3" json:"is_take_profit,omitempty"`
+	// Whether the order is a stop-loss.
+	IsStopLoss bool `protobuf:"varint,18,opt,name=is_stop_loss,json=isStopLoss,proto3" json:"is_stop_loss,omitempty"`
+	// Whether the TP/SL is attached to the whole position rather than an order.
+	IsPositionTpsl bool `protobuf:"varint,19,opt,name=is_position_tpsl,json=isPositionTpsl,proto3" json:"is_position_tpsl,omitempty"`
+	// Raw exchange status (e.g., "open", "canceled", "marginCanceled").
+	ExchangeStatus string `protobuf:"bytes,20,opt,name=exchange_status,json=exchangeStatus,proto3" json:"exchange_status,omitempty"`
+	// Status time from Hyperliquid in Unix millis.
+	TimestampMs int64 `protobuf:"varint,21,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
+	// Optional free-form metadata.
+	Metadata map[string]string `protobuf:"bytes,22,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// Whether market is a perpetual or spot market.
+	MarketType    MarketType `protobuf:"varint,23,opt,name=market_type,json=marketType,proto3,enum=bus.v1.MarketType" json:"market_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderIntent) Reset() {
+	*x = OrderIntent{}
+	mi := &file_bus_v1_order_intent_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderIntent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderIntent) ProtoMessage() {}
+
+func (x *OrderIntent) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_order_intent_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderIntent.ProtoReflect.Descriptor instead.
+func (*OrderIntent) Descriptor() ([]byte, []int) {
+	return file_bus_v1_order_intent_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *OrderIntent) GetIntentId() string {
+	if x != nil {
+		return x.IntentId
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetInfluencerId() string {
+	if x != nil {
+		return x.InfluencerId
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetStatus() OrderIntentStatus {
+	if x != nil {
+		return x.Status
+	}
+	return OrderIntentStatus_ORDER_INTENT_STATUS_UNSPECIFIED
+}
+
+func (x *OrderIntent) GetSide() OrderIntentSide {
+	if x != nil {
+		return x.Side
+	}
+	return OrderIntentSide_ORDER_INTENT_SIDE_UNSPECIFIED
+}
+
+func (x *OrderIntent) GetOrderId() int64 {
+	if x != nil {
+		return x.OrderId
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetLimitPrice() float64 {
+	if x != nil {
+		return x.LimitPrice
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetOriginalSize() float64 {
+	if x != nil {
+		return x.OriginalSize
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetOrderType() string {
+	if x != nil {
+		return x.OrderType
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetIsTrigger() bool {
+	if x != nil {
+		return x.IsTrigger
+	}
+	return false
+}
+
+func (x *OrderIntent) GetTriggerPrice() float64 {
+	if x != nil {
+		return x.TriggerPrice
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetTriggerCondition() string {
+	if x != nil {
+		return x.TriggerCondition
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetReduceOnly() bool {
+	if x != nil {
+		return x.ReduceOnly
+	}
+	return false
+}
+
+func (x *OrderIntent) GetIsTakeProfit() bool {
+	if x != nil {
+		return x.IsTakeProfit
+	}
+	return false
+}
+
+func (x *OrderIntent) GetIsStopLoss() bool {
+	if x != nil {
+		return x.IsStopLoss
+	}
+	return false
+}
+
+func (x *OrderIntent) GetIsPositionTpsl() bool {
+	if x != nil {
+		return x.IsPositionTpsl
+	}
+	return false
+}
+
+func (x *OrderIntent) GetExchangeStatus() string {
+	if x != nil {
+		return x.ExchangeStatus
+	}
+	return ""
+}
+
+func (x *OrderIntent) GetTimestampMs() int64 {
+	if x != nil {
+		return x.TimestampMs
+	}
+	return 0
+}
+
+func (x *OrderIntent) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *OrderIntent) GetMarketType() MarketType {
+	if x != nil {
+		return x.MarketType
+	}
+	return MarketType_MARKET_TYPE_UNSPECIFIED
+}
+
+var File_bus_v1_order_intent_proto protoreflect.FileDescriptor
+
+const file_bus_v1_order_intent_proto_rawDesc = "" +
+	"\n" +
+	"\x19bus/v1/order_intent.proto\x12\x06bus.v1\x1a\x13bus/v1/signal.proto\"\xa0\a\n" +
+	"\vOrderIntent\x12\x1b\n" +
+	"\tintent_id\x18\x01 \x01(\tR\bintentId\x12#\n" +
+	"\rinfluencer_id\x18\x02 \x01(\tR\finfluencerId\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x04 \x01(\tR\x06market\x121\n" +
+	"\x06status\x18\x05 \x01(\x0e2\x19.bus.v1.OrderIntentStatusR\x06status\x12+\n" +
+	"\x04side\x18\x06 \x01(\x0e2\x17.bus.v1.OrderIntentSideR\x04side\x12\x19\n" +
+	"\border_id\x18\a \x01(\x03R\aorderId\x12&\n" +
+	"\x0fclient_order_id\x18\b \x01(\tR\rclientOrderId\x12\x1f\n" +
+	"\vlimit_price\x18\t \x01(\x01R\n" +
+	"limitPrice\x12\x12\n" +
+	"\x04size\x18\n" +
+	" \x01(\x01R\x04size\x12#\n" +
+	"\roriginal_size\x18\v \x01(\x01R\foriginalSize\x12\x1d\n" +
+	"\n" +
+	"order_type\x18\f \x01(\tR\torderType\x12\x1d\n" +
+	"\n" +
+	"is_trigger\x18\r \x01(\bR\tisTrigger\x12#\n" +
+	"\rtrigger_price\x18\x0e \x01(\x01R\ftriggerPrice\x12+\n" +
+	"\x11trigger_condition\x18\x0f \x01(\tR\x10triggerCondition\x12\x1f\n" +
+	"\vreduce_only\x18\x10 \x01(\bR\n" +
+	"reduceOnly\x12$\n" +
+	"\x0eis_take_profit\x18\x11 \x01(\bR\fisTakeProfit\x12 \n" +
+	"\fis_stop_loss\x18\x12 \x01(\bR\n" +
+	"isStopLoss\x12(\n" +
+	"\x10is_position_tpsl\x18\x13 \x01(\bR\x0eisPositionTpsl\x12'\n" +
+	"\x0fexchange_status\x18\x14 \x01(\tR\x0eexchangeStatus\x12!\n" +
+	"\ftimestamp_ms\x18\x15 \x01(\x03R\vtimestampMs\x12=\n" +
+	"\bmetadata\x18\x16 \x03(\v2!.bus.v1.OrderIntent.MetadataEntryR\bmetadata\x123\n" +
+	"\vmarket_type\x18\x17 \x01(\x0e2\x12.bus.v1.MarketTypeR\n" +
+	"marketType\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\x82\x02\n" +
+	"\x11OrderIntentStatus\x12#\n" +
+	"\x1fORDER_INTENT_STATUS_UNSPECIFIED\x10\x00\x12\x1e\n" +
+	"\x1aORDER_INTENT_STATUS_PLACED\x10\x01\x12 \n" +
+	"\x1cORDER_INTENT_STATUS_MODIFIED\x10\x02\x12!\n" +
+	"\x1dORDER_INTENT_STATUS_CANCELLED\x10\x03\x12\x1e\n" +
+	"\x1aORDER_INTENT_STATUS_FILLED\x10\x04\x12!\n" +
+	"\x1dORDER_INTENT_STATUS_TRIGGERED\x10\x05\x12 \n" +
+	"\x1cORDER_INTENT_STATUS_REJECTED\x10\x06*k\n" +
+	"\x0fOrderIntentSide\x12!\n" +
+	"\x1dORDER_INTENT_SIDE_UNSPECIFIED\x10\x00\x12\x19\n" +
+	"\x15ORDER_INTENT_SIDE_BUY\x10\x01\x12\x1a\n" +
+	"\x16ORDER_INTENT_SIDE_SELL\x10\x02BEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
+
+var (
+	file_bus_v1_order_intent_proto_rawDescOnce sync.Once
+	file_bus_v1_order_intent_proto_rawDescData []byte
+)
+
+func file_bus_v1_order_intent_proto_rawDescGZIP() []byte {
+	file_bus_v1_order_intent_proto_rawDescOnce.Do(func() {
+		file_bus_v1_order_intent_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bus_v1_order_intent_proto_rawDesc), len(file_bus_v1_order_intent_proto_rawDesc)))
+	})
+	return file_bus_v1_order_intent_proto_rawDescData
+}
+
+var file_bus_v1_order_intent_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_bus_v1_order_intent_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_bus_v1_order_intent_proto_goTypes = []any{
+	(OrderIntentStatus)(0), // 0: bus.v1.OrderIntentStatus
+	(OrderIntentSide)(0),   // 1: bus.v1.OrderIntentSide
+	(*OrderIntent)(nil),    // 2: bus.v1.OrderIntent
+	nil,                    // 3: bus.v1.OrderIntent.MetadataEntry
+	(MarketType)(0),        // 4: bus.v1.MarketType
+}
+var file_bus_v1_order_intent_proto_depIdxs = []int32{
+	0, // 0: bus.v1.OrderIntent.status:type_name -> bus.v1.OrderIntentStatus
+	1, // 1: bus.v1.OrderIntent.side:type_name -> bus.v1.OrderIntentSide
+	3, // 2: bus.v1.OrderIntent.metadata:type_name -> bus.v1.OrderIntent.MetadataEntry
+	4, // 3: bus.v1.OrderIntent.market_type:type_name -> bus.v1.MarketType
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_bus_v1_order_intent_proto_init() }
+func file_bus_v1_order_intent_proto_init() {
+	if File_bus_v1_order_intent_proto != nil {
+		return
+	}
+	file_bus_v1_signal_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_order_intent_proto_rawDesc), len(file_bus_v1_order_intent_proto_rawDesc)),
+			NumEnums:      2,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bus_v1_order_intent_proto_goTypes,
+		DependencyIndexes: file_bus_v1_order_intent_proto_depIdxs,
+		EnumInfos:         file_bus_v1_order_intent_proto_enumTypes,
+		MessageInfos:      file_bus_v1_order_intent_proto_msgTypes,
+	}.Build()
+	File_bus_v1_order_intent_proto = out.File
+	file_bus_v1_order_intent_proto_goTypes = nil
+	file_bus_v1_order_intent_proto_depIdxs = nil
+}
@@ -0,0 +1,191 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: bus/v1/position.proto
+
+package busv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// InfluencerPosition is the latest known position of an influencer on one
+// market. It is published to the compacted influencer_positions topic keyed by
+// "<influencer_id>:<market>", so the topic retains one snapshot per pair.
+type InfluencerPosition struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Internal ID or reference for the influencer (not necessarily the raw Hyperliquid address).
+	InfluencerId string `protobuf:"bytes,1,opt,name=influencer_id,json=influencerId,proto3" json:"influencer_id,omitempty"`
+	// Exchange identifier, e.g. "hyperliquid".
+	Exchange string `protobuf:"bytes,2,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	// Canonical market as normalized by ingestion (e.g., "ETH-PERP").
+	Market string `protobuf:"bytes,3,opt,name=market,proto3" json:"market,omitempty"`
+	// LONG, SHORT or FLAT once the position is closed.
+	Side SignalSide `protobuf:"varint,4,opt,name=side,proto3,enum=bus.v1.SignalSide" json:"side,omitempty"`
+	// Absolute position size in base units; 0 when flat.
+	Size float64 `protobuf:"fixed64,5,opt,name=size,proto3" json:"size,omitempty"`
+	// Size-weighted average entry price; 0 when flat.
+	EntryPrice float64 `protobuf:"fixed64,6,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	// Exchange time (ms) of the event that last changed the position.
+	UpdatedMs     int64 `protobuf:"varint,7,opt,name=updated_ms,json=updatedMs,proto3" json:"updated_ms,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InfluencerPosition) Reset() {
+	*x = InfluencerPosition{}
+	mi := &file_bus_v1_position_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InfluencerPosition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InfluencerPosition) ProtoMessage() {}
+
+func (x *InfluencerPosition) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_position_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InfluencerPosition.ProtoReflect.Descriptor instead.
+func (*InfluencerPosition) Descriptor() ([]byte, []int) {
+	return file_bus_v1_position_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InfluencerPosition) GetInfluencerId() string {
+	if x != nil {
+		return x.InfluencerId
+	}
+	return ""
+}
+
+func (x *InfluencerPosition) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *InfluencerPosition) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *InfluencerPosition) GetSide() SignalSide {
+	if x != nil {
+		return x.Side
+	}
+	return SignalSide_SIGNAL_SIDE_UNSPECIFIED
+}
+
+func (x *InfluencerPosition) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *InfluencerPosition) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *InfluencerPosition) GetUpdatedMs() int64 {
+	if x != nil {
+		return x.UpdatedMs
+	}
+	return 0
+}
+
+var File_bus_v1_position_proto protoreflect.FileDescriptor
+
+const file_bus_v1_position_proto_rawDesc = "" +
+	"\n" +
+	"\x15bus/v1/position.proto\x12\x06bus.v1\x1a\x13bus/v1/signal.proto\"\xe9\x01\n" +
+	"\x12InfluencerPosition\x12#\n" +
+	"\rinfluencer_id\x18\x01 \x01(\tR\finfluencerId\x12\x1a\n" +
+	"\bexchange\x18\x02 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x03 \x01(\tR\x06market\x12&\n" +
+	"\x04side\x18\x04 \x01(\x0e2\x12.bus.v1.SignalSideR\x04side\x12\x12\n" +
+	"\x04size\x18\x05 \x01(\x01R\x04size\x12\x1f\n" +
+	"\ventry_price\x18\x06 \x01(\x01R\n" +
+	"entryPrice\x12\x1d\n" +
+	"\n" +
+	"updated_ms\x18\a \x01(\x03R\tupdatedMsBEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
+
+var (
+	file_bus_v1_position_proto_rawDescOnce sync.Once
+	file_bus_v1_position_proto_rawDescData []byte
+)
+
+func file_bus_v1_position_proto_rawDescGZIP() []byte {
+	file_bus_v1_position_proto_rawDescOnce.Do(func() {
+		file_bus_v1_position_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bus_v1_position_proto_rawDesc), len(file_bus_v1_position_proto_rawDesc)))
+	})
+	return file_bus_v1_position_proto_rawDescData
+}
+
+var file_bus_v1_position_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_bus_v1_position_proto_goTypes = []any{
+	(*InfluencerPosition)(nil), // 0: bus.v1.InfluencerPosition
+	(SignalSide)(0),            // 1: bus.v1.SignalSide
+}
+var file_bus_v1_position_proto_depIdxs = []int32{
+	1, // 0: bus.v1.InfluencerPosition.side:type_name -> bus.v1.SignalSide
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_bus_v1_position_proto_init() }
+func file_bus_v1_position_proto_init() {
+	if File_bus_v1_position_proto != nil {
+		return
+	}
+	file_bus_v1_signal_proto_init()
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_position_proto_rawDesc), len(file_bus_v1_position_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bus_v1_position_proto_goTypes,
+		DependencyIndexes: file_bus_v1_position_proto_depIdxs,
+		MessageInfos:      file_bus_v1_position_proto_msgTypes,
+	}.Build()
+	File_bus_v1_position_proto = out.File
+	file_bus_v1_position_proto_goTypes = nil
+	file_bus_v1_position_proto_depIdxs = nil
+}
@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v6.33.4
+// source: bus/v1/raw_event.proto
+
+package busv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// RawEventType classifies the Hyperliquid payload captured in a raw event.
+type RawEventType int32
+
+const (
+	RawEventType_RAW_EVENT_TYPE_UNSPECIFIED       RawEventType = 0
+	RawEventType_RAW_EVENT_TYPE_FILL              RawEventType = 1
+	RawEventType_RAW_EVENT_TYPE_ORDER_UPDATE      RawEventType = 2
+	RawEventType_RAW_EVENT_TYPE_POSITION_SNAPSHOT RawEventType = 3
+	RawEventType_RAW_EVENT_TYPE_POSITION_UPDATE   RawEventType = 4
+	RawEventType_RAW_EVENT_TYPE_LIQUIDATION       RawEventType = 5
+	RawEventType_RAW_EVENT_TYPE_NON_USER_CANCEL   RawEventType = 6
+)
+
+// Enum value maps for RawEventType.
+var (
+	RawEventType_name = map[int32]string{
+		0: "RAW_EVENT_TYPE_UNSPECIFIED",
+		1: "RAW_EVENT_TYPE_FILL",
+		2: "RAW_EVENT_TYPE_ORDER_UPDATE",
+		3: "RAW_EVENT_TYPE_POSITION_SNAPSHOT",
+		4: "RAW_EVENT_TYPE_POSITION_UPDATE",
+		5: "RAW_EVENT_TYPE_LIQUIDATION",
+		6: "RAW_EVENT_TYPE_NON_USER_CANCEL",
+	}
+	RawEventType_value = map[string]int32{
+		"RAW_EVENT_TYPE_UNSPECIFIED":       0,
+		"RAW_EVENT_TYPE_FILL":              1,
+		"RAW_EVENT_TYPE_ORDER_UPDATE":      2,
+		"RAW_EVENT_TYPE_POSITION_SNAPSHOT": 3,
+		"RAW_EVENT_TYPE_POSITION_UPDATE":   4,
+		"RAW_EVENT_TYPE_LIQUIDATION":       5,
+		"RAW_EVENT_TYPE_NON_USER_CANCEL":   6,
+	}
+)
+
+func (x RawEventType) Enum() *RawEventType {
+	p := new(RawEventType)
+	*p = x
+	return p
+}
+
+func (x RawEventType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RawEventType) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_raw_event_proto_enumTypes[0].Descriptor()
+}
+
+func (RawEventType) Type() protoreflect.EnumType {
+	return &file_bus_v1_raw_event_proto_enumTypes[0]
+}
+
+func (x RawEventType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RawEventType.Descriptor instead.
+func (RawEventType) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_raw_event_proto_rawDescGZIP(), []int{0}
+}
+
+// RawEvent is an untouched Hyperliquid payload recorded by ingestion before it is
+// normalized, published to the raw_hyperliquid_events topic for audit and replay.
+type RawEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deterministic ID for the raw event (influencer + event type + source event ID).
+	EventId string `protobuf:"bytes,1,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	// Hyperliquid account/address the event belongs to.
+	InfluencerAddress string `protobuf:"bytes,2,opt,name=influencer_address,json=influencerAddress,proto3" json:"influencer_address,omitempty"`
+	// Exchange identifier, e.g. "hyperliquid".
+	Exchange string `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
+	// Canonical market as normalized by ingestion (e.g., "ETH-PERP").
+	Market string `protobuf:"bytes,4,opt,name=market,proto3" json:"market,omitempty"`
+	// Kind of payload: FILL, ORDER_UPDATE, POSITION_SNAPSHOT, POSITION_UPDATE,
+	// LIQUIDATION, NON_USER_CANCEL.
+	EventType RawEventType `protobuf:"varint,5,opt,name=event_type,json=eventType,proto3,enum=bus.v1.RawEventType" json:"event_type,omitempty"`
+	// Reference to the underlying Hyperliquid event (e.g., tx hash / trade ID).
+	SourceEventId string `protobuf:"bytes,6,opt,name=source_event_id,json=sourceEventId,proto3" json:"source_event_id,omitempty"`
+	// Event time from Hyperliquid in Unix millis.
+	ExchangeTimestampMs int64 `protobuf:"varint,7,opt,name=exchange_timestamp_ms,json=exchangeTimestampMs,proto3" json:"exchange_timestamp_ms,omitempty"`
+	// Time ingestion received the event in Unix millis.
+	IngestedAtMs int64 `protobuf:"varint,8,opt,name=ingested_at_ms,json=ingestedAtMs,proto3" json:"ingested_at_ms,omitempty"`
+	// Original JSON payload as received from Hyperliquid.
+	Payload       []byte `protobuf:"bytes,9,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RawEvent) Reset() {
+	*x = RawEvent{}
+	mi := &file_bus_v1_raw_event_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RawEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RawEvent) ProtoMessage() {}
+
+func (x *RawEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_bus_v1_raw_event_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RawEvent.ProtoReflect.Descriptor instead.
+func (*RawEvent) Descriptor() ([]byte, []int) {
+	return file_bus_v1_raw_event_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RawEvent) GetEventId() string {
+	if x != nil {
+		return x.EventId
+	}
+	return ""
+}
+
+func (x *RawEvent) GetInfluencerAddress() string {
+	if x != nil {
+		return x.InfluencerAddress
+	}
+	return ""
+}
+
+func (x *RawEvent) GetExchange() string {
+	if x != nil {
+		return x.Exchange
+	}
+	return ""
+}
+
+func (x *RawEvent) GetMarket() string {
+	if x != nil {
+		return x.Market
+	}
+	return ""
+}
+
+func (x *RawEvent) GetEventType() RawEventType {
+	if x != nil {
+		return x.EventType
+	}
+	return RawEventType_RAW_EVENT_TYPE_UNSPECIFIED
+}
+
+func (x *RawEvent) GetSourceEventId() string {
+	if x != nil {
+		return x.SourceEventId
+	}
+	return ""
+}
+
+func (x *RawEvent) GetExchangeTimestampMs() int64 {
+	if x != nil {
+		return x.ExchangeTimestampMs
+	}
+	return 0
+}
+
+func (x *RawEvent) GetIngestedAtMs() int64 {
+	if x != nil {
+		return x.IngestedAtMs
+	}
+	return 0
+}
+
+func (x *RawEvent) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+var File_bus_v1_raw_event_proto protoreflect.FileDescriptor
+
+const file_bus_v1_raw_event_proto_rawDesc = "" +
+	"\n" +
+	"\x16bus/v1/raw_event.proto\x12\x06bus.v1\"\xd9\x02\n" +
+	"\bRawEvent\x12\x19\n" +
+	"\bevent_id\x18\x01 \x01(\tR\aeventId\x12-\n" +
+	"\x12influencer_address\x18\x02 \x01(\tR\x11influencerAddress\x12\x1a\n" +
+	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x16\n" +
+	"\x06market\x18\x04 \x01(\tR\x06market\x123\n" +
+	"\n" +
+	"event_type\x18\x05 \x01(\x0e2\x14.bus.v1.RawEventTypeR\teventType\x12&\n" +
+	"\x0fsource_event_id\x18\x06 \x01(\tR\rsourceEventId\x122\n" +
+	"\x15exchange_timestamp_ms\x18\a \x01(\x03R\x13exchangeTimestampMs\x12$\n" +
+	"\x0eingested_at_ms\x18\b \x01(\x03R\fingestedAtMs\x12\x18\n" +
+	"\apayload\x18\t \x01(\fR\apayload*\xf6\x01\n" +
+	"\fRawEventType\x12\x1e\n" +
+	"\x1aRAW_EVENT_TYPE_UNSPECIFIED\x10\x00\x12\x17\n" +
+	"\x13RAW_EVENT_TYPE_FILL\x10\x01\x12\x1f\n" +
+	"\x1bRAW_EVENT_TYPE_ORDER_UPDATE\x10\x02\x12$\n" +
+	" RAW_EVENT_TYPE_POSITION_SNAPSHOT\x10\x03\x12\"\n" +
+	"\x1eRAW_EVENT_TYPE_POSITION_UPDATE\x10\x04\x12\x1e\n" +
+	"\x1aRAW_EVENT_TYPE_LIQUIDATION\x10\x05\x12\"\n" +
+	"\x1eRAW_EVENT_TYPE_NON_USER_CANCEL\x10\x06BEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
+
+var (
+	file_bus_v1_raw_event_proto_rawDescOnce sync.Once
+	file_bus_v1_raw_event_proto_rawDescData []byte
+)
+
+func file_bus_v1_raw_event_proto_rawDescGZIP() []byte {
+	file_bus_v1_raw_event_proto_rawDescOnce.Do(func() {
+		file_bus_v1_raw_event_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bus_v1_raw_event_proto_rawDesc), len(file_bus_v1_raw_event_proto_rawDesc)))
+	})
+	return file_bus_v1_raw_event_proto_rawDescData
+}
+
+var file_bus_v1_raw_event_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_bus_v1_raw_event_proto_msgTypes = make([]protoimpl.MessageInfo, 1)
+var file_bus_v1_raw_event_proto_goTypes = []any{
+	(RawEventType)(0), // 0: bus.v1.RawEventType
+	(*RawEvent)(nil),  // 1: bus.v1.RawEvent
+}
+var file_bus_v1_raw_event_proto_depIdxs = []int32{
+	0, // 0: bus.v1.RawEvent.event_type:type_name -> bus.v1.RawEventType
+	1, // [1:1] is the sub-list for method output_type
+	1, // [1:1] is the sub-list for method input_type
+	1, // [1:1] is the sub-list for extension type_name
+	1, // [1:1] is the sub-list for extension extendee
+	0, // [0:1] is the sub-list for field type_name
+}
+
+func init() { file_bus_v1_raw_event_proto_init() }
+func file_bus_v1_raw_event_proto_init() {
+	if File_bus_v1_raw_event_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_raw_event_proto_rawDesc), len(file_bus_v1_raw_event_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   1,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_bus_v1_raw_event_proto_goTypes,
+		DependencyIndexes: file_bus_v1_raw_event_proto_depIdxs,
+		EnumInfos:         file_bus_v1_raw_event_proto_enumTypes,
+		MessageInfos:      file_bus_v1_raw_event_proto_msgTypes,
+	}.Build()
+	File_bus_v1_raw_event_proto = out.File
+	file_bus_v1_raw_event_proto_goTypes = nil
+	file_bus_v1_raw_event_proto_depIdxs = nil
+}
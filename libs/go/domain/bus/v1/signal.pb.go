@@ -31,6 +31,9 @@ const (
 	SignalAction_SIGNAL_ACTION_INCREASE    SignalAction = 3
 	SignalAction_SIGNAL_ACTION_DECREASE    SignalAction = 4
 	SignalAction_SIGNAL_ACTION_FLIP        SignalAction = 5
+	// The position was reduced or closed by a liquidation rather than by the
+	// influencer; size/side still describe the resulting position.
+	SignalAction_SIGNAL_ACTION_LIQUIDATED SignalAction = 6
 )
 
 // Enum value maps for SignalAction.
@@ -42,6 +45,7 @@ var (
 		3: "SIGNAL_ACTION_INCREASE",
 		4: "SIGNAL_ACTION_DECREASE",
 		5: "SIGNAL_ACTION_FLIP",
+		6: "SIGNAL_ACTION_LIQUIDATED",
 	}
 	SignalAction_value = map[string]int32{
 		"SIGNAL_ACTION_UNSPECIFIED": 0,
@@ -50,6 +54,7 @@ var (
 		"SIGNAL_ACTION_INCREASE":    3,
 		"SIGNAL_ACTION_DECREASE":    4,
 		"SIGNAL_ACTION_FLIP":        5,
+		"SIGNAL_ACTION_LIQUIDATED":  6,
 	}
 )
 
@@ -133,6 +138,106 @@ func (SignalSide) EnumDescriptor() ([]byte, []int) {
 	return file_bus_v1_signal_proto_rawDescGZIP(), []int{1}
 }
 
+// MarginMode is the margin type of a position: cross or isolated.
+type MarginMode int32
+
+const (
+	MarginMode_MARGIN_MODE_UNSPECIFIED MarginMode = 0
+	MarginMode_MARGIN_MODE_CROSS       MarginMode = 1
+	MarginMode_MARGIN_MODE_ISOLATED    MarginMode = 2
+)
+
+// Enum value maps for MarginMode.
+var (
+	MarginMode_name = map[int32]string{
+		0: "MARGIN_MODE_UNSPECIFIED",
+		1: "MARGIN_MODE_CROSS",
+		2: "MARGIN_MODE_ISOLATED",
+	}
+	MarginMode_value = map[string]int32{
+		"MARGIN_MODE_UNSPECIFIED": 0,
+		"MARGIN_MODE_CROSS":       1,
+		"MARGIN_MODE_ISOLATED":    2,
+	}
+)
+
+func (x MarginMode) Enum() *MarginMode {
+	p := new(MarginMode)
+	*p = x
+	return p
+}
+
+func (x MarginMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MarginMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_signal_proto_enumTypes[2].Descriptor()
+}
+
+func (MarginMode) Type() protoreflect.EnumType {
+	return &file_bus_v1_signal_proto_enumTypes[2]
+}
+
+func (x MarginMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MarginMode.Descriptor instead.
+func (MarginMode) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_signal_proto_rawDescGZIP(), []int{2}
+}
+
+// MarketType distinguishes perpetual (including HIP-3) from spot markets.
+type MarketType int32
+
+const (
+	MarketType_MARKET_TYPE_UNSPECIFIED MarketType = 0
+	MarketType_MARKET_TYPE_PERP        MarketType = 1
+	MarketType_MARKET_TYPE_SPOT        MarketType = 2
+)
+
+// Enum value maps for MarketType.
+var (
+	MarketType_name = map[int32]string{
+		0: "MARKET_TYPE_UNSPECIFIED",
+		1: "MARKET_TYPE_PERP",
+		2: "MARKET_TYPE_SPOT",
+	}
+	MarketType_value = map[string]int32{
+		"MARKET_TYPE_UNSPECIFIED": 0,
+		"MARKET_TYPE_PERP":        1,
+		"MARKET_TYPE_SPOT":        2,
+	}
+)
+
+func (x MarketType) Enum() *MarketType {
+	p := new(MarketType)
+	*p = x
+	return p
+}
+
+func (x MarketType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MarketType) Descriptor() protoreflect.EnumDescriptor {
+	return file_bus_v1_signal_proto_enumTypes[3].Descriptor()
+}
+
+func (MarketType) Type() protoreflect.EnumType {
+	return &file_bus_v1_signal_proto_enumTypes[3]
+}
+
+func (x MarketType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MarketType.Descriptor instead.
+func (MarketType) EnumDescriptor() ([]byte, []int) {
+	return file_bus_v1_signal_proto_rawDescGZIP(), []int{3}
+}
+
 // Signal is the canonical normalized signal published to the influencer_signals topic.
 type Signal struct {
 	state protoimpl.MessageState `protogen:"open.v1"`
@@ -142,24 +247,59 @@ type Signal struct {
 	InfluencerId string `protobuf:"bytes,2,opt,name=influencer_id,json=influencerId,proto3" json:"influencer_id,omitempty"`
 	// Exchange identifier, e.g. "hyperliquid".
 	Exchange string `protobuf:"bytes,3,opt,name=exchange,proto3" json:"exchange,omitempty"`
-	// Hyperliquid market identifier (e.g., "ETH-PERP").
+	// Canonical market (e.g., "ETH-PERP", "HYPE/USDC-SPOT", "xyz:XYZ100-PERP").
 	Market string `protobuf:"bytes,4,opt,name=market,proto3" json:"market,omitempty"`
-	// Semantic action: OPEN, CLOSE, INCREASE, DECREASE, FLIP.
+	// Semantic action: OPEN, CLOSE, INCREASE, DECREASE, FLIP, LIQUIDATED.
 	Action SignalAction `protobuf:"varint,5,opt,name=action,proto3,enum=bus.v1.SignalAction" json:"action,omitempty"`
 	// Resulting position side after the event: LONG, SHORT, FLAT.
 	Side SignalSide `protobuf:"varint,6,opt,name=side,proto3,enum=bus.v1.SignalSide" json:"side,omitempty"`
 	// Resulting position size after applying this event (base units).
+	// Superseded by size_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 	Size float64 `protobuf:"fixed64,7,opt,name=size,proto3" json:"size,omitempty"`
 	// Signed change in position size from the previous state.
+	// Superseded by delta_size_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 	DeltaSize float64 `protobuf:"fixed64,8,opt,name=delta_size,json=deltaSize,proto3" json:"delta_size,omitempty"`
 	// Relevant execution or average entry price.
+	// Superseded by price_decimal.
+	//
+	// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 	Price float64 `protobuf:"fixed64,9,opt,name=price,proto3" json:"price,omitempty"`
 	// Event time from Hyperliquid in Unix millis.
 	TimestampMs int64 `protobuf:"varint,10,opt,name=timestamp_ms,json=timestampMs,proto3" json:"timestamp_ms,omitempty"`
 	// Reference to the underlying Hyperliquid event (e.g., tx hash / event ID / sequence).
 	SourceEventId string `protobuf:"bytes,11,opt,name=source_event_id,json=sourceEventId,proto3" json:"source_event_id,omitempty"`
 	// Optional free-form metadata (e.g., leverage, margin mode, raw symbol).
-	Metadata      map[string]string `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Metadata map[string]string `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// USD notional of the change, |delta_size| * price. 0 when price is unknown.
+	NotionalUsd float64 `protobuf:"fixed64,13,opt,name=notional_usd,json=notionalUsd,proto3" json:"notional_usd,omitempty"`
+	// Maximum leverage the market allows (0 for spot and when market metadata is
+	// unavailable).
+	MaxLeverage int32 `protobuf:"varint,14,opt,name=max_leverage,json=maxLeverage,proto3" json:"max_leverage,omitempty"`
+	// Number of decimals allowed in order sizes for the market (lot size is
+	// 10^-size_decimals). Only meaningful when market metadata is available.
+	SizeDecimals int32 `protobuf:"varint,15,opt,name=size_decimals,json=sizeDecimals,proto3" json:"size_decimals,omitempty"`
+	// Whether the market only supports isolated margin.
+	OnlyIsolated bool `protobuf:"varint,16,opt,name=only_isolated,json=onlyIsolated,proto3" json:"only_isolated,omitempty"`
+	// Influencer's leverage on the market at the time of the signal (0 when unknown).
+	Leverage float64 `protobuf:"fixed64,17,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	// Influencer's margin mode on the market at the time of the signal.
+	MarginMode MarginMode `protobuf:"varint,18,opt,name=margin_mode,json=marginMode,proto3,enum=bus.v1.MarginMode" json:"margin_mode,omitempty"`
+	// Whether market is a perpetual or spot market.
+	MarketType MarketType `protobuf:"varint,19,opt,name=market_type,json=marketType,proto3,enum=bus.v1.MarketType" json:"market_type,omitempty"`
+	// Exact decimal strings (e.g. "2012.5") of size, delta_size and price, as
+	// produced by numbers.Decimal.String. Empty from producers predating them.
+	SizeDecimal      string `protobuf:"bytes,20,opt,name=size_decimal,json=sizeDecimal,proto3" json:"size_decimal,omitempty"`
+	DeltaSizeDecimal string `protobuf:"bytes,21,opt,name=delta_size_decimal,json=deltaSizeDecimal,proto3" json:"delta_size_decimal,omitempty"`
+	PriceDecimal     string `protobuf:"bytes,22,opt,name=price_decimal,json=priceDecimal,proto3" json:"price_decimal,omitempty"`
+	// Monotonic number per influencer+market starting at 1, assigned just before
+	// publishing, so consumers can detect gaps and out-of-order delivery. 0 when
+	// it could not be assigned. A publish that fails after assignment leaves a
+	// gap; the retried signal gets a new number.
+	Sequence      int64 `protobuf:"varint,23,opt,name=sequence,proto3" json:"sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -236,6 +376,7 @@ func (x *Signal) GetSide() SignalSide {
 	return SignalSide_SIGNAL_SIDE_UNSPECIFIED
 }
 
+// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 func (x *Signal) GetSize() float64 {
 	if x != nil {
 		return x.Size
@@ -243,6 +384,7 @@ func (x *Signal) GetSize() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 func (x *Signal) GetDeltaSize() float64 {
 	if x != nil {
 		return x.DeltaSize
@@ -250,6 +392,7 @@ func (x *Signal) GetDeltaSize() float64 {
 	return 0
 }
 
+// Deprecated: Marked as deprecated in bus/v1/signal.proto.
 func (x *Signal) GetPrice() float64 {
 	if x != nil {
 		return x.Price
@@ -278,42 +421,143 @@ func (x *Signal) GetMetadata() map[string]string {
 	return nil
 }
 
+func (x *Signal) GetNotionalUsd() float64 {
+	if x != nil {
+		return x.NotionalUsd
+	}
+	return 0
+}
+
+func (x *Signal) GetMaxLeverage() int32 {
+	if x != nil {
+		return x.MaxLeverage
+	}
+	return 0
+}
+
+func (x *Signal) GetSizeDecimals() int32 {
+	if x != nil {
+		return x.SizeDecimals
+	}
+	return 0
+}
+
+func (x *Signal) GetOnlyIsolated() bool {
+	if x != nil {
+		return x.OnlyIsolated
+	}
+	return false
+}
+
+func (x *Signal) GetLeverage() float64 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+func (x *Signal) GetMarginMode() MarginMode {
+	if x != nil {
+		return x.MarginMode
+	}
+	return MarginMode_MARGIN_MODE_UNSPECIFIED
+}
+
+func (x *Signal) GetMarketType() MarketType {
+	if x != nil {
+		return x.MarketType
+	}
+	return MarketType_MARKET_TYPE_UNSPECIFIED
+}
+
+func (x *Signal) GetSizeDecimal() string {
+	if x != nil {
+		return x.SizeDecimal
+	}
+	return ""
+}
+
+func (x *Signal) GetDeltaSizeDecimal() string {
+	if x != nil {
+		return x.DeltaSizeDecimal
+	}
+	return ""
+}
+
+func (x *Signal) GetPriceDecimal() string {
+	if x != nil {
+		return x.PriceDecimal
+	}
+	return ""
+}
+
+func (x *Signal) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
 var File_bus_v1_signal_proto protoreflect.FileDescriptor
 
 const file_bus_v1_signal_proto_rawDesc = "" +
 	"\n" +
-	"\x13bus/v1/signal.proto\x12\x06bus.v1\"\xdf\x03\n" +
+	"\x13bus/v1/signal.proto\x12\x06bus.v1\"\x93\a\n" +
 	"\x06Signal\x12\x1b\n" +
 	"\tsignal_id\x18\x01 \x01(\tR\bsignalId\x12#\n" +
 	"\rinfluencer_id\x18\x02 \x01(\tR\finfluencerId\x12\x1a\n" +
 	"\bexchange\x18\x03 \x01(\tR\bexchange\x12\x16\n" +
 	"\x06market\x18\x04 \x01(\tR\x06market\x12,\n" +
 	"\x06action\x18\x05 \x01(\x0e2\x14.bus.v1.SignalActionR\x06action\x12&\n" +
-	"\x04side\x18\x06 \x01(\x0e2\x12.bus.v1.SignalSideR\x04side\x12\x12\n" +
-	"\x04size\x18\a \x01(\x01R\x04size\x12\x1d\n" +
+	"\x04side\x18\x06 \x01(\x0e2\x12.bus.v1.SignalSideR\x04side\x12\x16\n" +
+	"\x04size\x18\a \x01(\x01B\x02\x18\x01R\x04size\x12!\n" +
 	"\n" +
-	"delta_size\x18\b \x01(\x01R\tdeltaSize\x12\x14\n" +
-	"\x05price\x18\t \x01(\x01R\x05price\x12!\n" +
+	"delta_size\x18\b \x01(\x01B\x02\x18\x01R\tdeltaSize\x12\x18\n" +
+	"\x05price\x18\t \x01(\x01B\x02\x18\x01R\x05price\x12!\n" +
 	"\ftimestamp_ms\x18\n" +
 	" \x01(\x03R\vtimestampMs\x12&\n" +
 	"\x0fsource_event_id\x18\v \x01(\tR\rsourceEventId\x128\n" +
-	"\bmetadata\x18\f \x03(\v2\x1c.bus.v1.Signal.MetadataEntryR\bmetadata\x1a;\n" +
+	"\bmetadata\x18\f \x03(\v2\x1c.bus.v1.Signal.MetadataEntryR\bmetadata\x12!\n" +
+	"\fnotional_usd\x18\r \x01(\x01R\vnotionalUsd\x12!\n" +
+	"\fmax_leverage\x18\x0e \x01(\x05R\vmaxLeverage\x12#\n" +
+	"\rsize_decimals\x18\x0f \x01(\x05R\fsizeDecimals\x12#\n" +
+	"\ronly_isolated\x18\x10 \x01(\bR\fonlyIsolated\x12\x1a\n" +
+	"\bleverage\x18\x11 \x01(\x01R\bleverage\x123\n" +
+	"\vmargin_mode\x18\x12 \x01(\x0e2\x12.bus.v1.MarginModeR\n" +
+	"marginMode\x123\n" +
+	"\vmarket_type\x18\x13 \x01(\x0e2\x12.bus.v1.MarketTypeR\n" +
+	"marketType\x12!\n" +
+	"\fsize_decimal\x18\x14 \x01(\tR\vsizeDecimal\x12,\n" +
+	"\x12delta_size_decimal\x18\x15 \x01(\tR\x10deltaSizeDecimal\x12#\n" +
+	"\rprice_decimal\x18\x16 \x01(\tR\fpriceDecimal\x12\x1a\n" +
+	"\bsequence\x18\x17 \x01(\x03R\bsequence\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xae\x01\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01*\xcc\x01\n" +
 	"\fSignalAction\x12\x1d\n" +
 	"\x19SIGNAL_ACTION_UNSPECIFIED\x10\x00\x12\x16\n" +
 	"\x12SIGNAL_ACTION_OPEN\x10\x01\x12\x17\n" +
 	"\x13SIGNAL_ACTION_CLOSE\x10\x02\x12\x1a\n" +
 	"\x16SIGNAL_ACTION_INCREASE\x10\x03\x12\x1a\n" +
 	"\x16SIGNAL_ACTION_DECREASE\x10\x04\x12\x16\n" +
-	"\x12SIGNAL_ACTION_FLIP\x10\x05*l\n" +
+	"\x12SIGNAL_ACTION_FLIP\x10\x05\x12\x1c\n" +
+	"\x18SIGNAL_ACTION_LIQUIDATED\x10\x06*l\n" +
 	"\n" +
 	"SignalSide\x12\x1b\n" +
 	"\x17SIGNAL_SIDE_UNSPECIFIED\x10\x00\x12\x14\n" +
 	"\x10SIGNAL_SIDE_LONG\x10\x01\x12\x15\n" +
 	"\x11SIGNAL_SIDE_SHORT\x10\x02\x12\x14\n" +
-	"\x10SIGNAL_SIDE_FLAT\x10\x03BEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
+	"\x10SIGNAL_SIDE_FLAT\x10\x03*Z\n" +
+	"\n" +
+	"MarginMode\x12\x1b\n" +
+	"\x17MARGIN_MODE_UNSPECIFIED\x10\x00\x12\x15\n" +
+	"\x11MARGIN_MODE_CROSS\x10\x01\x12\x18\n" +
+	"\x14MARGIN_MODE_ISOLATED\x10\x02*U\n" +
+	"\n" +
+	"MarketType\x12\x1b\n" +
+	"\x17MARKET_TYPE_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10MARKET_TYPE_PERP\x10\x01\x12\x14\n" +
+	"\x10MARKET_TYPE_SPOT\x10\x02BEZCgithub.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1;busv1b\x06proto3"
 
 var (
 	file_bus_v1_signal_proto_rawDescOnce sync.Once
@@ -327,23 +571,27 @@ func file_bus_v1_signal_proto_rawDescGZIP() []byte {
 	return file_bus_v1_signal_proto_rawDescData
 }
 
-var file_bus_v1_signal_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_bus_v1_signal_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
 var file_bus_v1_signal_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
 var file_bus_v1_signal_proto_goTypes = []any{
 	(SignalAction)(0), // 0: bus.v1.SignalAction
 	(SignalSide)(0),   // 1: bus.v1.SignalSide
-	(*Signal)(nil),    // 2: bus.v1.Signal
-	nil,               // 3: bus.v1.Signal.MetadataEntry
+	(MarginMode)(0),   // 2: bus.v1.MarginMode
+	(MarketType)(0),   // 3: bus.v1.MarketType
+	(*Signal)(nil),    // 4: bus.v1.Signal
+	nil,               // 5: bus.v1.Signal.MetadataEntry
 }
 var file_bus_v1_signal_proto_depIdxs = []int32{
 	0, // 0: bus.v1.Signal.action:type_name -> bus.v1.SignalAction
 	1, // 1: bus.v1.Signal.side:type_name -> bus.v1.SignalSide
-	3, // 2: bus.v1.Signal.metadata:type_name -> bus.v1.Signal.MetadataEntry
-	3, // [3:3] is the sub-list for method output_type
-	3, // [3:3] is the sub-list for method input_type
-	3, // [3:3] is the sub-list for extension type_name
-	3, // [3:3] is the sub-list for extension extendee
-	0, // [0:3] is the sub-list for field type_name
+	5, // 2: bus.v1.Signal.metadata:type_name -> bus.v1.Signal.MetadataEntry
+	2, // 3: bus.v1.Signal.margin_mode:type_name -> bus.v1.MarginMode
+	3, // 4: bus.v1.Signal.market_type:type_name -> bus.v1.MarketType
+	5, // [5:5] is the sub-list for method output_type
+	5, // [5:5] is the sub-list for method input_type
+	5, // [5:5] is the sub-list for extension type_name
+	5, // [5:5] is the sub-list for extension extendee
+	0, // [0:5] is the sub-list for field type_name
 }
 
 func init() { file_bus_v1_signal_proto_init() }
@@ -356,7 +604,7 @@ func file_bus_v1_signal_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bus_v1_signal_proto_rawDesc), len(file_bus_v1_signal_proto_rawDesc)),
-			NumEnums:      2,
+			NumEnums:      4,
 			NumMessages:   2,
 			NumExtensions: 0,
 			NumServices:   0,
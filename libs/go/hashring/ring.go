@@ -0,0 +1,83 @@
+package hashring
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// DefaultReplicas is the number of virtual nodes placed on the ring per member
+// when callers do not specify one.
+const DefaultReplicas = 64
+
+// Ring is an immutable consistent hash ring. Adding or removing a member only
+// moves the keys owned by that member's virtual nodes.
+type Ring struct {
+	points []uint64
+	owners map[uint64]string
+}
+
+// New builds a ring with the given number of virtual nodes per member.
+// Duplicate and empty members are ignored.
+func New(replicas int, members ...string) *Ring {
+	if replicas <= 0 {
+		replicas = DefaultReplicas
+	}
+	r := &Ring{owners: make(map[uint64]string, replicas*len(members))}
+	seen := make(map[string]struct{}, len(members))
+	for _, m := range members {
+		if m == "" {
+			continue
+		}
+		if _, ok := seen[m]; ok {
+			continue
+		}
+		seen[m] = struct{}{}
+		for i := 0; i < replicas; i++ {
+			point := hashKey(m + "#" + strconv.Itoa(i))
+			// On the (unlikely) collision keep the lexically smallest member so
+			// every instance derives the same ring from the same membership.
+			if current, ok := r.owners[point]; ok && current <= m {
+				continue
+			}
+			if _, ok := r.owners[point]; !ok {
+				r.points = append(r.points, point)
+			}
+			r.owners[point] = m
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// Owner returns the member responsible for key, or "" when the ring is empty.
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hashKey(key)
+	idx := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if idx == len(r.points) {
+		idx = 0
+	}
+	return r.owners[r.points[idx]]
+}
+
+// Len returns the number of virtual nodes on the ring.
+func (r *Ring) Len() int {
+	return len(r.points)
+}
+
+// hashKey applies a splitmix64 finalizer on top of FNV-1a so that keys which
+// only differ in their last bytes (e.g. virtual node suffixes) spread evenly.
+func hashKey(key string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
@@ -0,0 +1,132 @@
+package hashring
+
+import (
+	"fmt"
+	"testing"
+)
+
+func members(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("ingestion-%d", i)
+	}
+	return out
+}
+
+func keys(n int) []string {
+	out := make([]string, n)
+	for i := range out {
+		out[i] = fmt.Sprintf("0x%040x", i)
+	}
+	return out
+}
+
+func owners(r *Ring, keys []string) map[string]string {
+	out := make(map[string]string, len(keys))
+	for _, k := range keys {
+		out[k] = r.Owner(k)
+	}
+	return out
+}
+
+func TestRingDistribution(t *testing.T) {
+	tests := []struct {
+		members int
+		// tolerance is the largest allowed deviation from an even share.
+		tolerance float64
+	}{
+		{members: 1, tolerance: 0},
+		{members: 2, tolerance: 0.25},
+		{members: 3, tolerance: 0.25},
+		{members: 5, tolerance: 0.3},
+		{members: 10, tolerance: 0.4},
+	}
+	ks := keys(10000)
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d members", tt.members), func(t *testing.T) {
+			r := New(DefaultReplicas, members(tt.members)...)
+			if r.Len() != tt.members*DefaultReplicas {
+				t.Fatalf("Len = %d, want %d virtual nodes", r.Len(), tt.members*DefaultReplicas)
+			}
+			counts := map[string]int{}
+			for _, owner := range owners(r, ks) {
+				counts[owner]++
+			}
+			if len(counts) != tt.members {
+				t.Fatalf("keys owned by %v, want every member", counts)
+			}
+			even := float64(len(ks)) / float64(tt.members)
+			for m, n := range counts {
+				if dev := float64(n)/even - 1; dev > tt.tolerance || dev < -tt.tolerance {
+					t.Errorf("%s owns %d keys, want %.0f ± %.0f%%", m, n, even, tt.tolerance*100)
+				}
+			}
+		})
+	}
+}
+
+func TestRingStability(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []string
+		after  []string
+	}{
+		{name: "member added", before: members(3), after: members(4)},
+		{name: "member removed", before: members(4), after: members(3)},
+		{name: "middle member removed", before: members(5), after: []string{"ingestion-0", "ingestion-1", "ingestion-3", "ingestion-4"}},
+	}
+	ks := keys(10000)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := owners(New(0, tt.before...), ks)
+			after := owners(New(0, tt.after...), ks)
+			inBefore, inAfter := map[string]bool{}, map[string]bool{}
+			for _, m := range tt.before {
+				inBefore[m] = true
+			}
+			for _, m := range tt.after {
+				inAfter[m] = true
+			}
+			moved := 0
+			for _, k := range ks {
+				if before[k] == after[k] {
+					continue
+				}
+				moved++
+				// A key only moves to a joining member or away from a leaving one.
+				if inBefore[after[k]] && inAfter[before[k]] {
+					t.Fatalf("key %s moved from %s to %s, both members before and after", k, before[k], after[k])
+				}
+			}
+			// Roughly the joining or leaving member's share moves.
+			larger := max(len(tt.before), len(tt.after))
+			if share := float64(len(ks)) / float64(larger); float64(moved) > 1.5*share {
+				t.Fatalf("%d keys moved, want about %.0f", moved, share)
+			}
+		})
+	}
+}
+
+func TestRingIgnoresMemberOrderAndDuplicates(t *testing.T) {
+	ks := keys(1000)
+	want := owners(New(0, "a", "b", "c"), ks)
+	for _, ms := range [][]string{
+		{"c", "a", "b"},
+		{"b", "", "c", "a", "b"},
+	} {
+		got := owners(New(0, ms...), ks)
+		for _, k := range ks {
+			if got[k] != want[k] {
+				t.Fatalf("members %q: %s owned by %s, want %s", ms, k, got[k], want[k])
+			}
+		}
+	}
+}
+
+func TestEmptyRing(t *testing.T) {
+	for _, r := range []*Ring{New(0), New(8, "")} {
+		if r.Len() != 0 || r.Owner("0xabc") != "" {
+			t.Fatalf("empty ring: Len %d, Owner %q", r.Len(), r.Owner("0xabc"))
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// CheckKafkaBrokers returns a readiness check that passes when at least one of
+// brokers accepts a connection.
+func CheckKafkaBrokers(brokers []string) Check {
+	return func(ctx context.Context) error {
+		var errs []error
+		for _, broker := range brokers {
+			conn, err := kafka.DialContext(ctx, "tcp", broker)
+			if err == nil {
+				return conn.Close()
+			}
+			errs = append(errs, err)
+		}
+		return fmt.Errorf("no reachable kafka broker: %w", errors.Join(errs...))
+	}
+}
@@ -0,0 +1,134 @@
+package markets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// spotAssetOffset is added to a spot pair index to form its asset index.
+const spotAssetOffset = 10000
+
+// HyperliquidLoader loads perpetual and spot markets from the Hyperliquid info
+// endpoint's "meta" and "spotMeta" requests.
+type HyperliquidLoader struct {
+	url    string
+	client *http.Client
+}
+
+// NewHyperliquidLoader builds a loader for the API base URL, e.g.
+// https://api.hyperliquid.xyz.
+func NewHyperliquidLoader(apiURL string) *HyperliquidLoader {
+	return &HyperliquidLoader{
+		url:    strings.TrimRight(apiURL, "/") + "/info",
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type hyperliquidMeta struct {
+	Universe []struct {
+		Name         string `json:"name"`
+		SzDecimals   int    `json:"szDecimals"`
+		MaxLeverage  int    `json:"maxLeverage"`
+		OnlyIsolated bool   `json:"onlyIsolated"`
+		IsDelisted   bool   `json:"isDelisted"`
+	} `json:"universe"`
+}
+
+type hyperliquidSpotMeta struct {
+	Tokens []struct {
+		Name       string `json:"name"`
+		Index      int    `json:"index"`
+		SzDecimals int    `json:"szDecimals"`
+	} `json:"tokens"`
+	Universe []struct {
+		// Name is "@<index>" except for a few legacy pairs named "BASE/QUOTE".
+		Name   string `json:"name"`
+		Index  int    `json:"index"`
+		Tokens [2]int `json:"tokens"`
+	} `json:"universe"`
+}
+
+// Load returns every listed perpetual market followed by every spot pair.
+// Delisted perps are skipped but keep their position in the asset index
+// numbering.
+func (l *HyperliquidLoader) Load(ctx context.Context) ([]Market, error) {
+	var meta hyperliquidMeta
+	if err := l.info(ctx, "meta", &meta); err != nil {
+		return nil, err
+	}
+	var spot hyperliquidSpotMeta
+	if err := l.info(ctx, "spotMeta", &spot); err != nil {
+		return nil, err
+	}
+
+	out := make([]Market, 0, len(meta.Universe)+len(spot.Universe))
+	for i, u := range meta.Universe {
+		if u.IsDelisted {
+			continue
+		}
+		out = append(out, Market{
+			Name:         u.Name,
+			Symbol:       PerpSymbol(u.Name),
+			Type:         TypePerp,
+			Index:        i,
+			SzDecimals:   u.SzDecimals,
+			MaxLeverage:  u.MaxLeverage,
+			OnlyIsolated: u.OnlyIsolated,
+		})
+	}
+
+	type token struct {
+		name       string
+		szDecimals int
+	}
+	tokens := make(map[int]token, len(spot.Tokens))
+	for _, t := range spot.Tokens {
+		tokens[t.Index] = token{name: t.Name, szDecimals: t.SzDecimals}
+	}
+	for _, u := range spot.Universe {
+		base, okBase := tokens[u.Tokens[0]]
+		quote, okQuote := tokens[u.Tokens[1]]
+		if !okBase || !okQuote {
+			continue
+		}
+		out = append(out, Market{
+			Name:       u.Name,
+			Symbol:     SpotSymbol(base.name, quote.name),
+			Type:       TypeSpot,
+			Index:      spotAssetOffset + u.Index,
+			SzDecimals: base.szDecimals,
+		})
+	}
+	return out, nil
+}
+
+// info posts {"type": typ} to the info endpoint and decodes the response into out.
+func (l *HyperliquidLoader) info(ctx context.Context, typ string, out any) error {
+	body, err := json.Marshal(map[string]string{"type": typ})
+	if err != nil {
+		return fmt.Errorf("encode %s request: %w", typ, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, l.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", typ, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := l.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s request: %w", typ, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s request: unexpected status %s", typ, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode %s response: %w", typ, err)
+	}
+	return nil
+}
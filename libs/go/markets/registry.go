@@ -0,0 +1,186 @@
+package markets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+)
+
+// ErrUnknownMarket is returned when a market is not present in the registry.
+var ErrUnknownMarket = errors.New("markets: unknown market")
+
+// Market is the static trading metadata of a perpetual or spot market.
+type Market struct {
+	// Name is the exchange coin identifier, e.g. "BTC", "PURR/USDC" or "@107".
+	Name string
+	// Symbol is the canonical market, e.g. "BTC-PERP" or "HYPE/USDC-SPOT".
+	Symbol string
+	// Type is the kind of market.
+	Type Type
+	// Index is the asset index used by the exchange's order API.
+	Index int
+	// SzDecimals is the number of decimals allowed in order sizes.
+	SzDecimals int
+	// MaxLeverage is the highest leverage the market allows; 0 for spot.
+	MaxLeverage int
+	// OnlyIsolated reports markets that cannot be traded with cross margin.
+	OnlyIsolated bool
+}
+
+// LotSize is the smallest size increment, 10^-SzDecimals.
+func (m Market) LotSize() float64 {
+	return math.Pow10(-m.SzDecimals)
+}
+
+// PriceDecimals is the maximum number of decimals allowed in prices (6 minus
+// SzDecimals for perps, 8 minus SzDecimals for spot). Hyperliquid additionally
+// limits prices to five significant figures.
+func (m Market) PriceDecimals() int {
+	if m.Type == TypeSpot {
+		return max(0, 8-m.SzDecimals)
+	}
+	return max(0, 6-m.SzDecimals)
+}
+
+// RoundSize truncates size to the market's lot size so an order never exceeds
+// the requested size.
+func (m Market) RoundSize(size numbers.Decimal) numbers.Decimal {
+	return size.Truncate(int32(m.SzDecimals))
+}
+
+// RoundPrice rounds px to a valid price: five significant figures and at most
+// PriceDecimals decimals. Integer prices are always valid.
+func (m Market) RoundPrice(px numbers.Decimal) numbers.Decimal {
+	if px.Equal(px.Truncate(0)) {
+		return px
+	}
+	return px.RoundSignificant(5).Round(int32(m.PriceDecimals()))
+}
+
+// Loader fetches the current market list from an exchange.
+type Loader interface {
+	Load(ctx context.Context) ([]Market, error)
+}
+
+// Registry is a concurrency-safe, periodically refreshed view of market
+// metadata keyed by upper-cased exchange name and canonical symbol.
+type Registry struct {
+	loader Loader
+
+	mu       sync.RWMutex
+	byName   map[string]Market
+	loadedAt time.Time
+
+	// loaded is closed by the first successful refresh.
+	loaded     chan struct{}
+	loadedOnce sync.Once
+}
+
+func NewRegistry(loader Loader) *Registry {
+	return &Registry{loader: loader, byName: map[string]Market{}, loaded: make(chan struct{})}
+}
+
+// Refresh reloads all markets. On error the previous snapshot is kept.
+func (r *Registry) Refresh(ctx context.Context) error {
+	list, err := r.loader.Load(ctx)
+	if err != nil {
+		return err
+	}
+	byName := make(map[string]Market, 2*len(list))
+	for _, m := range list {
+		byName[strings.ToUpper(m.Name)] = m
+		if m.Symbol != "" {
+			byName[strings.ToUpper(m.Symbol)] = m
+		}
+	}
+
+	r.mu.Lock()
+	r.byName = byName
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+	r.loadedOnce.Do(func() { close(r.loaded) })
+	return nil
+}
+
+// Wait blocks until the registry has been refreshed successfully once or ctx
+// is done. A nil registry is never loaded and returns immediately.
+func (r *Registry) Wait(ctx context.Context) error {
+	if r == nil {
+		return nil
+	}
+	select {
+	case <-r.loaded:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run refreshes the registry immediately and then every interval until ctx is
+// done. Refresh errors are passed to onError (if set) and do not stop the loop.
+// An interval of zero or less refreshes until the first success, retrying
+// with exponential backoff, and returns.
+func (r *Registry) Run(ctx context.Context, interval time.Duration, onError func(error)) {
+	if interval <= 0 {
+		backoff := time.Second
+		for {
+			err := r.Refresh(ctx)
+			if err == nil || ctx.Err() != nil {
+				return
+			}
+			if onError != nil {
+				onError(err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(backoff):
+			}
+			backoff = min(backoff*2, time.Minute)
+		}
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := r.Refresh(ctx); err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Lookup returns the metadata for an exchange name or canonical symbol
+// (case-insensitive).
+func (r *Registry) Lookup(name string) (Market, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m, ok := r.byName[strings.ToUpper(name)]
+	return m, ok
+}
+
+// Get is like Lookup but reports a missing market as ErrUnknownMarket.
+func (r *Registry) Get(name string) (Market, error) {
+	m, ok := r.Lookup(name)
+	if !ok {
+		return Market{}, fmt.Errorf("%w: %s", ErrUnknownMarket, name)
+	}
+	return m, nil
+}
+
+// LoadedAt returns when the registry was last refreshed successfully; zero if
+// it never was.
+func (r *Registry) LoadedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.loadedAt
+}
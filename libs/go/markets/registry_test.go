@@ -0,0 +1,103 @@
+package markets
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type loaderFunc func(ctx context.Context) ([]Market, error)
+
+func (f loaderFunc) Load(ctx context.Context) ([]Market, error) { return f(ctx) }
+
+func TestRegistryRunWithoutIntervalRefreshesOnce(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		var loads atomic.Int32
+		r := NewRegistry(loaderFunc(func(context.Context) ([]Market, error) {
+			loads.Add(1)
+			return []Market{{Name: "ETH", Symbol: "ETH-PERP", Type: TypePerp}}, nil
+		}))
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			r.Run(context.Background(), interval, nil)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatalf("Run(%v) did not return", interval)
+		}
+		if loads.Load() != 1 {
+			t.Fatalf("Run(%v) loaded %d times, want 1", interval, loads.Load())
+		}
+		if _, ok := r.Lookup("eth"); !ok {
+			t.Fatalf("Run(%v) did not load markets", interval)
+		}
+	}
+}
+
+func TestRegistryRunReportsErrorsAndKeepsRefreshing(t *testing.T) {
+	var loads atomic.Int32
+	r := NewRegistry(loaderFunc(func(context.Context) ([]Market, error) {
+		if loads.Add(1) == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return []Market{{Name: "BTC", Symbol: "BTC-PERP", Type: TypePerp}}, nil
+	}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errs := make(chan error, 1)
+	go r.Run(ctx, 5*time.Millisecond, func(err error) { errs <- err })
+
+	select {
+	case <-errs:
+	case <-time.After(time.Second):
+		t.Fatal("refresh error was not reported")
+	}
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, ok := r.Lookup("BTC-PERP"); ok {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("registry was not refreshed after the error")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRegistryWaitBlocksUntilLoaded(t *testing.T) {
+	fail := atomic.Bool{}
+	fail.Store(true)
+	r := NewRegistry(loaderFunc(func(context.Context) ([]Market, error) {
+		if fail.Load() {
+			return nil, errors.New("unavailable")
+		}
+		return []Market{{Name: "@107", Symbol: "HYPE/USDC-SPOT", Type: TypeSpot}}, nil
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := r.Refresh(ctx); err == nil {
+		t.Fatal("Refresh succeeded, want error")
+	}
+	if err := r.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Wait on an unloaded registry = %v, want deadline exceeded", err)
+	}
+
+	fail.Store(false)
+	if err := r.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	if err := r.Wait(context.Background()); err != nil {
+		t.Fatalf("Wait on a loaded registry: %v", err)
+	}
+	var nilRegistry *Registry
+	if err := nilRegistry.Wait(ctx); err != nil {
+		t.Fatalf("Wait on a nil registry: %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+package markets
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type distinguishes perpetual from spot markets.
+type Type string
+
+const (
+	TypePerp Type = "PERP"
+	TypeSpot Type = "SPOT"
+)
+
+// Symbol is the canonical form of an exchange coin identifier.
+type Symbol struct {
+	// Coin is the identifier as sent by the exchange, e.g. "@107" or "xyz:XYZ100".
+	Coin string
+	// Market is the canonical market, e.g. "ETH-PERP", "HYPE/USDC-SPOT" or
+	// "xyz:XYZ100-PERP".
+	Market string
+	Type   Type
+	// Dex is the HIP-3 builder-deployed perp dex; empty for the default dex.
+	Dex string
+}
+
+// Normalize maps a Hyperliquid coin identifier to its canonical market:
+//
+//   - "ETH" (default-dex perp) becomes "ETH-PERP";
+//   - "xyz:XYZ100" (HIP-3 perp) becomes "xyz:XYZ100-PERP";
+//   - "@107" or "PURR/USDC" (spot) becomes "<BASE>/<QUOTE>-SPOT" using the spot
+//     metadata, or "<coin>-SPOT" when the pair is not loaded.
+//
+// r may be nil, in which case only the structural rules apply.
+func (r *Registry) Normalize(coin string) Symbol {
+	if r != nil {
+		if m, ok := r.Lookup(coin); ok && m.Symbol != "" {
+			return Symbol{Coin: coin, Market: m.Symbol, Type: m.Type}
+		}
+	}
+	if dex, name, ok := strings.Cut(coin, ":"); ok {
+		return Symbol{Coin: coin, Market: dex + ":" + name + "-PERP", Type: TypePerp, Dex: dex}
+	}
+	if strings.HasPrefix(coin, "@") || strings.Contains(coin, "/") {
+		return Symbol{Coin: coin, Market: strings.ToUpper(coin) + "-SPOT", Type: TypeSpot}
+	}
+	return Symbol{Coin: coin, Market: PerpSymbol(coin), Type: TypePerp}
+}
+
+// Resolve is like Normalize but fails with ErrUnknownMarket for spot index
+// coins ("@107") that are not in the registry, whose structural fallback
+// ("@107-SPOT") would name the market differently once metadata is loaded.
+// Callers should Wait for the registry before resolving.
+func (r *Registry) Resolve(coin string) (Symbol, error) {
+	sym := r.Normalize(coin)
+	if strings.HasPrefix(coin, "@") && sym.Market == strings.ToUpper(coin)+"-SPOT" {
+		return sym, fmt.Errorf("%w: %s", ErrUnknownMarket, coin)
+	}
+	return sym, nil
+}
+
+// PerpSymbol is the canonical market of a default-dex perp coin.
+func PerpSymbol(coin string) string {
+	return strings.ToUpper(coin) + "-PERP"
+}
+
+// SpotSymbol is the canonical market of a spot pair.
+func SpotSymbol(base, quote string) string {
+	return strings.ToUpper(base) + "/" + strings.ToUpper(quote) + "-SPOT"
+}
@@ -0,0 +1,47 @@
+package markets
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestRegistryResolve(t *testing.T) {
+	loaded := NewRegistry(loaderFunc(func(context.Context) ([]Market, error) {
+		return []Market{{Name: "@107", Symbol: "HYPE/USDC-SPOT", Type: TypeSpot}}, nil
+	}))
+	if err := loaded.Refresh(context.Background()); err != nil {
+		t.Fatalf("Refresh: %v", err)
+	}
+	empty := NewRegistry(loaderFunc(func(context.Context) ([]Market, error) { return nil, nil }))
+
+	tests := []struct {
+		name     string
+		registry *Registry
+		coin     string
+		want     string
+		wantErr  bool
+	}{
+		{name: "perp without metadata", registry: empty, coin: "ETH", want: "ETH-PERP"},
+		{name: "hip-3 perp without metadata", registry: empty, coin: "xyz:XYZ100", want: "xyz:XYZ100-PERP"},
+		{name: "spot pair name without metadata", registry: empty, coin: "PURR/USDC", want: "PURR/USDC-SPOT"},
+		{name: "spot index from metadata", registry: loaded, coin: "@107", want: "HYPE/USDC-SPOT"},
+		{name: "spot index without metadata", registry: empty, coin: "@107", wantErr: true},
+		{name: "spot index on a nil registry", coin: "@107", wantErr: true},
+		{name: "unlisted spot index", registry: loaded, coin: "@999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sym, err := tt.registry.Resolve(tt.coin)
+			if tt.wantErr {
+				if !errors.Is(err, ErrUnknownMarket) {
+					t.Fatalf("Resolve(%q) = %+v, %v, want ErrUnknownMarket", tt.coin, sym, err)
+				}
+				return
+			}
+			if err != nil || sym.Market != tt.want {
+				t.Fatalf("Resolve(%q) = %q, %v, want %q", tt.coin, sym.Market, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package messaging
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrUndecodable marks a message whose payload cannot be decoded. Redelivery
+// cannot fix it, so consumers log it and commit past it instead of stopping.
+var ErrUndecodable = errors.New("undecodable message")
+
+// Message is one bus record. Topic, Partition, Offset, HighWaterMark and Time
+// are set on consumed messages and ignored when publishing.
+type Message struct {
+	Key     []byte
+	Value   []byte
+	Headers []Header
+
+	Topic     string
+	Partition int
+	Offset    int64
+	// HighWaterMark is the offset the next message written to the partition
+	// will get, as of this fetch; HighWaterMark-Offset-1 is the consumer lag.
+	HighWaterMark int64
+	Time          time.Time
+}
+
+// Lag returns how many messages of the partition follow m.
+func (m Message) Lag() int64 {
+	return max(m.HighWaterMark-m.Offset-1, 0)
+}
+
+// Publisher writes messages to one topic. Messages with the same key go to
+// the same partition and keep their publish order.
+type Publisher interface {
+	// Publish writes msgs as one batch and returns once the bus accepted all
+	// of them.
+	Publish(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Consumer reads one topic as a member of a consumer group. Each partition is
+// read by one member of the group at a time, in offset order.
+type Consumer interface {
+	// Fetch blocks until the next message is available or ctx is done.
+	Fetch(ctx context.Context) (Message, error)
+	// Commit acknowledges msgs. After a restart or rebalance the group resumes
+	// each partition after its highest committed offset, so uncommitted
+	// messages are delivered again.
+	Commit(ctx context.Context, msgs ...Message) error
+	// CheckMembership fails unless the consumer is currently a member of its
+	// group, e.g. for a readiness check.
+	CheckMembership(ctx context.Context) error
+	Close() error
+}
+
+// Consume fetches messages and passes each to handler, committing it once the
+// handler returns nil. A handler error wrapping ErrUndecodable also commits the
+// message, which the handler is expected to have logged or dead-lettered; any
+// other error stops the loop uncommitted. It returns nil when ctx is done.
+func Consume(ctx context.Context, c Consumer, handler func(context.Context, Message) error) error {
+	for {
+		msg, err := c.Fetch(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		if err := handler(ctx, msg); err != nil && !errors.Is(err, ErrUndecodable) {
+			return err
+		}
+		if err := c.Commit(ctx, msg); err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package messaging is the message bus abstraction shared by the services:
+// the Publisher and Consumer interfaces, their typed protobuf wrappers and
+// the standard message headers. Kafka implements them in package kafkabus and
+// an in-memory partitioned bus, for single-process tests, in package membus.
+package messaging
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// Standard bus message header names.
+const (
+	HeaderTraceParent      = "traceparent"
+	HeaderTraceState       = "tracestate"
+	HeaderSchema           = "schema"
+	HeaderSchemaVersion    = "schema_version"
+	HeaderContentType      = "content-type"
+	HeaderProducerService  = "producer_service"
+	HeaderProducerInstance = "producer_instance"
+	HeaderProducedAt       = "produced_at_ms"
+)
+
+// ContentTypeProtobuf is the content type of binary protobuf payloads.
+const ContentTypeProtobuf = "application/x-protobuf"
+
+// Header is one message header, mirroring the Kafka record header.
+type Header struct {
+	Key   string
+	Value []byte
+}
+
+// Producer identifies the service instance writing a message.
+type Producer struct {
+	Service  string
+	Instance string
+}
+
+// Headers are the standard headers of a bus message.
+type Headers struct {
+	Trace         trace.SpanContext
+	Schema        string
+	SchemaVersion string
+	ContentType   string
+	Producer      Producer
+	ProducedAt    time.Time
+}
+
+// NewHeaders builds the headers for a protobuf message m produced now. The
+// message carries the span in ctx (normally the producer span), or starts a
+// new trace when ctx has none.
+func NewHeaders(ctx context.Context, producer Producer, m proto.Message, now time.Time) Headers {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		sc = NewTrace()
+	}
+	schema, version := SchemaOf(m)
+	return Headers{
+		Trace:         sc,
+		Schema:        schema,
+		SchemaVersion: version,
+		ContentType:   ContentTypeProtobuf,
+		Producer:      producer,
+		ProducedAt:    now,
+	}
+}
+
+// SchemaOf returns the full protobuf name of m (e.g. "bus.v1.Signal") and the
+// version segment of its package (e.g. "v1").
+func SchemaOf(m proto.Message) (schema, version string) {
+	schema = string(proto.MessageName(m))
+	for _, part := range strings.Split(schema, ".") {
+		if len(part) > 1 && part[0] == 'v' && strings.Trim(part[1:], "0123456789") == "" {
+			version = part
+		}
+	}
+	return schema, version
+}
+
+// Encode returns the non-empty headers in a stable order.
+func (h Headers) Encode() []Header {
+	out := make([]Header, 0, 8)
+	add := func(key, value string) {
+		if value != "" {
+			out = append(out, Header{Key: key, Value: []byte(value)})
+		}
+	}
+	traceparent, tracestate := EncodeTrace(h.Trace)
+	add(HeaderTraceParent, traceparent)
+	add(HeaderTraceState, tracestate)
+	add(HeaderSchema, h.Schema)
+	add(HeaderSchemaVersion, h.SchemaVersion)
+	add(HeaderContentType, h.ContentType)
+	add(HeaderProducerService, h.Producer.Service)
+	add(HeaderProducerInstance, h.Producer.Instance)
+	if !h.ProducedAt.IsZero() {
+		add(HeaderProducedAt, strconv.FormatInt(h.ProducedAt.UnixMilli(), 10))
+	}
+	return out
+}
+
+// DecodeHeaders reads the standard headers, ignoring unknown and malformed
+// ones so messages from older producers are still accepted.
+func DecodeHeaders(headers []Header) Headers {
+	var h Headers
+	var traceparent, tracestate string
+	for _, hd := range headers {
+		v := string(hd.Value)
+		switch hd.Key {
+		case HeaderTraceParent:
+			traceparent = v
+		case HeaderTraceState:
+			tracestate = v
+		case HeaderSchema:
+			h.Schema = v
+		case HeaderSchemaVersion:
+			h.SchemaVersion = v
+		case HeaderContentType:
+			h.ContentType = v
+		case HeaderProducerService:
+			h.Producer.Service = v
+		case HeaderProducerInstance:
+			h.Producer.Instance = v
+		case HeaderProducedAt:
+			if ms, err := strconv.ParseInt(v, 10, 64); err == nil {
+				h.ProducedAt = time.UnixMilli(ms).UTC()
+			}
+		}
+	}
+	h.Trace = DecodeTrace(traceparent, tracestate)
+	return h
+}
+
+// Context returns ctx with the message's trace context as the remote parent,
+// so spans started while handling it continue the trace.
+func (h Headers) Context(ctx context.Context) context.Context {
+	if !h.Trace.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, h.Trace)
+}
+
+// Latency returns how long ago the message was produced, or zero when the
+// producer did not set produced_at_ms.
+func (h Headers) Latency(now time.Time) time.Duration {
+	if h.ProducedAt.IsZero() {
+		return 0
+	}
+	return now.Sub(h.ProducedAt)
+}
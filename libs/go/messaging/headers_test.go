@@ -0,0 +1,66 @@
+package messaging
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestHeadersRoundTrip(t *testing.T) {
+	state, err := trace.ParseTraceState("vendor=value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9},
+		SpanID:     trace.SpanID{0x00, 0xf0},
+		TraceFlags: trace.FlagsSampled,
+		TraceState: state,
+	})
+	now := time.UnixMilli(1_700_000_000_000).UTC()
+	h := NewHeaders(trace.ContextWithSpanContext(context.Background(), sc), Producer{Service: "ingestion", Instance: "i-1"}, &busv1.Signal{}, now)
+
+	got := DecodeHeaders(h.Encode())
+	if !got.Trace.Equal(sc.WithRemote(true)) {
+		t.Fatalf("trace = %v, want %v", got.Trace, sc)
+	}
+	if got.Schema != "bus.v1.Signal" || got.SchemaVersion != "v1" || got.ContentType != ContentTypeProtobuf {
+		t.Errorf("schema headers = %+v", got)
+	}
+	if got.Producer != h.Producer || !got.ProducedAt.Equal(now) {
+		t.Errorf("producer headers = %+v", got)
+	}
+}
+
+func TestNewHeadersStartsTrace(t *testing.T) {
+	h := NewHeaders(context.Background(), Producer{}, &busv1.Signal{}, time.Now())
+	if !h.Trace.IsValid() || !h.Trace.IsSampled() {
+		t.Fatalf("trace = %v, want a new sampled trace", h.Trace)
+	}
+}
+
+func TestDecodeHeadersIgnoresMalformedTrace(t *testing.T) {
+	tests := []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e473z-00f067aa0ba902b7-01",
+	}
+	for _, tp := range tests {
+		h := DecodeHeaders([]Header{{Key: HeaderTraceParent, Value: []byte(tp)}, {Key: HeaderSchema, Value: []byte("bus.v1.Signal")}})
+		if h.Trace.IsValid() {
+			t.Errorf("traceparent %q decoded as %v", tp, h.Trace)
+		}
+		if h.Schema != "bus.v1.Signal" {
+			t.Errorf("traceparent %q dropped the other headers", tp)
+		}
+	}
+	h := DecodeHeaders([]Header{{Key: HeaderTraceParent, Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")}})
+	if h.Trace.TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || !h.Trace.IsRemote() {
+		t.Fatalf("trace = %v", h.Trace)
+	}
+}
@@ -0,0 +1,189 @@
+// Package kafkabus implements the messaging Publisher and Consumer on Kafka
+// with segmentio/kafka-go.
+package kafkabus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/segmentio/kafka-go"
+)
+
+// PublisherConfig configures a Publisher.
+type PublisherConfig struct {
+	Brokers []string
+	Topic   string
+	// AutoCreateTopic lets the first write create a missing topic with the
+	// broker defaults.
+	AutoCreateTopic bool
+}
+
+// Publisher writes to a Kafka topic, waiting for all in-sync replicas. Keys
+// are hashed to partitions.
+type Publisher struct {
+	writer *kafka.Writer
+}
+
+var _ messaging.Publisher = (*Publisher)(nil)
+
+func NewPublisher(cfg PublisherConfig) *Publisher {
+	return &Publisher{writer: &kafka.Writer{
+		Addr:                   kafka.TCP(cfg.Brokers...),
+		Topic:                  cfg.Topic,
+		RequiredAcks:           kafka.RequireAll,
+		Balancer:               &kafka.Hash{},
+		AllowAutoTopicCreation: cfg.AutoCreateTopic,
+	}}
+}
+
+func (p *Publisher) Publish(ctx context.Context, msgs ...messaging.Message) error {
+	out := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		out[i] = kafka.Message{Key: m.Key, Value: m.Value, Headers: toKafkaHeaders(m.Headers)}
+	}
+	if err := p.writer.WriteMessages(ctx, out...); err != nil {
+		return fmt.Errorf("kafka write: %w", err)
+	}
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	return p.writer.Close()
+}
+
+// ConsumerConfig configures a Consumer.
+type ConsumerConfig struct {
+	Brokers []string
+	Topic   string
+	GroupID string
+	// ClientID identifies the member in the consumer group.
+	ClientID string
+}
+
+// Consumer reads a Kafka topic as a consumer group member. Offsets are
+// committed only by Commit.
+type Consumer struct {
+	reader   *kafka.Reader
+	brokers  []string
+	groupID  string
+	clientID string
+}
+
+var _ messaging.Consumer = (*Consumer)(nil)
+
+func NewConsumer(cfg ConsumerConfig) *Consumer {
+	return &Consumer{
+		reader: kafka.NewReader(kafka.ReaderConfig{
+			Brokers: cfg.Brokers,
+			GroupID: cfg.GroupID,
+			Topic:   cfg.Topic,
+			Dialer: &kafka.Dialer{
+				ClientID:  cfg.ClientID,
+				Timeout:   10 * time.Second,
+				DualStack: true,
+			},
+		}),
+		brokers:  cfg.Brokers,
+		groupID:  cfg.GroupID,
+		clientID: cfg.ClientID,
+	}
+}
+
+func (c *Consumer) Fetch(ctx context.Context) (messaging.Message, error) {
+	msg, err := c.reader.FetchMessage(ctx)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return messaging.Message{}, err
+		}
+		return messaging.Message{}, fmt.Errorf("kafka read: %w", err)
+	}
+	headers := make([]messaging.Header, len(msg.Headers))
+	for i, h := range msg.Headers {
+		headers[i] = messaging.Header{Key: h.Key, Value: h.Value}
+	}
+	return messaging.Message{
+		Key:           msg.Key,
+		Value:         msg.Value,
+		Headers:       headers,
+		Topic:         msg.Topic,
+		Partition:     msg.Partition,
+		Offset:        msg.Offset,
+		HighWaterMark: msg.HighWaterMark,
+		Time:          msg.Time,
+	}, nil
+}
+
+func (c *Consumer) Commit(ctx context.Context, msgs ...messaging.Message) error {
+	out := make([]kafka.Message, len(msgs))
+	for i, m := range msgs {
+		out[i] = kafka.Message{Topic: m.Topic, Partition: m.Partition, Offset: m.Offset}
+	}
+	if err := c.reader.CommitMessages(ctx, out...); err != nil {
+		return fmt.Errorf("kafka commit: %w", err)
+	}
+	return nil
+}
+
+// CheckMembership fails unless the consumer group is stable and has a member
+// with the consumer's client ID, so the ClientID must be unique per member.
+func (c *Consumer) CheckMembership(ctx context.Context) error {
+	client := &kafka.Client{Addr: kafka.TCP(c.brokers...)}
+	resp, err := client.DescribeGroups(ctx, &kafka.DescribeGroupsRequest{GroupIDs: []string{c.groupID}})
+	if err != nil {
+		return fmt.Errorf("describe consumer group %s: %w", c.groupID, err)
+	}
+	for _, group := range resp.Groups {
+		if group.Error != nil {
+			return fmt.Errorf("describe consumer group %s: %w", c.groupID, group.Error)
+		}
+		if group.GroupState != "Stable" {
+			return fmt.Errorf("consumer group %s is %s", c.groupID, group.GroupState)
+		}
+		for _, member := range group.Members {
+			if member.ClientID == c.clientID {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("client %s is not a member of consumer group %s", c.clientID, c.groupID)
+}
+
+func (c *Consumer) Close() error {
+	return c.reader.Close()
+}
+
+// EnsureCompactedTopic creates topic with cleanup.policy=compact and the
+// broker's default partitions and replication unless it already exists.
+// Auto-creation would use the delete policy, so publishers to compacted
+// topics should not enable AutoCreateTopic.
+func EnsureCompactedTopic(ctx context.Context, brokers []string, topic string) error {
+	client := &kafka.Client{Addr: kafka.TCP(brokers...)}
+	res, err := client.CreateTopics(ctx, &kafka.CreateTopicsRequest{
+		Topics: []kafka.TopicConfig{{
+			Topic:             topic,
+			NumPartitions:     -1,
+			ReplicationFactor: -1,
+			ConfigEntries: []kafka.ConfigEntry{
+				{ConfigName: "cleanup.policy", ConfigValue: "compact"},
+			},
+		}},
+	})
+	if err != nil {
+		return fmt.Errorf("create topic %s: %w", topic, err)
+	}
+	if err := res.Errors[topic]; err != nil && !errors.Is(err, kafka.TopicAlreadyExists) {
+		return fmt.Errorf("create topic %s: %w", topic, err)
+	}
+	return nil
+}
+
+func toKafkaHeaders(hs []messaging.Header) []kafka.Header {
+	out := make([]kafka.Header, len(hs))
+	for i, h := range hs {
+		out[i] = kafka.Header{Key: h.Key, Value: h.Value}
+	}
+	return out
+}
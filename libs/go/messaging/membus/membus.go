@@ -0,0 +1,277 @@
+// Package membus is an in-memory, partitioned implementation of the messaging
+// Publisher and Consumer. It follows Kafka's ordering and delivery semantics
+// closely enough to run producers and consumers of several services in one
+// process, e.g. the ingestion → matcher flow in a test, without a broker.
+package membus
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"hash/fnv"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// ErrClosed is returned by a closed Publisher or Consumer.
+var ErrClosed = errors.New("membus: closed")
+
+// Bus holds topics in memory. Topics are created on first use with the bus's
+// partition count and keep every message for the life of the Bus.
+type Bus struct {
+	partitions int
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	// changed is closed and replaced whenever a message is written or a group
+	// rebalances, waking blocked Fetch calls.
+	changed chan struct{}
+}
+
+type topic struct {
+	partitions [][]messaging.Message
+	// next is the partition of the next keyless message.
+	next   int
+	groups map[string]*group
+}
+
+type group struct {
+	committed []int64
+	members   []*Consumer
+}
+
+// New returns an empty Bus whose topics have the given number of partitions
+// (at least one).
+func New(partitions int) *Bus {
+	return &Bus{
+		partitions: max(partitions, 1),
+		topics:     make(map[string]*topic),
+		changed:    make(chan struct{}),
+	}
+}
+
+// topic returns the named topic, creating it. b.mu must be held.
+func (b *Bus) topic(name string) *topic {
+	t, ok := b.topics[name]
+	if !ok {
+		t = &topic{partitions: make([][]messaging.Message, b.partitions), groups: make(map[string]*group)}
+		b.topics[name] = t
+	}
+	return t
+}
+
+// notify wakes blocked fetches. b.mu must be held.
+func (b *Bus) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+// Messages returns the messages written to topic so far, by partition and
+// offset.
+func (b *Bus) Messages(topic string) []messaging.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var out []messaging.Message
+	if t, ok := b.topics[topic]; ok {
+		for _, p := range t.partitions {
+			out = append(out, p...)
+		}
+	}
+	return out
+}
+
+// Publisher returns a publisher for topic. Keyed messages are hashed to a
+// partition (FNV-1a, as kafka-go's Hash balancer does); keyless ones are
+// spread round-robin.
+func (b *Bus) Publisher(topic string) *Publisher {
+	return &Publisher{bus: b, topic: topic}
+}
+
+// Publisher writes to one topic of a Bus.
+type Publisher struct {
+	bus   *Bus
+	topic string
+
+	mu     sync.Mutex
+	closed bool
+}
+
+var _ messaging.Publisher = (*Publisher)(nil)
+
+func (p *Publisher) Publish(ctx context.Context, msgs ...messaging.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.mu.Lock()
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return ErrClosed
+	}
+
+	b := p.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(p.topic)
+	now := time.Now().UTC()
+	for _, m := range msgs {
+		var part int
+		if len(m.Key) > 0 {
+			h := fnv.New32a()
+			_, _ = h.Write(m.Key)
+			part = int(h.Sum32() % uint32(len(t.partitions)))
+		} else {
+			part = t.next
+			t.next = (t.next + 1) % len(t.partitions)
+		}
+		t.partitions[part] = append(t.partitions[part], messaging.Message{
+			Key:       bytes.Clone(m.Key),
+			Value:     bytes.Clone(m.Value),
+			Headers:   slices.Clone(m.Headers),
+			Topic:     p.topic,
+			Partition: part,
+			Offset:    int64(len(t.partitions[part])),
+			Time:      now,
+		})
+	}
+	b.notify()
+	return nil
+}
+
+func (p *Publisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.closed = true
+	return nil
+}
+
+// Consumer joins groupID on topic. Partitions are split among the group's
+// open members (partition i goes to member i mod n). When a member joins or
+// closes, every member resumes from the group's committed offsets, so
+// uncommitted messages are delivered again, as after a Kafka rebalance.
+func (b *Bus) Consumer(topic, groupID string) *Consumer {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t := b.topic(topic)
+	g, ok := t.groups[groupID]
+	if !ok {
+		g = &group{committed: make([]int64, len(t.partitions))}
+		t.groups[groupID] = g
+	}
+	c := &Consumer{bus: b, topic: t, group: g, position: make([]int64, len(t.partitions))}
+	g.members = append(g.members, c)
+	b.rebalance(g)
+	return c
+}
+
+// rebalance rewinds every member of g to the committed offsets. b.mu must be
+// held.
+func (b *Bus) rebalance(g *group) {
+	for _, m := range g.members {
+		copy(m.position, g.committed)
+	}
+	b.notify()
+}
+
+// Consumer reads one topic of a Bus as a consumer group member.
+type Consumer struct {
+	bus   *Bus
+	topic *topic
+	group *group
+
+	// Guarded by bus.mu.
+	position []int64
+	cursor   int
+	closed   bool
+}
+
+var _ messaging.Consumer = (*Consumer)(nil)
+
+func (c *Consumer) Fetch(ctx context.Context) (messaging.Message, error) {
+	b := c.bus
+	for {
+		b.mu.Lock()
+		if c.closed {
+			b.mu.Unlock()
+			return messaging.Message{}, ErrClosed
+		}
+		if msg, ok := c.next(); ok {
+			b.mu.Unlock()
+			return msg, nil
+		}
+		wait := b.changed
+		b.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return messaging.Message{}, ctx.Err()
+		case <-wait:
+		}
+	}
+}
+
+// next returns the next message of an assigned partition, rotating between
+// partitions so none is starved. bus.mu must be held.
+func (c *Consumer) next() (messaging.Message, bool) {
+	member := slices.Index(c.group.members, c)
+	n := len(c.group.members)
+	parts := len(c.topic.partitions)
+	for i := range parts {
+		p := (c.cursor + i) % parts
+		if p%n != member || c.position[p] >= int64(len(c.topic.partitions[p])) {
+			continue
+		}
+		msg := c.topic.partitions[p][c.position[p]]
+		msg.HighWaterMark = int64(len(c.topic.partitions[p]))
+		c.position[p]++
+		c.cursor = p + 1
+		return msg, true
+	}
+	return messaging.Message{}, false
+}
+
+func (c *Consumer) Commit(ctx context.Context, msgs ...messaging.Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	for _, m := range msgs {
+		if m.Partition < 0 || m.Partition >= len(c.group.committed) {
+			continue
+		}
+		c.group.committed[m.Partition] = max(c.group.committed[m.Partition], m.Offset+1)
+	}
+	return nil
+}
+
+// CheckMembership fails once the consumer is closed; an open consumer is
+// always a member of its group.
+func (c *Consumer) CheckMembership(context.Context) error {
+	c.bus.mu.Lock()
+	defer c.bus.mu.Unlock()
+	if c.closed {
+		return ErrClosed
+	}
+	return nil
+}
+
+// Close leaves the group, handing its partitions to the remaining members.
+func (c *Consumer) Close() error {
+	b := c.bus
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	c.group.members = slices.DeleteFunc(c.group.members, func(m *Consumer) bool { return m == c })
+	b.rebalance(c.group)
+	return nil
+}
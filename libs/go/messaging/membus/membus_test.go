@@ -0,0 +1,222 @@
+package membus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+func publish(t *testing.T, p *Publisher, key string, values ...string) {
+	t.Helper()
+	for _, v := range values {
+		msg := messaging.Message{Value: []byte(v)}
+		if key != "" {
+			msg.Key = []byte(key)
+		}
+		if err := p.Publish(context.Background(), msg); err != nil {
+			t.Fatalf("Publish: %v", err)
+		}
+	}
+}
+
+// drain fetches until nothing arrives for a short while.
+func drain(t *testing.T, c *Consumer) []messaging.Message {
+	t.Helper()
+	var out []messaging.Message
+	for {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		msg, err := c.Fetch(ctx)
+		cancel()
+		if errors.Is(err, context.DeadlineExceeded) {
+			return out
+		}
+		if err != nil {
+			t.Fatalf("Fetch: %v", err)
+		}
+		out = append(out, msg)
+	}
+}
+
+func values(msgs []messaging.Message) []string {
+	out := make([]string, len(msgs))
+	for i, m := range msgs {
+		out[i] = string(m.Value)
+	}
+	return out
+}
+
+func TestPublishPartitionsByKey(t *testing.T) {
+	bus := New(4)
+	pub := bus.Publisher("signals")
+	for i := range 20 {
+		publish(t, pub, fmt.Sprintf("key-%d", i%5), fmt.Sprintf("v%d", i))
+	}
+	publish(t, pub, "", "a", "b", "c", "d")
+
+	partitionOf := map[string]int{}
+	offsets := map[int]int64{}
+	keyless := map[int]bool{}
+	for _, m := range bus.Messages("signals") {
+		if m.Offset != offsets[m.Partition] {
+			t.Fatalf("partition %d: offset %d, want %d", m.Partition, m.Offset, offsets[m.Partition])
+		}
+		offsets[m.Partition]++
+		if len(m.Key) == 0 {
+			keyless[m.Partition] = true
+			continue
+		}
+		if p, ok := partitionOf[string(m.Key)]; ok && p != m.Partition {
+			t.Fatalf("key %s written to partitions %d and %d", m.Key, p, m.Partition)
+		}
+		partitionOf[string(m.Key)] = m.Partition
+	}
+	if len(keyless) != 4 {
+		t.Fatalf("keyless messages went to partitions %v, want one each round-robin", keyless)
+	}
+
+	// A single member reads every partition, each in offset order.
+	c := bus.Consumer("signals", "group")
+	got := drain(t, c)
+	if len(got) != 24 {
+		t.Fatalf("fetched %d messages, want 24", len(got))
+	}
+	last := map[int]int64{}
+	for _, m := range got {
+		if prev, ok := last[m.Partition]; ok && m.Offset != prev+1 {
+			t.Fatalf("partition %d: offset %d after %d", m.Partition, m.Offset, prev)
+		}
+		last[m.Partition] = m.Offset
+		if m.HighWaterMark != offsets[m.Partition] || m.Topic != "signals" {
+			t.Fatalf("message %+v, want topic signals and high-water mark %d", m, offsets[m.Partition])
+		}
+	}
+}
+
+func TestConsumerGroupsAreIndependent(t *testing.T) {
+	bus := New(2)
+	publish(t, bus.Publisher("signals"), "k", "1", "2")
+	a := bus.Consumer("signals", "a")
+	b := bus.Consumer("signals", "b")
+	if got := values(drain(t, a)); !slices.Equal(got, []string{"1", "2"}) {
+		t.Fatalf("group a = %v", got)
+	}
+	if got := values(drain(t, b)); !slices.Equal(got, []string{"1", "2"}) {
+		t.Fatalf("group b = %v", got)
+	}
+}
+
+func TestCommitAndRedelivery(t *testing.T) {
+	bus := New(1)
+	publish(t, bus.Publisher("signals"), "k", "1", "2", "3", "4")
+
+	c := bus.Consumer("signals", "group")
+	got := drain(t, c)
+	// Commits are cumulative and never move back.
+	if err := c.Commit(context.Background(), got[1]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit(context.Background(), got[0]); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Commit(context.Background(), got[3]); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Commit after Close = %v, want ErrClosed", err)
+	}
+	if _, err := c.Fetch(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("Fetch after Close = %v, want ErrClosed", err)
+	}
+	if err := c.CheckMembership(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Fatalf("CheckMembership after Close = %v, want ErrClosed", err)
+	}
+
+	restarted := bus.Consumer("signals", "group")
+	if err := restarted.CheckMembership(context.Background()); err != nil {
+		t.Fatalf("CheckMembership: %v", err)
+	}
+	if got := values(drain(t, restarted)); !slices.Equal(got, []string{"3", "4"}) {
+		t.Fatalf("after restart = %v, want the uncommitted messages", got)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	bus := New(4)
+	pub := bus.Publisher("signals")
+	publish(t, pub, "", "a", "b", "c", "d")
+
+	first := bus.Consumer("signals", "group")
+	got := drain(t, first)
+	if len(got) != 4 {
+		t.Fatalf("sole member fetched %d messages, want 4", len(got))
+	}
+	// Only partitions 0 and 1 are committed before a second member joins.
+	if err := first.Commit(context.Background(), got[0], got[1]); err != nil {
+		t.Fatal(err)
+	}
+	committed := []int{got[0].Partition, got[1].Partition}
+
+	second := bus.Consumer("signals", "group")
+	// Both members rewind to the committed offsets and split the partitions.
+	redelivered := append(drain(t, first), drain(t, second)...)
+	if len(redelivered) != 2 {
+		t.Fatalf("redelivered %v, want the two uncommitted messages", values(redelivered))
+	}
+	for _, m := range redelivered {
+		if slices.Contains(committed, m.Partition) {
+			t.Fatalf("redelivered committed partition %d", m.Partition)
+		}
+	}
+
+	publish(t, pub, "", "e", "f", "g", "h")
+	firstParts, secondParts := map[int]bool{}, map[int]bool{}
+	for _, m := range drain(t, first) {
+		firstParts[m.Partition] = true
+	}
+	for _, m := range drain(t, second) {
+		secondParts[m.Partition] = true
+	}
+	if len(firstParts) != 2 || len(secondParts) != 2 {
+		t.Fatalf("partitions split %v / %v, want two each", firstParts, secondParts)
+	}
+	for p := range firstParts {
+		if secondParts[p] {
+			t.Fatalf("partition %d read by both members", p)
+		}
+	}
+
+	// When a member leaves, the other takes over its partitions from the
+	// committed offsets.
+	if err := second.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got := drain(t, first); len(got) != 6 {
+		t.Fatalf("after the other member left fetched %v, want every uncommitted message", values(got))
+	}
+}
+
+func TestFetchWaitsForPublish(t *testing.T) {
+	bus := New(1)
+	c := bus.Consumer("signals", "group")
+	fetched := make(chan messaging.Message, 1)
+	go func() {
+		msg, err := c.Fetch(context.Background())
+		if err == nil {
+			fetched <- msg
+		}
+	}()
+	publish(t, bus.Publisher("signals"), "k", "late")
+	select {
+	case msg := <-fetched:
+		if string(msg.Value) != "late" {
+			t.Fatalf("fetched %q", msg.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Fetch did not wake up on publish")
+	}
+}
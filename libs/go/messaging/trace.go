@@ -0,0 +1,38 @@
+package messaging
+
+import (
+	"context"
+	"crypto/rand"
+
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// propagator reads and writes the W3C Trace Context
+// (https://www.w3.org/TR/trace-context/) traceparent and tracestate values
+// carried by bus messages.
+var propagator = propagation.TraceContext{}
+
+// NewTrace starts a sampled trace with random IDs.
+func NewTrace() trace.SpanContext {
+	var cfg trace.SpanContextConfig
+	_, _ = rand.Read(cfg.TraceID[:])
+	_, _ = rand.Read(cfg.SpanID[:])
+	cfg.TraceFlags = trace.FlagsSampled
+	return trace.NewSpanContext(cfg)
+}
+
+// EncodeTrace returns the traceparent and tracestate values of sc. Both are
+// empty when sc is not valid.
+func EncodeTrace(sc trace.SpanContext) (traceparent, tracestate string) {
+	carrier := propagation.MapCarrier{}
+	propagator.Inject(trace.ContextWithSpanContext(context.Background(), sc), carrier)
+	return carrier.Get(HeaderTraceParent), carrier.Get(HeaderTraceState)
+}
+
+// DecodeTrace parses traceparent and tracestate values into a remote span
+// context. The result is not valid when traceparent is missing or malformed.
+func DecodeTrace(traceparent, tracestate string) trace.SpanContext {
+	carrier := propagation.MapCarrier{HeaderTraceParent: traceparent, HeaderTraceState: tracestate}
+	return trace.SpanContextFromContext(propagator.Extract(context.Background(), carrier))
+}
@@ -0,0 +1,149 @@
+package messaging
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/protobuf/proto"
+)
+
+// TypedPublisher publishes protobuf messages of type T with the standard
+// headers, keyed by a function of the message.
+type TypedPublisher[T proto.Message] struct {
+	pub      Publisher
+	producer Producer
+	key      func(T) string
+}
+
+// NewTypedPublisher wraps pub. key picks the partitioning key of a message.
+func NewTypedPublisher[T proto.Message](pub Publisher, producer Producer, key func(T) string) *TypedPublisher[T] {
+	return &TypedPublisher[T]{pub: pub, producer: producer, key: key}
+}
+
+// Traced is a message to publish with the span its bus message continues.
+type Traced[T proto.Message] struct {
+	Message T
+	Trace   trace.SpanContext
+}
+
+// Publish writes m, continuing the trace carried by ctx.
+func (p *TypedPublisher[T]) Publish(ctx context.Context, m T) error {
+	msg, err := p.encode(ctx, m)
+	if err != nil {
+		return err
+	}
+	return p.pub.Publish(ctx, msg)
+}
+
+// PublishBatch writes msgs in one batch. Each continues its own trace, or the
+// one carried by ctx when it has none.
+func (p *TypedPublisher[T]) PublishBatch(ctx context.Context, msgs []Traced[T]) error {
+	out := make([]Message, 0, len(msgs))
+	for _, m := range msgs {
+		msgCtx := ctx
+		if m.Trace.IsValid() {
+			msgCtx = trace.ContextWithRemoteSpanContext(ctx, m.Trace)
+		}
+		msg, err := p.encode(msgCtx, m.Message)
+		if err != nil {
+			return err
+		}
+		out = append(out, msg)
+	}
+	return p.pub.Publish(ctx, out...)
+}
+
+func (p *TypedPublisher[T]) encode(ctx context.Context, m T) (Message, error) {
+	value, err := proto.Marshal(m)
+	if err != nil {
+		schema, _ := SchemaOf(m)
+		return Message{}, fmt.Errorf("marshal %s: %w", schema, err)
+	}
+	return Message{
+		Key:     []byte(p.key(m)),
+		Value:   value,
+		Headers: NewHeaders(ctx, p.producer, m, time.Now().UTC()).Encode(),
+	}, nil
+}
+
+// Close closes the underlying publisher.
+func (p *TypedPublisher[T]) Close() error {
+	return p.pub.Close()
+}
+
+// Delivery is a consumed message decoded as T.
+type Delivery[T proto.Message] struct {
+	Message T
+	Headers Headers
+	Raw     Message
+}
+
+// TypedConsumer consumes protobuf messages of type T.
+type TypedConsumer[T proto.Message] struct {
+	consumer Consumer
+	logger   *slog.Logger
+}
+
+// NewTypedConsumer wraps c. Consume logs the messages it skips to logger.
+func NewTypedConsumer[T proto.Message](c Consumer, logger *slog.Logger) *TypedConsumer[T] {
+	return &TypedConsumer[T]{consumer: c, logger: logger}
+}
+
+// Fetch returns the next message. A payload that does not decode as T is an
+// error wrapping ErrUndecodable; its raw message is still returned so it can
+// be committed past.
+func (c *TypedConsumer[T]) Fetch(ctx context.Context) (Delivery[T], error) {
+	msg, err := c.consumer.Fetch(ctx)
+	if err != nil {
+		return Delivery[T]{}, err
+	}
+	return decode[T](msg)
+}
+
+// Commit acknowledges deliveries.
+func (c *TypedConsumer[T]) Commit(ctx context.Context, ds ...Delivery[T]) error {
+	msgs := make([]Message, len(ds))
+	for i, d := range ds {
+		msgs[i] = d.Raw
+	}
+	return c.consumer.Commit(ctx, msgs...)
+}
+
+// Consume passes each decoded message to handler and commits it once the
+// handler returns nil, as the package-level Consume does. Undecodable
+// messages are logged and committed without reaching handler.
+func (c *TypedConsumer[T]) Consume(ctx context.Context, handler func(context.Context, Delivery[T]) error) error {
+	return Consume(ctx, c.consumer, func(ctx context.Context, msg Message) error {
+		d, err := decode[T](msg)
+		if err != nil {
+			c.logger.ErrorContext(ctx, "skip undecodable message", slog.String("topic", msg.Topic), slog.Int("partition", msg.Partition), slog.Int64("offset", msg.Offset), observability.Err(err))
+			return err
+		}
+		return handler(ctx, d)
+	})
+}
+
+// CheckMembership checks the group membership of the underlying consumer.
+func (c *TypedConsumer[T]) CheckMembership(ctx context.Context) error {
+	return c.consumer.CheckMembership(ctx)
+}
+
+// Close closes the underlying consumer.
+func (c *TypedConsumer[T]) Close() error {
+	return c.consumer.Close()
+}
+
+func decode[T proto.Message](msg Message) (Delivery[T], error) {
+	var zero T
+	m := zero.ProtoReflect().New().Interface().(T)
+	d := Delivery[T]{Message: m, Headers: DecodeHeaders(msg.Headers), Raw: msg}
+	if err := proto.Unmarshal(msg.Value, m); err != nil {
+		schema, _ := SchemaOf(m)
+		return d, fmt.Errorf("%w: unmarshal %s: %w", ErrUndecodable, schema, err)
+	}
+	return d, nil
+}
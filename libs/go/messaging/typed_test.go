@@ -0,0 +1,56 @@
+package messaging_test
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/membus"
+)
+
+// An undecodable payload is committed past instead of stopping Consume.
+func TestTypedConsumerSkipsUndecodableMessages(t *testing.T) {
+	bus := membus.New(1)
+	pub := messaging.NewTypedPublisher(bus.Publisher("signals"), messaging.Producer{Service: "test"}, (*busv1.Signal).GetInfluencerId)
+	if err := pub.Publish(context.Background(), &busv1.Signal{SignalId: "sig-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publisher("signals").Publish(context.Background(), messaging.Message{Value: []byte("\xff\xff\xff not a signal")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := pub.Publish(context.Background(), &busv1.Signal{SignalId: "sig-2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := messaging.NewTypedConsumer[*busv1.Signal](bus.Consumer("signals", "group"), slog.New(slog.DiscardHandler))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var handled []string
+	err := c.Consume(ctx, func(_ context.Context, d messaging.Delivery[*busv1.Signal]) error {
+		handled = append(handled, d.Message.GetSignalId())
+		if len(handled) == 2 {
+			cancel()
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if !slices.Equal(handled, []string{"sig-1", "sig-2"}) {
+		t.Fatalf("handled %v, want both signals around the bad message", handled)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// sig-2 was handled as ctx ended, so only it is redelivered.
+	again := bus.Consumer("signals", "group")
+	defer again.Close()
+	if msg, err := again.Fetch(context.Background()); err != nil || msg.Offset != 2 {
+		t.Fatalf("redelivered offset %d (%v), want only sig-2 at offset 2", msg.Offset, err)
+	}
+}
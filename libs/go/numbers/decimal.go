@@ -0,0 +1,339 @@
+package numbers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ErrDivisionByZero is returned by Decimal.Quo for a zero divisor.
+var ErrDivisionByZero = errors.New("numbers: division by zero")
+
+// Decimal is an immutable arbitrary-precision decimal number equal to
+// coef × 10^-scale. The zero value is 0. Use it instead of float64 for prices
+// and sizes so exchange strings round-trip exactly and lot/tick rounding is
+// not affected by binary floating point error.
+type Decimal struct {
+	// coef is nil for zero and never mutated once the Decimal is built.
+	coef  *big.Int
+	scale int32
+}
+
+// MaxExponent bounds the exponent accepted by ParseDecimal, so a short input
+// such as "1e999999999" cannot make it allocate a huge coefficient.
+const MaxExponent = 64
+
+var bigTen = big.NewInt(10)
+
+func pow10(n int64) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(n), nil)
+}
+
+// NewDecimal returns coef × 10^-scale.
+func NewDecimal(coef int64, scale int32) Decimal {
+	return newDecimal(big.NewInt(coef), int64(scale))
+}
+
+// newDecimal takes ownership of c and normalizes a negative scale to 0.
+func newDecimal(c *big.Int, scale int64) Decimal {
+	if scale < 0 {
+		c.Mul(c, pow10(-scale))
+		scale = 0
+	}
+	if c.Sign() == 0 {
+		return Decimal{}
+	}
+	return Decimal{coef: c, scale: int32(scale)}
+}
+
+// ParseDecimal parses a decimal string such as "2012.5", "-0.001" or "1e-3",
+// the formats Hyperliquid uses for prices and sizes. NaN, infinities and
+// exponents beyond ±MaxExponent are rejected.
+func ParseDecimal(s string) (Decimal, error) {
+	str := strings.TrimSpace(s)
+	var exp int64
+	if i := strings.IndexAny(str, "eE"); i >= 0 {
+		e, err := strconv.ParseInt(str[i+1:], 10, 32)
+		if err != nil {
+			return Decimal{}, fmt.Errorf("numbers: invalid decimal %q", s)
+		}
+		if e > MaxExponent || e < -MaxExponent {
+			return Decimal{}, fmt.Errorf("numbers: decimal %q out of range", s)
+		}
+		exp, str = e, str[:i]
+	}
+	neg := false
+	if str != "" && (str[0] == '+' || str[0] == '-') {
+		neg, str = str[0] == '-', str[1:]
+	}
+	intPart, frac, _ := strings.Cut(str, ".")
+	digits := intPart + frac
+	if digits == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return Decimal{}, fmt.Errorf("numbers: invalid decimal %q", s)
+	}
+	c, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		return Decimal{}, fmt.Errorf("numbers: invalid decimal %q", s)
+	}
+	scale := int64(len(frac)) - exp
+	if scale > math.MaxInt32 {
+		return Decimal{}, fmt.Errorf("numbers: decimal %q out of range", s)
+	}
+	if neg {
+		c.Neg(c)
+	}
+	return newDecimal(c, scale), nil
+}
+
+// MustParseDecimal is like ParseDecimal but panics on error. Intended for
+// constants.
+func MustParseDecimal(s string) Decimal {
+	d, err := ParseDecimal(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+// DecimalFromFloat converts f using its shortest decimal representation, so
+// 0.1 becomes exactly 0.1. Magnitudes whose exponent exceeds ±MaxExponent are
+// rejected like in ParseDecimal.
+func DecimalFromFloat(f float64) (Decimal, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return Decimal{}, fmt.Errorf("numbers: non-finite value %v", f)
+	}
+	return ParseDecimal(strconv.FormatFloat(f, 'g', -1, 64))
+}
+
+// ExtractDecimal converts the same scalar types as ExtractFloat into a Decimal.
+func ExtractDecimal(val any) (Decimal, error) {
+	switch v := val.(type) {
+	case string:
+		if v == "" {
+			return Decimal{}, fmt.Errorf("empty string")
+		}
+		return ParseDecimal(v)
+	case json.Number:
+		return ParseDecimal(string(v))
+	case Decimal:
+		return v, nil
+	case int:
+		return NewDecimal(int64(v), 0), nil
+	case int64:
+		return NewDecimal(v, 0), nil
+	case uint64:
+		return newDecimal(new(big.Int).SetUint64(v), 0), nil
+	case float64:
+		return DecimalFromFloat(v)
+	case float32:
+		return DecimalFromFloat(float64(v))
+	default:
+		return Decimal{}, fmt.Errorf("unsupported decimal type %T", val)
+	}
+}
+
+func (d Decimal) bigCoef() *big.Int {
+	if d.coef == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(d.coef)
+}
+
+// rescaled returns the coefficient of d at scale s >= d.scale.
+func (d Decimal) rescaled(s int32) *big.Int {
+	c := d.bigCoef()
+	if s > d.scale {
+		c.Mul(c, pow10(int64(s-d.scale)))
+	}
+	return c
+}
+
+// Sign returns -1, 0 or +1.
+func (d Decimal) Sign() int {
+	if d.coef == nil {
+		return 0
+	}
+	return d.coef.Sign()
+}
+
+// IsZero reports whether d == 0.
+func (d Decimal) IsZero() bool { return d.Sign() == 0 }
+
+// Cmp returns -1, 0 or +1 as d is less than, equal to or greater than o.
+func (d Decimal) Cmp(o Decimal) int {
+	s := max(d.scale, o.scale)
+	return d.rescaled(s).Cmp(o.rescaled(s))
+}
+
+// Equal reports whether d and o are numerically equal ("1.50" equals "1.5").
+func (d Decimal) Equal(o Decimal) bool { return d.Cmp(o) == 0 }
+
+// Add returns d + o.
+func (d Decimal) Add(o Decimal) Decimal {
+	s := max(d.scale, o.scale)
+	c := d.rescaled(s)
+	return newDecimal(c.Add(c, o.rescaled(s)), int64(s))
+}
+
+// Sub returns d - o.
+func (d Decimal) Sub(o Decimal) Decimal {
+	return d.Add(o.Neg())
+}
+
+// Mul returns d × o.
+func (d Decimal) Mul(o Decimal) Decimal {
+	c := d.bigCoef()
+	return newDecimal(c.Mul(c, o.bigCoef()), int64(d.scale)+int64(o.scale))
+}
+
+// Quo returns d / o rounded half away from zero to places fractional digits.
+func (d Decimal) Quo(o Decimal, places int32) (Decimal, error) {
+	if o.IsZero() {
+		return Decimal{}, ErrDivisionByZero
+	}
+	// d/o at scale p has coefficient d.coef × 10^(p - d.scale + o.scale) / o.coef.
+	num, den := d.bigCoef(), o.bigCoef()
+	if e := int64(places) - int64(d.scale) + int64(o.scale); e >= 0 {
+		num.Mul(num, pow10(e))
+	} else {
+		den.Mul(den, pow10(-e))
+	}
+	return newDecimal(quoRound(num, den, true), int64(places)), nil
+}
+
+// Neg returns -d.
+func (d Decimal) Neg() Decimal {
+	c := d.bigCoef()
+	return newDecimal(c.Neg(c), int64(d.scale))
+}
+
+// Abs returns |d|.
+func (d Decimal) Abs() Decimal {
+	if d.Sign() < 0 {
+		return d.Neg()
+	}
+	return d
+}
+
+// Round rounds d half away from zero to places fractional digits.
+func (d Decimal) Round(places int32) Decimal { return d.roundTo(places, true) }
+
+// Truncate rounds d toward zero to places fractional digits, e.g. to the lot
+// size of a market with Truncate(szDecimals).
+func (d Decimal) Truncate(places int32) Decimal { return d.roundTo(places, false) }
+
+func (d Decimal) roundTo(places int32, halfUp bool) Decimal {
+	if d.scale <= places {
+		return d
+	}
+	q := quoRound(d.bigCoef(), pow10(int64(d.scale-places)), halfUp)
+	return newDecimal(q, int64(places))
+}
+
+// RoundToStep rounds d half away from zero to a multiple of step (e.g. a tick
+// size). A zero step returns d unchanged.
+func (d Decimal) RoundToStep(step Decimal) Decimal { return d.toStep(step, true) }
+
+// TruncateToStep rounds d toward zero to a multiple of step. A zero step
+// returns d unchanged.
+func (d Decimal) TruncateToStep(step Decimal) Decimal { return d.toStep(step, false) }
+
+func (d Decimal) toStep(step Decimal, halfUp bool) Decimal {
+	if step.IsZero() {
+		return d
+	}
+	s := max(d.scale, step.scale)
+	n := quoRound(d.rescaled(s), step.rescaled(s), halfUp)
+	return step.Mul(newDecimal(n, 0))
+}
+
+// RoundSignificant rounds d half away from zero to figs significant digits,
+// never removing integer digits (Hyperliquid's price rule uses figs = 5 and
+// always accepts integer prices).
+func (d Decimal) RoundSignificant(figs int) Decimal {
+	if d.IsZero() || figs <= 0 {
+		return d
+	}
+	digits := len(new(big.Int).Abs(d.coef).String())
+	places := int64(d.scale) - int64(digits-figs)
+	if places >= int64(d.scale) {
+		return d
+	}
+	return d.Round(int32(max(places, 0)))
+}
+
+// quoRound divides num by den, rounding half away from zero when halfUp and
+// toward zero otherwise.
+func quoRound(num, den *big.Int, halfUp bool) *big.Int {
+	q, r := new(big.Int).QuoRem(num, den, new(big.Int))
+	if halfUp && r.Sign() != 0 {
+		r.Abs(r).Lsh(r, 1)
+		if r.Cmp(new(big.Int).Abs(den)) >= 0 {
+			if num.Sign()*den.Sign() < 0 {
+				q.Sub(q, big.NewInt(1))
+			} else {
+				q.Add(q, big.NewInt(1))
+			}
+		}
+	}
+	return q
+}
+
+// Float64 returns the nearest float64 to d.
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(d.String(), 64)
+	return f
+}
+
+// String formats d in plain notation without trailing fractional zeros, e.g.
+// "2012.5" or "-0.001". It is the encoding used for decimal proto fields.
+func (d Decimal) String() string {
+	if d.IsZero() {
+		return "0"
+	}
+	abs := new(big.Int).Abs(d.coef).String()
+	sign := ""
+	if d.coef.Sign() < 0 {
+		sign = "-"
+	}
+	if d.scale == 0 {
+		return sign + abs
+	}
+	if pad := int(d.scale) - len(abs) + 1; pad > 0 {
+		abs = strings.Repeat("0", pad) + abs
+	}
+	split := len(abs) - int(d.scale)
+	frac := strings.TrimRight(abs[split:], "0")
+	if frac == "" {
+		return sign + abs[:split]
+	}
+	return sign + abs[:split] + "." + frac
+}
+
+// MarshalText encodes d as String, so JSON holds decimals as strings.
+func (d Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText parses text with ParseDecimal.
+func (d *Decimal) UnmarshalText(text []byte) error {
+	v, err := ParseDecimal(string(text))
+	if err != nil {
+		return err
+	}
+	*d = v
+	return nil
+}
+
+// DecimalOr parses the decimal proto field s, falling back to the deprecated
+// float64 field when s is unset (messages from producers that predate it).
+func DecimalOr(s string, fallback float64) (Decimal, error) {
+	if s != "" {
+		return ParseDecimal(s)
+	}
+	return DecimalFromFloat(fallback)
+}
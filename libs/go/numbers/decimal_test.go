@@ -0,0 +1,187 @@
+package numbers
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseDecimal(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "2012.5", want: "2012.5"},
+		{in: "-0.001", want: "-0.001"},
+		{in: "+3", want: "3"},
+		{in: " 42 ", want: "42"},
+		{in: "0.000", want: "0"},
+		{in: "-0", want: "0"},
+		{in: ".5", want: "0.5"},
+		{in: "5.", want: "5"},
+		{in: "1e-3", want: "0.001"},
+		{in: "1.25E2", want: "125"},
+		{in: "1e64", want: "1" + zeros(64)},
+		{in: "1e-64", want: "0." + zeros(63) + "1"},
+		{in: "123456789012345678901234567890.123456789", want: "123456789012345678901234567890.123456789"},
+		{in: "1e65", wantErr: true},
+		{in: "1e-65", wantErr: true},
+		{in: "1e999999999", wantErr: true},
+		{in: "1e99999999999", wantErr: true},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "1e", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "0x10", wantErr: true},
+		{in: "NaN", wantErr: true},
+		{in: "Inf", wantErr: true},
+		{in: "-Infinity", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseDecimal(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseDecimal(%q) = %s, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDecimal(%q): %v", tt.in, err)
+			continue
+		}
+		if got.String() != tt.want {
+			t.Errorf("ParseDecimal(%q) = %s, want %s", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDecimalFromFloat(t *testing.T) {
+	tests := []struct {
+		in      float64
+		want    string
+		wantErr bool
+	}{
+		{in: 0.1, want: "0.1"},
+		{in: -2012.5, want: "-2012.5"},
+		{in: 1e21, want: "1" + zeros(21)},
+		{in: 1e-7, want: "0.0000001"},
+		{in: 1e100, wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := DecimalFromFloat(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("DecimalFromFloat(%v) = %s, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("DecimalFromFloat(%v) = %s, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalArithmetic(t *testing.T) {
+	d := MustParseDecimal
+	tests := []struct {
+		name string
+		got  Decimal
+		want string
+	}{
+		{name: "add aligns scales", got: d("0.1").Add(d("0.2")), want: "0.3"},
+		{name: "sub to zero", got: d("1.50").Sub(d("1.5")), want: "0"},
+		{name: "sub negative", got: d("1").Sub(d("2.25")), want: "-1.25"},
+		{name: "mul", got: d("1.5").Mul(d("-0.2")), want: "-0.3"},
+		{name: "neg", got: d("3.1").Neg(), want: "-3.1"},
+		{name: "abs", got: d("-3.1").Abs(), want: "3.1"},
+		{name: "round half up", got: d("2.345").Round(2), want: "2.35"},
+		{name: "round half away from zero", got: d("-2.345").Round(2), want: "-2.35"},
+		{name: "round below half", got: d("2.344").Round(2), want: "2.34"},
+		{name: "round to integer", got: d("2.5").Round(0), want: "3"},
+		{name: "round keeps shorter values", got: d("2.3").Round(4), want: "2.3"},
+		{name: "truncate", got: d("0.99999").Truncate(4), want: "0.9999"},
+		{name: "truncate negative toward zero", got: d("-0.99999").Truncate(4), want: "-0.9999"},
+		{name: "truncate to zero", got: d("0.00001").Truncate(4), want: "0"},
+		{name: "round to step", got: d("2012.37").RoundToStep(d("0.5")), want: "2012.5"},
+		{name: "truncate to step", got: d("2012.37").TruncateToStep(d("0.25")), want: "2012.25"},
+		{name: "zero step", got: d("2012.37").TruncateToStep(Decimal{}), want: "2012.37"},
+		{name: "five significant figures", got: d("2012.345").RoundSignificant(5), want: "2012.3"},
+		{name: "significant figures keep integer digits", got: d("123456.7").RoundSignificant(5), want: "123457"},
+		{name: "significant figures of small values", got: d("0.000123456").RoundSignificant(5), want: "0.00012346"},
+	}
+	for _, tt := range tests {
+		if tt.got.String() != tt.want {
+			t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestDecimalQuo(t *testing.T) {
+	d := MustParseDecimal
+	tests := []struct {
+		a, b    string
+		places  int32
+		want    string
+		wantErr error
+	}{
+		{a: "100", b: "3000", places: 8, want: "0.03333333"},
+		{a: "2", b: "3", places: 2, want: "0.67"},
+		{a: "-2", b: "3", places: 2, want: "-0.67"},
+		{a: "1", b: "0.125", places: 0, want: "8"},
+		{a: "1", b: "0", places: 2, wantErr: ErrDivisionByZero},
+	}
+	for _, tt := range tests {
+		got, err := d(tt.a).Quo(d(tt.b), tt.places)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("%s / %s = %s, %v, want %v", tt.a, tt.b, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got.String() != tt.want {
+			t.Errorf("%s / %s = %s, %v, want %s", tt.a, tt.b, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecimalCompareAndText(t *testing.T) {
+	d := MustParseDecimal
+	if !d("1.10").Equal(d("1.1")) || d("1.1").Cmp(d("1.09")) != 1 || d("-1").Cmp(Decimal{}) != -1 {
+		t.Fatal("comparison disregards scale or sign")
+	}
+	if !(Decimal{}).IsZero() || d("0.00").Sign() != 0 {
+		t.Fatal("zero values are not zero")
+	}
+	if got := d("0.1").Float64(); got != 0.1 {
+		t.Fatalf("Float64 = %v, want 0.1", got)
+	}
+
+	text, err := d("-0.0500").MarshalText()
+	if err != nil || string(text) != "-0.05" {
+		t.Fatalf("MarshalText = %q, %v", text, err)
+	}
+	var back Decimal
+	if err := back.UnmarshalText(text); err != nil || !back.Equal(d("-0.05")) {
+		t.Fatalf("UnmarshalText = %s, %v", back, err)
+	}
+	if err := back.UnmarshalText([]byte("1e100")); err == nil {
+		t.Fatal("UnmarshalText accepted an out-of-range exponent")
+	}
+
+	if got, err := DecimalOr("", 1.5); err != nil || got.String() != "1.5" {
+		t.Fatalf("DecimalOr fallback = %s, %v", got, err)
+	}
+	if got, err := DecimalOr("2.25", 1.5); err != nil || got.String() != "2.25" {
+		t.Fatalf("DecimalOr = %s, %v", got, err)
+	}
+}
+
+func zeros(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = '0'
+	}
+	return string(b)
+}
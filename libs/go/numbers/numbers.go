@@ -3,6 +3,7 @@ package numbers
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 )
 
@@ -31,6 +32,19 @@ func ExtractFloat(val any) (float64, error) {
 	}
 }
 
+// ExtractFiniteFloat is like ExtractFloat but also rejects NaN and ±Inf,
+// which strconv accepts as "NaN" and "Inf".
+func ExtractFiniteFloat(val any) (float64, error) {
+	f, err := ExtractFloat(val)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, fmt.Errorf("non-finite value %v", f)
+	}
+	return f, nil
+}
+
 // ExtractInt converts common scalar types into int64.
 func ExtractInt(val any) (int64, error) {
 	switch v := val.(type) {
@@ -0,0 +1,113 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log attribute keys shared by every service, so logs can be queried across
+// ingestion, the matcher and downstream consumers.
+const (
+	LogKeyService        = "service"
+	LogKeyInstance       = "instance"
+	LogKeyInfluencerID   = "influencer_id"
+	LogKeySignalID       = "signal_id"
+	LogKeySubscriptionID = "subscription_id"
+	LogKeyTraceID        = "trace_id"
+	LogKeySpanID         = "span_id"
+	LogKeyError          = "error"
+)
+
+// LoggingConfig configures NewLogger.
+type LoggingConfig struct {
+	ServiceName     string
+	ServiceInstance string
+	// Level is the minimum level logged: debug, info (default), warn or error.
+	Level string
+	// LevelVar, when set, is initialized to Level and used by the logger so
+	// the level can be changed while running.
+	LevelVar *slog.LevelVar
+}
+
+// ParseLevel parses a LOG_LEVEL value. An empty string is info.
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	if s == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(strings.ToUpper(s))); err != nil {
+		return 0, fmt.Errorf("invalid log level %q: want debug, info, warn or error", s)
+	}
+	return level, nil
+}
+
+// NewLogger returns a JSON logger writing to w. Every record carries the
+// service and instance, the attributes added to its context with
+// WithLogAttrs, and the trace and span IDs of the span in its context; use
+// the Context variants (InfoContext, ErrorContext, ...) to pass one.
+func NewLogger(w io.Writer, cfg LoggingConfig) (*slog.Logger, error) {
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+	var leveler slog.Leveler = level
+	if cfg.LevelVar != nil {
+		cfg.LevelVar.Set(level)
+		leveler = cfg.LevelVar
+	}
+	handler := slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: true, Level: leveler})
+	logger := slog.New(contextHandler{handler}).With(slog.String(LogKeyService, cfg.ServiceName))
+	if cfg.ServiceInstance != "" {
+		logger = logger.With(slog.String(LogKeyInstance, cfg.ServiceInstance))
+	}
+	return logger, nil
+}
+
+// Err is the attribute for an error.
+func Err(err error) slog.Attr {
+	return slog.Any(LogKeyError, err)
+}
+
+type logAttrsKey struct{}
+
+// WithLogAttrs returns a copy of ctx whose log records carry attrs in addition
+// to the ones already in ctx.
+func WithLogAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	if len(attrs) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, logAttrsKey{}, append(slices.Clip(LogAttrs(ctx)), attrs...))
+}
+
+// LogAttrs returns the attributes added to ctx with WithLogAttrs.
+func LogAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(logAttrsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler adds the context's log attributes and span to each record.
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	r.AddAttrs(LogAttrs(ctx)...)
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(slog.String(LogKeyTraceID, sc.TraceID().String()), slog.String(LogKeySpanID, sc.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,39 @@
+package observability
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// LatencyBuckets are histogram buckets in seconds from 5ms to ~41s, sized for
+// end-to-end latencies measured from exchange time.
+var LatencyBuckets = prometheus.ExponentialBuckets(0.005, 2, 14)
+
+// DurationBuckets are histogram buckets in seconds from 1ms to ~16s, sized
+// for single calls such as a Kafka write.
+var DurationBuckets = prometheus.ExponentialBuckets(0.001, 2, 15)
+
+// MetricsHandler serves the default Prometheus registry, which includes the
+// Go runtime and process collectors.
+func MetricsHandler() http.Handler {
+	return promhttp.Handler()
+}
+
+// ObserveSinceMillis records the seconds elapsed between the Unix millisecond
+// timestamp ms and now. Unset timestamps are skipped.
+func ObserveSinceMillis(o prometheus.Observer, ms int64, now time.Time) {
+	if ms <= 0 {
+		return
+	}
+	o.Observe(now.Sub(time.UnixMilli(ms)).Seconds())
+}
+
+// EnumLabel turns a protobuf enum name such as "SIGNAL_ACTION_OPEN" into a
+// label value ("open") by dropping prefix.
+func EnumLabel(name, prefix string) string {
+	return strings.ToLower(strings.TrimPrefix(name, prefix))
+}
@@ -0,0 +1,101 @@
+// Package observability sets up tracing for the Go services.
+package observability
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Trace exporters.
+const (
+	// ExporterNone generates trace IDs for propagation but records no spans.
+	ExporterNone = "none"
+	// ExporterStdout writes spans as JSON to stdout.
+	ExporterStdout = "stdout"
+	// ExporterOTLP sends spans over OTLP/HTTP. The endpoint and headers come
+	// from the standard OTEL_EXPORTER_OTLP_* environment variables.
+	ExporterOTLP = "otlp"
+)
+
+// TracingConfig selects how a service exports spans.
+type TracingConfig struct {
+	ServiceName     string
+	ServiceInstance string
+	// Exporter is ExporterNone, ExporterStdout or ExporterOTLP.
+	Exporter string
+	// SampleRatio is the fraction of new traces that are sampled; traces
+	// continued from an upstream message follow the upstream decision.
+	SampleRatio float64
+}
+
+// ShutdownFunc flushes and stops the tracer provider.
+type ShutdownFunc func(context.Context) error
+
+// SetupTracing installs the global tracer provider and the W3C trace context
+// propagator. Call the returned function on exit to flush pending spans.
+func SetupTracing(ctx context.Context, cfg TracingConfig) (ShutdownFunc, error) {
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.instance.id", cfg.ServiceInstance),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("build trace resource: %w", err)
+	}
+
+	opts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	switch cfg.Exporter {
+	case ExporterNone, "":
+		opts = append(opts, sdktrace.WithSampler(sdktrace.NeverSample()))
+	case ExporterStdout:
+		exp, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("create stdout trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp), sdktrace.WithSampler(sampler(cfg.SampleRatio)))
+	case ExporterOTLP:
+		exp, err := otlptracehttp.New(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("create OTLP trace exporter: %w", err)
+		}
+		opts = append(opts, sdktrace.WithBatcher(exp), sdktrace.WithSampler(sampler(cfg.SampleRatio)))
+	default:
+		return nil, fmt.Errorf("unknown trace exporter %q", cfg.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(opts...)
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return tp.Shutdown, nil
+}
+
+func sampler(ratio float64) sdktrace.Sampler {
+	return sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))
+}
+
+// Tracer returns a tracer from the global provider for the named
+// instrumentation scope.
+func Tracer(name string) trace.Tracer {
+	return otel.Tracer(name)
+}
+
+// EndSpan records err (if any) on span and ends it. Context cancellation is
+// not treated as a failure.
+func EndSpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, context.Canceled) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
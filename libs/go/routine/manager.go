@@ -58,6 +58,17 @@ func (m *Manager) TaskCount() int {
 	return count
 }
 
+// TaskIDs returns the ids of the currently running routines in no particular order.
+func (m *Manager) TaskIDs() []string {
+	m.mu.RLock()
+	ids := make([]string, 0, len(m.tasks))
+	for id := range m.tasks {
+		ids = append(ids, id)
+	}
+	m.mu.RUnlock()
+	return ids
+}
+
 // Run starts a task with the bare id/handler pair.
 // Prefer RunTask when lifecycle hooks are needed.
 func (m *Manager) Run(id string, handler Handler) error {
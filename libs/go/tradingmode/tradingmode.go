@@ -0,0 +1,228 @@
+// Package tradingmode implements the system-wide trading kill switch. A mode
+// is set globally or for one influencer, market or subscriber in a Redis hash
+// shared by every service; each service keeps a cached copy that follows
+// changes and resolves the mode that applies to a signal or request.
+package tradingmode
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	redis "github.com/redis/go-redis/v9"
+)
+
+// Mode is a trading mode, from least to most restrictive.
+type Mode string
+
+const (
+	// Normal trades as usual.
+	Normal Mode = "normal"
+	// RecordOnly keeps signals flowing but turns execution requests into
+	// rejected ones with reason RejectionReason.
+	RecordOnly Mode = "record-only"
+	// Halted stops signals and execution requests altogether.
+	Halted Mode = "halted"
+)
+
+// RejectionReason marks execution requests emitted in RecordOnly mode.
+const RejectionReason = "TRADING_HALTED"
+
+// ParseMode validates a mode name, case-insensitively.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case Normal, RecordOnly, Halted:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown trading mode %q (want normal, record-only or halted)", s)
+}
+
+func (m Mode) rank() int {
+	switch m {
+	case RecordOnly:
+		return 1
+	case Halted:
+		return 2
+	}
+	return 0
+}
+
+// Scope is what a mode applies to.
+type Scope string
+
+const (
+	ScopeGlobal     Scope = "global"
+	ScopeInfluencer Scope = "influencer"
+	ScopeMarket     Scope = "market"
+	ScopeSubscriber Scope = "subscriber"
+)
+
+// ParseScope validates a scope name.
+func ParseScope(s string) (Scope, error) {
+	switch sc := Scope(strings.ToLower(s)); sc {
+	case ScopeGlobal, ScopeInfluencer, ScopeMarket, ScopeSubscriber:
+		return sc, nil
+	}
+	return "", fmt.Errorf("unknown trading mode scope %q", s)
+}
+
+// field is the hash field of a scope: "global" or "<scope>:<id>". Influencer
+// addresses are lower-cased and markets upper-cased so lookups match however
+// they were entered.
+func field(scope Scope, id string) string {
+	switch scope {
+	case ScopeGlobal:
+		return string(ScopeGlobal)
+	case ScopeInfluencer:
+		id = strings.ToLower(id)
+	case ScopeMarket:
+		id = strings.ToUpper(id)
+	}
+	return string(scope) + ":" + id
+}
+
+// Target identifies what a mode is resolved for. Empty fields are not
+// consulted.
+type Target struct {
+	Influencer string
+	Market     string
+	Subscriber string
+}
+
+// Switch caches the modes stored in a Redis hash. Writers publish on a
+// channel named after the hash so every Switch reloads immediately; polling
+// covers missed notifications. Until the first successful Reload every target
+// resolves to Halted, so an instance that cannot read the modes trades
+// nothing rather than ignoring a halt.
+type Switch struct {
+	source   *libconfig.RedisSource
+	interval time.Duration
+	logger   *slog.Logger
+
+	mu     sync.RWMutex
+	modes  map[string]Mode
+	loaded bool
+}
+
+// NewSwitch returns a Switch backed by the hash at key, polled every
+// interval.
+func NewSwitch(client redis.UniversalClient, key string, interval time.Duration, logger *slog.Logger) *Switch {
+	return &Switch{
+		source:   libconfig.NewRedisSource(client, key),
+		interval: interval,
+		logger:   logger,
+		modes:    map[string]Mode{},
+	}
+}
+
+// Resolve returns the most restrictive mode set globally or for any of
+// target's influencer, market and subscriber, so a scoped mode can tighten
+// but never relax the global one. It returns Halted until the modes have been
+// loaded.
+func (s *Switch) Resolve(target Target) Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if !s.loaded {
+		return Halted
+	}
+	mode := s.modes[field(ScopeGlobal, "")]
+	for scope, id := range map[Scope]string{
+		ScopeInfluencer: target.Influencer,
+		ScopeMarket:     target.Market,
+		ScopeSubscriber: target.Subscriber,
+	} {
+		if id == "" {
+			continue
+		}
+		if m := s.modes[field(scope, id)]; m.rank() > mode.rank() {
+			mode = m
+		}
+	}
+	if mode == "" {
+		return Normal
+	}
+	return mode
+}
+
+// Modes returns the modes in effect keyed by hash field ("global",
+// "influencer:0xabc", "market:ETH", "subscriber:sub-1"). Scopes without a
+// mode are omitted; a missing "global" means Normal.
+func (s *Switch) Modes() map[string]Mode {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.modes)
+}
+
+// Set stores mode for scope and id (ignored for ScopeGlobal) and notifies
+// every Switch. An empty mode clears the scope.
+func (s *Switch) Set(ctx context.Context, scope Scope, id string, mode Mode) error {
+	if scope != ScopeGlobal && strings.TrimSpace(id) == "" {
+		return fmt.Errorf("trading mode scope %s needs an id", scope)
+	}
+	if err := s.source.Set(ctx, map[string]string{field(scope, id): string(mode)}); err != nil {
+		return err
+	}
+	return s.Reload(ctx)
+}
+
+// Reload reads the hash and applies it, logging every changed mode. Invalid
+// entries are logged and skipped.
+func (s *Switch) Reload(ctx context.Context) error {
+	values, err := s.source.Load(ctx)
+	if err != nil {
+		return fmt.Errorf("load trading modes: %w", err)
+	}
+	next := make(map[string]Mode, len(values))
+	for key, raw := range values {
+		mode, err := ParseMode(raw)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "invalid trading mode", slog.String("key", key), observability.Err(err))
+			continue
+		}
+		next[key] = mode
+	}
+
+	s.mu.Lock()
+	old := s.modes
+	s.modes = next
+	s.loaded = true
+	s.mu.Unlock()
+
+	for key, mode := range next {
+		if old[key] != mode {
+			s.logger.WarnContext(ctx, "trading mode changed", slog.String("key", key), slog.String("old", string(old[key])), slog.String("new", string(mode)))
+		}
+	}
+	for key, mode := range old {
+		if _, ok := next[key]; !ok {
+			s.logger.WarnContext(ctx, "trading mode changed", slog.String("key", key), slog.String("old", string(mode)), slog.String("new", ""))
+		}
+	}
+	return nil
+}
+
+// Run reloads on every poll interval and change notification until ctx is
+// done. Reload errors are logged and the last known modes stay in effect
+// (Halted for every target if none were ever loaded).
+func (s *Switch) Run(ctx context.Context) error {
+	notify := s.source.Notify(ctx)
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-notify:
+		}
+		if err := s.Reload(ctx); err != nil && ctx.Err() == nil {
+			s.logger.ErrorContext(ctx, "reload trading modes", observability.Err(err))
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package tradingmode
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+)
+
+const testKey = "trading:modes"
+
+func newTestSwitch(t *testing.T) (*miniredis.Miniredis, *Switch) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	return mr, NewSwitch(client, testKey, time.Minute, slog.New(slog.DiscardHandler))
+}
+
+func TestSwitchFailsClosedUntilLoaded(t *testing.T) {
+	mr, s := newTestSwitch(t)
+	target := Target{Influencer: "0xabc", Market: "ETH"}
+	if got := s.Resolve(target); got != Halted {
+		t.Fatalf("before any reload: %s, want halted", got)
+	}
+
+	mr.SetError("connection refused")
+	if err := s.Reload(context.Background()); err == nil {
+		t.Fatal("Reload succeeded against a failing Redis")
+	}
+	if got := s.Resolve(target); got != Halted {
+		t.Fatalf("after a failed reload: %s, want halted", got)
+	}
+
+	mr.SetError("")
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got := s.Resolve(target); got != Normal {
+		t.Fatalf("after loading an empty hash: %s, want normal", got)
+	}
+
+	// Once loaded, a failing Redis keeps the last known modes.
+	mr.SetError("connection refused")
+	_ = s.Reload(context.Background())
+	if got := s.Resolve(target); got != Normal {
+		t.Fatalf("after a later failed reload: %s, want the last known normal", got)
+	}
+}
+
+func TestSwitchResolvesMostRestrictiveMode(t *testing.T) {
+	mr, s := newTestSwitch(t)
+	mr.HSet(testKey, "global", "record-only", "influencer:0xabc", "halted", "market:BTC", "normal", "subscriber:sub-1", "bogus")
+	if err := s.Reload(context.Background()); err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	tests := []struct {
+		target Target
+		want   Mode
+	}{
+		{Target{}, RecordOnly},
+		{Target{Influencer: "0xABC"}, Halted},
+		{Target{Market: "btc"}, RecordOnly},
+		{Target{Subscriber: "sub-1"}, RecordOnly},
+		{Target{Influencer: "0xdef", Market: "ETH"}, RecordOnly},
+	}
+	for _, tt := range tests {
+		if got := s.Resolve(tt.target); got != tt.want {
+			t.Errorf("Resolve(%+v) = %s, want %s", tt.target, got, tt.want)
+		}
+	}
+}
@@ -4,10 +4,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
+	"time"
 
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/health"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/kafkabus"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/kafka"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/rest"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/services"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/store"
 	redis "github.com/redis/go-redis/v9"
@@ -16,25 +24,42 @@ import (
 // App centralizes dependency wiring for the matcher service.
 type App struct {
 	cfg    config.Config
-	logger *log.Logger
+	logger *slog.Logger
 
 	redis         *redis.Client
 	subscriptions *store.SubscriptionStore
 	consumer      *kafka.SignalConsumer
 	publisher     *kafka.ExecutionRequestPublisher
+	settings      *libconfig.Reloader[config.Runtime]
+	modes         *tradingmode.Switch
 }
 
-// NewApp builds an App with all required dependencies.
-func NewApp(cfg config.Config, logger *log.Logger) *App {
+// NewApp builds an App with all required dependencies. level is the logger's
+// level, adjusted when the log_level runtime setting changes.
+func NewApp(cfg config.Config, logger *slog.Logger, level *slog.LevelVar) (*App, error) {
 	redisClient := redis.NewClient(&redis.Options{
 		Addr:     cfg.RedisAddr,
 		Password: cfg.RedisPassword,
 		DB:       cfg.RedisDB,
 	})
+	settings, err := newSettings(cfg, redisClient, logger, level)
+	if err != nil {
+		_ = redisClient.Close()
+		return nil, err
+	}
 
 	subStore := store.NewSubscriptionStore(redisClient, cfg.SubscriptionSetKey)
-	consumer := kafka.NewSignalConsumer(cfg)
-	publisher := kafka.NewExecutionRequestPublisher(cfg)
+	consumer := kafka.NewSignalConsumer(cfg, kafkabus.NewConsumer(kafkabus.ConsumerConfig{
+		Brokers:  cfg.KafkaBrokers,
+		Topic:    cfg.KafkaTopicSignals,
+		GroupID:  cfg.KafkaGroupID,
+		ClientID: cfg.InstanceID,
+	}), logger)
+	publisher := kafka.NewExecutionRequestPublisher(cfg, kafkabus.NewPublisher(kafkabus.PublisherConfig{
+		Brokers:         cfg.KafkaBrokers,
+		Topic:           cfg.KafkaTopicExecRequests,
+		AutoCreateTopic: true,
+	}))
 
 	return &App{
 		cfg:           cfg,
@@ -43,35 +68,152 @@ func NewApp(cfg config.Config, logger *log.Logger) *App {
 		subscriptions: subStore,
 		consumer:      consumer,
 		publisher:     publisher,
+		settings:      settings,
+		modes:         tradingmode.NewSwitch(redisClient, cfg.TradingModeKey, cfg.SettingsPollInterval, logger),
+	}, nil
+}
+
+// newSettings builds the runtime settings reloader, reading SETTINGS_FILE when
+// set and the SETTINGS_KEY Redis hash otherwise.
+func newSettings(cfg config.Config, redisClient *redis.Client, logger *slog.Logger, level *slog.LevelVar) (*libconfig.Reloader[config.Runtime], error) {
+	var source libconfig.Source = libconfig.NewRedisSource(redisClient, cfg.SettingsKey)
+	if cfg.SettingsFile != "" {
+		source = libconfig.FileSource{Path: cfg.SettingsFile}
+	}
+	settings, err := libconfig.NewReloader[config.Runtime](source, cfg.SettingsPollInterval, logger)
+	if err != nil {
+		return nil, err
 	}
+	settings.OnChange(func(_, next config.Runtime) {
+		name := next.LogLevel
+		if name == "" {
+			name = cfg.LogLevel
+		}
+		if l, err := observability.ParseLevel(name); err == nil {
+			level.Set(l)
+		}
+	})
+	return settings, nil
 }
 
 // Run starts the matcher service and blocks until ctx cancellation or fatal error.
 func (a *App) Run(ctx context.Context) error {
+	shutdownTracing, err := observability.SetupTracing(ctx, observability.TracingConfig{
+		ServiceName:     "matcher",
+		ServiceInstance: a.cfg.InstanceID,
+		Exporter:        a.cfg.TracingExporter,
+		SampleRatio:     a.cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		return fmt.Errorf("set up tracing: %w", err)
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			a.logger.Error("flush traces", observability.Err(err))
+		}
+	}()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
 	defer a.cleanup()
 
-	matcher := services.NewMatcherService(a.subscriptions, a.consumer, a.publisher, a.logger)
+	// Start on the stored settings; defaults apply until they can be read.
+	if err := a.settings.Reload(ctx); err != nil {
+		a.logger.ErrorContext(ctx, "load runtime settings", observability.Err(err))
+	}
+	go func() {
+		_ = a.settings.Run(ctx)
+	}()
+	if err := a.modes.Reload(ctx); err != nil {
+		a.logger.ErrorContext(ctx, "load trading modes", observability.Err(err))
+	}
+	go func() {
+		_ = a.modes.Run(ctx)
+	}()
+
+	httpErr := make(chan error, 1)
+	go func() {
+		httpErr <- a.runHTTPServer(ctx)
+		cancel()
+	}()
 
-	if err := matcher.Start(ctx); err != nil && !errors.Is(err, context.Canceled) {
+	matcher := services.NewMatcherService(a.subscriptions, a.consumer, a.publisher, a.cfg.SignalReorderWindow, a.settings, a.modes, a.logger)
+
+	err = matcher.Start(ctx)
+	cancel()
+	if herr := <-httpErr; herr != nil && !errors.Is(herr, context.Canceled) {
+		return fmt.Errorf("http server: %w", herr)
+	}
+	if err != nil && !errors.Is(err, context.Canceled) {
 		return fmt.Errorf("matcher service exited with error: %w", err)
 	}
 	return ctx.Err()
 }
 
+func (a *App) runHTTPServer(ctx context.Context) error {
+	mux, srv, err := rest.NewServer(a.cfg, a.readiness(), a.settings)
+	if err != nil {
+		return err
+	}
+	rest.NewTradingModeController(a.modes).RegisterTradingModeRoutes(mux)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		a.logger.Info("HTTP server started", slog.String("addr", srv.Addr))
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	// App context shutdown:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := srv.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return fmt.Errorf("http server shutdown: %w", err)
+		}
+		err := <-serverErr
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return ctx.Err()
+	// HTTP server error:
+	case err := <-serverErr:
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+// readiness checks the matcher's dependencies and that the consumer is a
+// group member and keeping up.
+func (a *App) readiness() *health.Checker {
+	checker := health.NewChecker(0)
+	checker.Add("redis", func(ctx context.Context) error {
+		return a.redis.Ping(ctx).Err()
+	})
+	checker.Add("kafka", health.CheckKafkaBrokers(a.cfg.KafkaBrokers))
+	checker.Add("consumer_group", a.consumer.CheckGroupMembership)
+	checker.Add("consumer_progress", a.consumer.CheckProgress)
+	return checker
+}
+
 func (a *App) cleanup() {
 	if a.consumer != nil {
 		if err := a.consumer.Close(); err != nil {
-			a.logger.Printf("error closing Kafka consumer: %v", err)
+			a.logger.Error("close Kafka consumer", observability.Err(err))
 		}
 	}
 	if a.publisher != nil {
 		if err := a.publisher.Close(); err != nil {
-			a.logger.Printf("error closing Kafka publisher: %v", err)
+			a.logger.Error("close Kafka publisher", observability.Err(err))
 		}
 	}
 	if a.redis != nil {
 		if err := a.redis.Close(); err != nil {
-			a.logger.Printf("error closing Redis client: %v", err)
+			a.logger.Error("close Redis client", observability.Err(err))
 		}
 	}
 }
@@ -3,73 +3,76 @@ package config
 import (
 	"fmt"
 	"os"
-	"strconv"
-	"strings"
+	"time"
+
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
 )
 
-// Config holds runtime configuration for the matcher service.
+// Config holds runtime configuration for the matcher service. Fields are
+// loaded by libs/go/config from their env tags, a YAML file and flags.
 type Config struct {
-	RedisAddr     string
-	RedisPassword string
-	RedisDB       int
+	RedisAddr     string `env:"REDIS_ADDR" default:"localhost:6379" validate:"required"`
+	RedisPassword string `env:"REDIS_PASSWORD" secret:"true"`
+	RedisDB       int    `env:"REDIS_DB" default:"0" validate:"min=0"`
 
-	KafkaBrokers           []string
-	KafkaGroupID           string
-	KafkaTopicSignals      string
-	KafkaTopicExecRequests string
+	KafkaBrokers           []string `env:"KAFKA_BROKERS" default:"localhost:9092" validate:"required"`
+	KafkaGroupID           string   `env:"KAFKA_GROUP_ID_MATCHER" default:"matcher" validate:"required"`
+	KafkaTopicSignals      string   `env:"KAFKA_TOPIC_INFLUENCER_SIGNALS" default:"influencer_signals" validate:"required"`
+	KafkaTopicExecRequests string   `env:"KAFKA_TOPIC_EXECUTION_REQUESTS" default:"execution_requests" validate:"required"`
 
-	SubscriptionSetKey string
-}
+	SubscriptionSetKey string `env:"SUBSCRIPTION_SET_KEY" default:"matcher:subscriptions:primary" validate:"required"`
 
-// envOrDefault returns the value of an environment variable or a default.
-func envOrDefault(key, def string) string {
-	if v := os.Getenv(key); v != "" {
-		return v
-	}
-	return def
-}
+	// InstanceID identifies this matcher instance in produced message headers.
+	// It defaults to the hostname.
+	InstanceID string `env:"INSTANCE_ID"`
 
-func envIntOrDefault(key string, def int) (int, error) {
-	if raw := os.Getenv(key); raw != "" {
-		val, err := strconv.Atoi(raw)
-		if err != nil {
-			return 0, fmt.Errorf("invalid %s: %w", key, err)
-		}
-		return val, nil
-	}
+	// SignalReorderWindow is how long signals that arrive after a sequence gap
+	// are held waiting for the missing ones. Zero only logs gaps.
+	SignalReorderWindow time.Duration `env:"SIGNAL_REORDER_WINDOW" default:"0s" validate:"min=0s"`
 
-	return def, nil
-}
+	// ConsumerStallTimeout is how long the signal consumer may go without
+	// handling a message while behind before readiness fails. Zero disables
+	// the check.
+	ConsumerStallTimeout time.Duration `env:"CONSUMER_STALL_TIMEOUT" default:"2m" validate:"min=0s"`
 
-func envCSVOrDefault(key, def string) []string {
-	raw := envOrDefault(key, def)
-	parts := strings.Split(raw, ",")
-	for i := range parts {
-		parts[i] = strings.TrimSpace(parts[i])
-	}
-	return parts
+	// TracingExporter selects the span exporter (none, stdout or otlp);
+	// TracingSampleRatio is the fraction of new traces sampled.
+	TracingExporter    string  `env:"TRACING_EXPORTER" default:"none" validate:"oneof=none stdout otlp"`
+	TracingSampleRatio float64 `env:"TRACING_SAMPLE_RATIO" default:"1" validate:"min=0,max=1"`
+
+	// LogLevel is the minimum level logged: debug, info, warn or error.
+	LogLevel string `env:"LOG_LEVEL" default:"info" validate:"oneof=debug info warn error"`
+
+	// SettingsKey is the Redis hash holding the Runtime settings, unless
+	// SettingsFile names a YAML file to read them from instead. Both are
+	// polled every SettingsPollInterval; the hash is also reloaded on publish
+	// to its channel.
+	SettingsKey          string        `env:"SETTINGS_KEY" default:"matcher:settings" validate:"required"`
+	SettingsFile         string        `env:"SETTINGS_FILE"`
+	SettingsPollInterval time.Duration `env:"SETTINGS_POLL_INTERVAL" default:"10s" validate:"min=1s"`
+
+	// TradingModeKey is the Redis hash of the system-wide trading kill switch
+	// (libs/go/tradingmode), shared by every service and polled every
+	// SettingsPollInterval.
+	TradingModeKey string `env:"TRADING_MODE_KEY" default:"trading:modes" validate:"required"`
+
+	HTTPAddr string `env:"HTTP_ADDR" default:":8081" validate:"required"`
 }
 
-// LoadConfig loads configuration from environment variables.
-// This matches the TECHNICAL_SPECS at a coarse level and can be
-// refined as shared config libraries are introduced.
+// LoadConfig loads configuration from defaults, the YAML file named by
+// -config or CONFIG_FILE, environment variables and command-line flags.
 func LoadConfig() (Config, error) {
-	redisDB, err := envIntOrDefault("REDIS_DB", 0)
-	if err != nil {
+	var cfg Config
+	if err := libconfig.Load(&cfg, libconfig.Options{Args: os.Args[1:]}); err != nil {
 		return Config{}, err
 	}
 
-	cfg := Config{
-		RedisAddr:     envOrDefault("REDIS_ADDR", "localhost:6379"),
-		RedisPassword: os.Getenv("REDIS_PASSWORD"),
-		RedisDB:       redisDB,
-
-		KafkaBrokers:           envCSVOrDefault("KAFKA_BROKERS", "localhost:9092"),
-		KafkaGroupID:           envOrDefault("KAFKA_GROUP_ID_MATCHER", "matcher"),
-		KafkaTopicSignals:      envOrDefault("KAFKA_TOPIC_INFLUENCER_SIGNALS", "influencer_signals"),
-		KafkaTopicExecRequests: envOrDefault("KAFKA_TOPIC_EXECUTION_REQUESTS", "execution_requests"),
-
-		SubscriptionSetKey: envOrDefault("SUBSCRIPTION_SET_KEY", "matcher:subscriptions:primary"),
+	if cfg.InstanceID == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return Config{}, fmt.Errorf("resolve INSTANCE_ID from hostname: %w", err)
+		}
+		cfg.InstanceID = hostname
 	}
 
 	return cfg, nil
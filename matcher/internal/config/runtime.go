@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// Runtime holds the settings that can change without a restart. They are read
+// by key from the SETTINGS_KEY Redis hash (or SETTINGS_FILE) and applied
+// live, e.g. `HSET matcher:settings max_signal_age 30s` followed by
+// `PUBLISH matcher:settings changed`. Unset keys take their defaults.
+type Runtime struct {
+	// MaxSignalAge rejects signals whose exchange timestamp is older than
+	// this when matched. Zero disables the check.
+	MaxSignalAge time.Duration `key:"max_signal_age" default:"0s" validate:"min=0s"`
+	// PublishTimeout bounds each execution request write to Kafka.
+	PublishTimeout time.Duration `key:"publish_timeout" default:"5s" validate:"min=100ms"`
+	// LogLevel overrides LOG_LEVEL while set.
+	LogLevel string `key:"log_level" validate:"oneof=debug info warn error"`
+}
@@ -1,5 +1,38 @@
 package domain
 
+// Subscription statuses.
+const (
+	StatusActive    = "ACTIVE"
+	StatusPaused    = "PAUSED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Sizing modes; SizeValue is interpreted according to the mode.
+const (
+	// SizeModeNotional trades SizeValue in quote currency per signal.
+	SizeModeNotional = "NOTIONAL"
+	// SizeModeSizeFactor trades SizeValue times the influencer's size change.
+	SizeModeSizeFactor = "SIZE_FACTOR"
+	// SizeModeFixedSize trades SizeValue base units per signal.
+	SizeModeFixedSize = "FIXED_SIZE"
+)
+
+// Leverage modes.
+const (
+	// LeverageModeFixed applies Subscription.Leverage as is (0 keeps the
+	// follower's account setting). It is the default.
+	LeverageModeFixed = "FIXED"
+	// LeverageModeMatchInfluencer applies the influencer's leverage and margin
+	// mode from the signal, capped by Subscription.Leverage when set.
+	LeverageModeMatchInfluencer = "MATCH_INFLUENCER"
+)
+
+// Market types accepted in Subscription.AllowedMarketTypes.
+const (
+	MarketTypePerp = "PERP"
+	MarketTypeSpot = "SPOT"
+)
+
 // Subscription represents a follower's configuration to copy an influencer's signals.
 type Subscription struct {
 	ID                   string   `json:"subscription_id"`
@@ -7,9 +40,11 @@ type Subscription struct {
 	SubscriberID         string   `json:"subscriber_id"`
 	Status               string   `json:"status"`
 	AllowedMarkets       []string `json:"allowed_markets,omitempty"`
+	AllowedMarketTypes   []string `json:"allowed_market_types,omitempty"`
 	SizeMode             string   `json:"size_mode"`
 	SizeValue            float64  `json:"size_value"`
 	MaxNotionalPerSignal float64  `json:"max_notional_per_signal,omitempty"`
 	MaxOpenNotional      float64  `json:"max_open_notional,omitempty"`
 	Leverage             float64  `json:"leverage,omitempty"`
+	LeverageMode         string   `json:"leverage_mode,omitempty"`
 }
@@ -2,56 +2,44 @@ package kafka
 
 import (
 	"context"
-	"fmt"
+	"time"
 
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
 )
 
-// ExecutionRequestPublisher publishes ExecutionRequest messages to Kafka.
+// ExecutionRequestPublisher publishes ExecutionRequest messages keyed by
+// subscriber, or by influencer when the subscriber is unset.
 type ExecutionRequestPublisher struct {
-	writer *kafka.Writer
-	Topic  string
+	pub   *messaging.TypedPublisher[*busv1.ExecutionRequest]
+	Topic string
 }
 
-// NewExecutionRequestPublisher creates a new Kafka publisher for execution requests.
-func NewExecutionRequestPublisher(cfg config.Config) *ExecutionRequestPublisher {
-	writer := &kafka.Writer{
-		Addr:                   kafka.TCP(cfg.KafkaBrokers...),
-		Topic:                  cfg.KafkaTopicExecRequests,
-		RequiredAcks:           kafka.RequireAll,
-		Balancer:               &kafka.Hash{},
-		AllowAutoTopicCreation: true,
+// NewExecutionRequestPublisher publishes to pub, which writes to the
+// KAFKA_TOPIC_EXECUTION_REQUESTS topic.
+func NewExecutionRequestPublisher(cfg config.Config, pub messaging.Publisher) *ExecutionRequestPublisher {
+	key := func(r *busv1.ExecutionRequest) string {
+		if r.GetSubscriberId() != "" {
+			return r.GetSubscriberId()
+		}
+		return r.GetInfluencerId()
+	}
+	return &ExecutionRequestPublisher{
+		pub:   messaging.NewTypedPublisher(pub, producerFor(cfg), key),
+		Topic: cfg.KafkaTopicExecRequests,
 	}
-	return &ExecutionRequestPublisher{writer: writer, Topic: cfg.KafkaTopicExecRequests}
 }
 
-// Publish sends an ExecutionRequest to the configured Kafka topic.
+// Publish sends an ExecutionRequest, continuing the trace carried by ctx.
 func (p *ExecutionRequestPublisher) Publish(ctx context.Context, r *busv1.ExecutionRequest) error {
-	value, err := proto.Marshal(r)
-	if err != nil {
-		return fmt.Errorf("marshal execution request proto: %w", err)
-	}
-
-	key := []byte(r.GetSubscriberId())
-	if len(key) == 0 {
-		key = []byte(r.GetInfluencerId())
-	}
-
-	msg := kafka.Message{
-		Key:   key,
-		Value: value,
-	}
-
-	if err := p.writer.WriteMessages(ctx, msg); err != nil {
-		return fmt.Errorf("kafka write: %w", err)
-	}
-	return nil
+	started := time.Now()
+	err := p.pub.Publish(ctx, r)
+	publishDuration.WithLabelValues(p.Topic).Observe(time.Since(started).Seconds())
+	return err
 }
 
-// Close closes the underlying Kafka writer.
+// Close closes the underlying publisher.
 func (p *ExecutionRequestPublisher) Close() error {
-	return p.writer.Close()
+	return p.pub.Close()
 }
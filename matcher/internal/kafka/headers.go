@@ -0,0 +1,11 @@
+package kafka
+
+import (
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+)
+
+// producerFor identifies this matcher instance in message headers.
+func producerFor(cfg config.Config) messaging.Producer {
+	return messaging.Producer{Service: "matcher", Instance: cfg.InstanceID}
+}
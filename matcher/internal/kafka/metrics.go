@@ -0,0 +1,23 @@
+package kafka
+
+import (
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	consumerLag = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "matcher_consumer_lag_messages",
+		Help: "Messages behind the partition high watermark as of the last consumed message, by partition.",
+	}, []string{"partition"})
+	signalsUndecodable = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "matcher_signals_undecodable_total",
+		Help: "Signal messages skipped because their payload does not decode.",
+	})
+	publishDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "matcher_kafka_publish_duration_seconds",
+		Help:    "Duration of Kafka writes, by topic.",
+		Buckets: observability.DurationBuckets,
+	}, []string{"topic"})
+)
@@ -0,0 +1,81 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+// offsetTracker decides which offsets are safe to commit when messages are
+// acknowledged out of order. Committing an offset commits every offset before
+// it on the partition, so a partition is only committed up to just below its
+// oldest fetched message that has not been acknowledged yet.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[partitionKey]*partitionOffsets
+}
+
+type partitionKey struct {
+	topic     string
+	partition int
+}
+
+type partitionOffsets struct {
+	// pending holds the fetched offsets not acknowledged yet.
+	pending map[int64]struct{}
+	// acked is the highest acknowledged offset and committed the highest
+	// committed one.
+	acked, committed int64
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[partitionKey]*partitionOffsets)}
+}
+
+// Fetched records msg as fetched and not yet acknowledged.
+func (t *offsetTracker) Fetched(msg messaging.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+	p, ok := t.partitions[key]
+	if !ok {
+		p = &partitionOffsets{pending: make(map[int64]struct{}), acked: msg.Offset - 1, committed: msg.Offset - 1}
+		t.partitions[key] = p
+	}
+	if msg.Offset > p.committed {
+		p.pending[msg.Offset] = struct{}{}
+	}
+}
+
+// Acked records msgs as acknowledged and returns one message per partition
+// whose offset can now be committed.
+func (t *offsetTracker) Acked(msgs ...messaging.Message) []messaging.Message {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	touched := make(map[partitionKey]struct{})
+	for _, msg := range msgs {
+		key := partitionKey{topic: msg.Topic, partition: msg.Partition}
+		p, ok := t.partitions[key]
+		if !ok {
+			continue
+		}
+		delete(p.pending, msg.Offset)
+		p.acked = max(p.acked, msg.Offset)
+		touched[key] = struct{}{}
+	}
+
+	var out []messaging.Message
+	for key := range touched {
+		p := t.partitions[key]
+		upTo := p.acked
+		for offset := range p.pending {
+			upTo = min(upTo, offset-1)
+		}
+		if upTo <= p.committed {
+			continue
+		}
+		p.committed = upTo
+		out = append(out, messaging.Message{Topic: key.topic, Partition: key.partition, Offset: upTo})
+	}
+	return out
+}
@@ -0,0 +1,90 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/membus"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestOffsetTrackerCommitsBelowUnacknowledged(t *testing.T) {
+	msg := func(partition int, offset int64) messaging.Message {
+		return messaging.Message{Topic: "signals", Partition: partition, Offset: offset}
+	}
+	tracker := newOffsetTracker()
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.Fetched(msg(0, offset))
+	}
+	tracker.Fetched(msg(1, 3))
+
+	steps := []struct {
+		name string
+		ack  []messaging.Message
+		want []messaging.Message
+	}{
+		{name: "acknowledging behind a held message commits nothing", ack: []messaging.Message{msg(0, 11), msg(0, 12)}},
+		{name: "other partitions are committed independently", ack: []messaging.Message{msg(1, 3)}, want: []messaging.Message{msg(1, 3)}},
+		{name: "releasing the held message commits up to the next unacknowledged one", ack: []messaging.Message{msg(0, 10)}, want: []messaging.Message{msg(0, 12)}},
+		{name: "a repeated acknowledgement commits nothing", ack: []messaging.Message{msg(0, 10)}},
+		{name: "the last message commits the partition", ack: []messaging.Message{msg(0, 13)}, want: []messaging.Message{msg(0, 13)}},
+	}
+	for _, step := range steps {
+		got := tracker.Acked(step.ack...)
+		if len(got) != len(step.want) {
+			t.Fatalf("%s: committed %v, want %v", step.name, got, step.want)
+		}
+		for i := range got {
+			if got[i].Topic != step.want[i].Topic || got[i].Partition != step.want[i].Partition || got[i].Offset != step.want[i].Offset {
+				t.Fatalf("%s: committed %v, want %v", step.name, got, step.want)
+			}
+		}
+	}
+}
+
+func TestSignalConsumerRedeliversUnacknowledged(t *testing.T) {
+	bus := membus.New(1)
+	pub := bus.Publisher("signals")
+	for _, id := range []string{"sig-1", "sig-2", "sig-3"} {
+		value, err := proto.Marshal(&busv1.Signal{SignalId: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := pub.Publish(context.Background(), messaging.Message{Value: value}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.Config{KafkaTopicSignals: "signals"}
+	consume := func(ack func(id string) bool) []string {
+		c := NewSignalConsumer(cfg, bus.Consumer("signals", "matcher"), slog.New(slog.DiscardHandler))
+		defer c.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var seen []string
+		_ = c.Consume(ctx, func(ctx context.Context, d messaging.Delivery[*busv1.Signal]) error {
+			seen = append(seen, d.Message.GetSignalId())
+			if ack(d.Message.GetSignalId()) {
+				if err := c.Ack(ctx, d.Raw); err != nil {
+					return err
+				}
+			}
+			if len(seen) == 3 {
+				cancel()
+			}
+			return nil
+		})
+		return seen
+	}
+
+	// sig-1 is held and never acknowledged; the later signals are.
+	consume(func(id string) bool { return id != "sig-1" })
+	seen := consume(func(string) bool { return true })
+	if len(seen) != 3 || seen[0] != "sig-1" {
+		t.Fatalf("redelivered %v, want every signal from sig-1", seen)
+	}
+}
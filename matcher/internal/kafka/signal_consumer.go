@@ -4,51 +4,184 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
 
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
-	"github.com/segmentio/kafka-go"
-	"google.golang.org/protobuf/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
-// SignalConsumer consumes normalized influencer signals from Kafka.
+var tracer = observability.Tracer("github.com/0xRichardL/vibe-copy-trading/matcher/internal/kafka")
+
+// SignalConsumer consumes normalized influencer signals.
 type SignalConsumer struct {
-	reader *kafka.Reader
+	consumer *messaging.TypedConsumer[*busv1.Signal]
+	topic    string
+	offsets  *offsetTracker
+	logger   *slog.Logger
+
+	stallTimeout time.Duration
+
+	// progressMu guards the consumer progress read by CheckProgress.
+	progressMu sync.Mutex
+	lastDone   time.Time
+	backlog    map[int]int64
+	// inFlight is when the handler started on the current signal, zero
+	// between signals.
+	inFlight time.Time
 }
 
-// NewSignalConsumer creates a new Kafka consumer for influencer signals.
-func NewSignalConsumer(cfg config.Config) *SignalConsumer {
-	reader := kafka.NewReader(kafka.ReaderConfig{
-		Brokers: cfg.KafkaBrokers,
-		GroupID: cfg.KafkaGroupID,
-		Topic:   cfg.KafkaTopicSignals,
-	})
-	return &SignalConsumer{reader: reader}
+// NewSignalConsumer consumes from consumer, a member of the
+// KAFKA_GROUP_ID_MATCHER group on the signals topic. On Kafka the member must
+// join with the instance ID as its client ID so CheckGroupMembership can find
+// it.
+func NewSignalConsumer(cfg config.Config, consumer messaging.Consumer, logger *slog.Logger) *SignalConsumer {
+	return &SignalConsumer{
+		consumer:     messaging.NewTypedConsumer[*busv1.Signal](consumer, logger),
+		topic:        cfg.KafkaTopicSignals,
+		offsets:      newOffsetTracker(),
+		logger:       logger,
+		stallTimeout: cfg.ConsumerStallTimeout,
+		lastDone:     time.Now(),
+		backlog:      make(map[int]int64),
+	}
 }
 
-// Consume reads messages from Kafka and passes them to the provided handler.
-func (c *SignalConsumer) Consume(ctx context.Context, handler func(context.Context, *busv1.Signal) error) error {
+// Consume passes each signal to handler with its decoded standard headers,
+// inside a consumer span continuing the producer's trace. Signals are not
+// committed when handler returns: the handler acknowledges them with Ack once
+// it is done with them, which may be later for signals it holds back. A
+// handler error stops consumption; unacknowledged signals are redelivered
+// after a restart. Messages that do not decode as a Signal are logged, counted
+// and acknowledged without reaching handler, so one bad record cannot stop
+// the matcher.
+func (c *SignalConsumer) Consume(ctx context.Context, handler func(context.Context, messaging.Delivery[*busv1.Signal]) error) error {
 	for {
-		msg, err := c.reader.ReadMessage(ctx)
+		d, err := c.consumer.Fetch(ctx)
+		if errors.Is(err, messaging.ErrUndecodable) {
+			if err := c.skip(ctx, d.Raw, err); err != nil {
+				return err
+			}
+			continue
+		}
 		if err != nil {
-			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			if ctx.Err() != nil {
 				return ctx.Err()
 			}
-			return fmt.Errorf("kafka read: %w", err)
+			return err
 		}
+		msg := d.Raw
+		c.offsets.Fetched(msg)
+		consumerLag.WithLabelValues(strconv.Itoa(msg.Partition)).Set(float64(msg.Lag()))
 
-		var sig busv1.Signal
-		if err := proto.Unmarshal(msg.Value, &sig); err != nil {
-			return fmt.Errorf("unmarshal signal proto: %w", err)
+		msgCtx, span := tracer.Start(d.Headers.Context(ctx), c.topic+" receive",
+			trace.WithSpanKind(trace.SpanKindConsumer),
+			trace.WithAttributes(
+				attribute.String("messaging.system", "kafka"),
+				attribute.String("messaging.destination.name", c.topic),
+				attribute.Int("messaging.kafka.partition", msg.Partition),
+				attribute.Int64("messaging.kafka.offset", msg.Offset),
+				attribute.String("signal.id", d.Message.GetSignalId()),
+			))
+		c.startHandling()
+		err = handler(msgCtx, d)
+		observability.EndSpan(span, err)
+		if err != nil {
+			c.recordFailure()
+			return err
 		}
+		c.recordProgress(msg.Partition, msg.Lag())
+	}
+}
 
-		if err := handler(ctx, &sig); err != nil {
-			return err
+// Ack acknowledges handled signal messages. Each partition is committed up to
+// just below its oldest consumed message that is not acknowledged yet, so a
+// signal held back by the handler is redelivered after a restart even when
+// later signals of its partition were acknowledged.
+func (c *SignalConsumer) Ack(ctx context.Context, msgs ...messaging.Message) error {
+	commit := c.offsets.Acked(msgs...)
+	if len(commit) == 0 {
+		return nil
+	}
+	ds := make([]messaging.Delivery[*busv1.Signal], len(commit))
+	for i, msg := range commit {
+		ds[i].Raw = msg
+	}
+	return c.consumer.Commit(ctx, ds...)
+}
+
+// skip acknowledges an undecodable message.
+func (c *SignalConsumer) skip(ctx context.Context, msg messaging.Message, cause error) error {
+	signalsUndecodable.Inc()
+	c.logger.ErrorContext(ctx, "skip undecodable signal", slog.Int("partition", msg.Partition), slog.Int64("offset", msg.Offset), observability.Err(cause))
+	c.offsets.Fetched(msg)
+	if err := c.Ack(ctx, msg); err != nil {
+		return fmt.Errorf("acknowledge undecodable signal: %w", err)
+	}
+	c.recordProgress(msg.Partition, msg.Lag())
+	return nil
+}
+
+func (c *SignalConsumer) startHandling() {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.inFlight = time.Now()
+}
+
+func (c *SignalConsumer) recordFailure() {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.inFlight = time.Time{}
+}
+
+func (c *SignalConsumer) recordProgress(partition int, lag int64) {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	c.lastDone = time.Now()
+	c.inFlight = time.Time{}
+	c.backlog[partition] = lag
+}
+
+// CheckProgress fails when the handler has been working on one signal for the
+// stall timeout, or when a partition had a backlog after the last handled
+// message and nothing has been handled for the stall timeout. An idle,
+// caught-up consumer is considered healthy.
+func (c *SignalConsumer) CheckProgress(context.Context) error {
+	c.progressMu.Lock()
+	defer c.progressMu.Unlock()
+	if c.stallTimeout <= 0 {
+		return nil
+	}
+	if !c.inFlight.IsZero() {
+		if busy := time.Since(c.inFlight); busy >= c.stallTimeout {
+			return fmt.Errorf("signal handler running for %s", busy.Round(time.Second))
 		}
 	}
+	idle := time.Since(c.lastDone)
+	if idle < c.stallTimeout {
+		return nil
+	}
+	for partition, lag := range c.backlog {
+		if lag > 0 {
+			return fmt.Errorf("no signal handled for %s with %d messages pending on partition %d", idle.Round(time.Second), lag, partition)
+		}
+	}
+	return nil
+}
+
+// CheckGroupMembership fails unless the consumer is a member of its group
+// (see messaging.Consumer.CheckMembership).
+func (c *SignalConsumer) CheckGroupMembership(ctx context.Context) error {
+	return c.consumer.CheckMembership(ctx)
 }
 
-// Close closes the underlying Kafka reader.
+// Close leaves the consumer group.
 func (c *SignalConsumer) Close() error {
-	return c.reader.Close()
+	return c.consumer.Close()
 }
@@ -0,0 +1,112 @@
+package kafka
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/membus"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCheckProgressFailsOnHungHandler(t *testing.T) {
+	bus := membus.New(1)
+	value, err := proto.Marshal(&busv1.Signal{SignalId: "sig-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := bus.Publisher("signals").Publish(context.Background(), messaging.Message{Value: value}); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewSignalConsumer(config.Config{KafkaTopicSignals: "signals", ConsumerStallTimeout: 50 * time.Millisecond}, bus.Consumer("signals", "matcher"), slog.New(slog.DiscardHandler))
+	t.Cleanup(func() { _ = c.Close() })
+	ctx, cancel := context.WithCancel(context.Background())
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// The only message has no backlog behind it, so only the in-flight
+		// check can catch the hang.
+		_ = c.Consume(ctx, func(ctx context.Context, _ messaging.Delivery[*busv1.Signal]) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		})
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	<-started
+	if err := c.CheckProgress(context.Background()); err != nil {
+		t.Fatalf("CheckProgress right after the handler started: %v", err)
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		err := c.CheckProgress(context.Background())
+		if err != nil {
+			if !strings.Contains(err.Error(), "handler running") {
+				t.Fatalf("CheckProgress = %v, want a hung handler", err)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("CheckProgress never reported the hung handler")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// A payload that is not a Signal is acknowledged and skipped, so the next
+// signal is handled and a restart does not redeliver the bad one.
+func TestConsumeSkipsUndecodableSignals(t *testing.T) {
+	bus := membus.New(1)
+	pub := bus.Publisher("signals")
+	value, err := proto.Marshal(&busv1.Signal{SignalId: "sig-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range [][]byte{[]byte("\xff\xff\xff not a signal"), value} {
+		if err := pub.Publish(context.Background(), messaging.Message{Value: v}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := config.Config{KafkaTopicSignals: "signals"}
+	c := NewSignalConsumer(cfg, bus.Consumer("signals", "matcher"), slog.New(slog.DiscardHandler))
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	var handled []string
+	err = c.Consume(ctx, func(ctx context.Context, d messaging.Delivery[*busv1.Signal]) error {
+		handled = append(handled, d.Message.GetSignalId())
+		if err := c.Ack(ctx, d.Raw); err != nil {
+			return err
+		}
+		cancel()
+		return nil
+	})
+	if err != nil && ctx.Err() == nil {
+		t.Fatalf("Consume: %v", err)
+	}
+	if len(handled) != 1 || handled[0] != "sig-1" {
+		t.Fatalf("handled %v, want only sig-1", handled)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	again := bus.Consumer("signals", "matcher")
+	defer again.Close()
+	fetchCtx, cancelFetch := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFetch()
+	if msg, err := again.Fetch(fetchCtx); err == nil {
+		t.Fatalf("redelivered offset %d after restart", msg.Offset)
+	}
+}
@@ -0,0 +1,42 @@
+package rest
+
+import (
+	"net/http"
+
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/health"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+)
+
+// NewServer builds the matcher's operational HTTP server, which serves the
+// Prometheus metrics, the liveness and readiness probes and the effective
+// configuration. /health is an alias of /health/live.
+func NewServer(cfg config.Config, ready *health.Checker, settings *libconfig.Reloader[config.Runtime]) (*http.ServeMux, *http.Server, error) {
+	static, err := libconfig.Redacted(&cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /health", health.LiveHandler())
+	mux.Handle("GET /health/live", health.LiveHandler())
+	mux.Handle("GET /health/ready", health.ReadyHandler(ready))
+	mux.Handle("GET /metrics", observability.MetricsHandler())
+	mux.Handle("GET /config", configHandler(static, settings))
+	srv := &http.Server{
+		Addr:    cfg.HTTPAddr,
+		Handler: mux,
+	}
+	return mux, srv, nil
+}
+
+// configHandler reports the static config loaded at startup and the runtime
+// settings currently in effect. Secrets are redacted.
+func configHandler(static map[string]string, settings *libconfig.Reloader[config.Runtime]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]map[string]string{
+			"static":  static,
+			"runtime": settings.Redacted(),
+		})
+	})
+}
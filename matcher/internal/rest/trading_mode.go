@@ -0,0 +1,125 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
+)
+
+// TradingModeController is the admin API of the trading kill switch. Modes
+// are stored in the shared Redis hash, so a change applies to every matcher
+// and ingestion instance.
+type TradingModeController struct {
+	modes *tradingmode.Switch
+}
+
+func NewTradingModeController(modes *tradingmode.Switch) *TradingModeController {
+	return &TradingModeController{modes: modes}
+}
+
+func (c *TradingModeController) RegisterTradingModeRoutes(mux *http.ServeMux) {
+	mux.HandleFunc("GET /trading-modes", c.handleList)
+	mux.HandleFunc("PUT /trading-modes/global", c.handleSetGlobal)
+	mux.HandleFunc("DELETE /trading-modes/global", c.handleClearGlobal)
+	mux.HandleFunc("PUT /trading-modes/{scope}/{id}", c.handleSet)
+	mux.HandleFunc("DELETE /trading-modes/{scope}/{id}", c.handleClear)
+}
+
+type tradingModesResponse struct {
+	Global      tradingmode.Mode            `json:"global"`
+	Influencers map[string]tradingmode.Mode `json:"influencers"`
+	Markets     map[string]tradingmode.Mode `json:"markets"`
+	Subscribers map[string]tradingmode.Mode `json:"subscribers"`
+}
+
+type setTradingModeRequest struct {
+	Mode string `json:"mode"`
+}
+
+func (c *TradingModeController) handleList(w http.ResponseWriter, r *http.Request) {
+	resp := tradingModesResponse{
+		Global:      tradingmode.Normal,
+		Influencers: map[string]tradingmode.Mode{},
+		Markets:     map[string]tradingmode.Mode{},
+		Subscribers: map[string]tradingmode.Mode{},
+	}
+	for key, mode := range c.modes.Modes() {
+		scope, id, _ := strings.Cut(key, ":")
+		switch tradingmode.Scope(scope) {
+		case tradingmode.ScopeGlobal:
+			resp.Global = mode
+		case tradingmode.ScopeInfluencer:
+			resp.Influencers[id] = mode
+		case tradingmode.ScopeMarket:
+			resp.Markets[id] = mode
+		case tradingmode.ScopeSubscriber:
+			resp.Subscribers[id] = mode
+		}
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (c *TradingModeController) handleSetGlobal(w http.ResponseWriter, r *http.Request) {
+	c.set(w, r, tradingmode.ScopeGlobal, "")
+}
+
+func (c *TradingModeController) handleClearGlobal(w http.ResponseWriter, r *http.Request) {
+	c.store(w, r, tradingmode.ScopeGlobal, "", "")
+}
+
+func (c *TradingModeController) handleSet(w http.ResponseWriter, r *http.Request) {
+	scope, ok := c.scope(w, r)
+	if !ok {
+		return
+	}
+	c.set(w, r, scope, r.PathValue("id"))
+}
+
+func (c *TradingModeController) handleClear(w http.ResponseWriter, r *http.Request) {
+	scope, ok := c.scope(w, r)
+	if !ok {
+		return
+	}
+	c.store(w, r, scope, r.PathValue("id"), "")
+}
+
+// scope parses the {scope} path segment; global has its own routes.
+func (c *TradingModeController) scope(w http.ResponseWriter, r *http.Request) (tradingmode.Scope, bool) {
+	scope, err := tradingmode.ParseScope(r.PathValue("scope"))
+	if err != nil || scope == tradingmode.ScopeGlobal {
+		writeJSON(w, http.StatusNotFound, map[string]string{"error": "unknown scope; want influencer, market or subscriber"})
+		return "", false
+	}
+	return scope, true
+}
+
+func (c *TradingModeController) set(w http.ResponseWriter, r *http.Request, scope tradingmode.Scope, id string) {
+	var req setTradingModeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid request body"})
+		return
+	}
+	mode, err := tradingmode.ParseMode(req.Mode)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+	c.store(w, r, scope, id, mode)
+}
+
+// store writes mode (empty clears it) and responds with the updated modes.
+func (c *TradingModeController) store(w http.ResponseWriter, r *http.Request, scope tradingmode.Scope, id string, mode tradingmode.Mode) {
+	if err := c.modes.Set(r.Context(), scope, id, mode); err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+		return
+	}
+	c.handleList(w, r)
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/numbers"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrNotMatched is returned by BuildExecutionRequest when a subscription does
+// not apply to a signal (inactive, market or market type filtered out, or
+// nothing to trade).
+var ErrNotMatched = errors.New("subscription does not match signal")
+
+// Pre-risk rejections returned (wrapped) by BuildExecutionRequest.
+var (
+	ErrInvalidSignal = errors.New("invalid signal")
+	ErrInvalidSizing = errors.New("invalid subscription sizing")
+	ErrBelowLotSize  = errors.New("quantity below lot size")
+	ErrMaxNotional   = errors.New("max notional per signal exceeded")
+)
+
+// BuildExecutionRequest applies sub's filters and sizing to sig. It returns
+// ErrNotMatched for subscriptions that should silently skip the signal and
+// another error when the signal fails a pre-risk check.
+func BuildExecutionRequest(sub domain.Subscription, sig *busv1.Signal, now time.Time) (*busv1.ExecutionRequest, error) {
+	if !strings.EqualFold(sub.Status, domain.StatusActive) {
+		return nil, ErrNotMatched
+	}
+	if len(sub.AllowedMarkets) > 0 && !slices.ContainsFunc(sub.AllowedMarkets, func(m string) bool {
+		return strings.EqualFold(m, sig.GetMarket())
+	}) {
+		return nil, ErrNotMatched
+	}
+	if len(sub.AllowedMarketTypes) > 0 && !slices.ContainsFunc(sub.AllowedMarketTypes, func(t string) bool {
+		return strings.EqualFold(t, marketTypeName(sig.GetMarketType()))
+	}) {
+		// Signals without a market type never match a type filter.
+		return nil, ErrNotMatched
+	}
+
+	req := &busv1.ExecutionRequest{
+		ExecutionRequestId: buildExecutionRequestID(sig.GetSignalId(), sub.ID),
+		SignalId:           sig.GetSignalId(),
+		InfluencerId:       sig.GetInfluencerId(),
+		SubscriberId:       sub.SubscriberID,
+		SubscriptionId:     sub.ID,
+		Market:             sig.GetMarket(),
+		OrderType:          busv1.OrderType_ORDER_TYPE_MARKET,
+		TimeInForce:        busv1.TimeInForce_TIME_IN_FORCE_IOC,
+		RiskChecksPassed:   true,
+		Source:             busv1.ExecutionRequestSource_EXECUTION_REQUEST_SOURCE_MATCHER_V1,
+		CreatedAt:          timestamppb.New(now),
+		CorrelationId:      sig.GetSignalId(),
+	}
+
+	price, err := numbers.DecimalOr(sig.GetPriceDecimal(), sig.GetPrice())
+	if err != nil {
+		return nil, fmt.Errorf("%w: price: %w", ErrInvalidSignal, err)
+	}
+	delta, err := numbers.DecimalOr(sig.GetDeltaSizeDecimal(), sig.GetDeltaSize())
+	if err != nil {
+		return nil, fmt.Errorf("%w: delta size: %w", ErrInvalidSignal, err)
+	}
+	req.Price, req.PriceDecimal = price.Float64(), price.String()
+
+	switch {
+	case sig.GetSide() == busv1.SignalSide_SIGNAL_SIDE_FLAT:
+		// The influencer is flat on the market: close the follower's whole
+		// position regardless of sizing.
+		req.Side = busv1.OrderSide_ORDER_SIDE_CLOSE
+		return req, nil
+	case delta.Sign() > 0:
+		req.Side = busv1.OrderSide_ORDER_SIDE_BUY
+	case delta.Sign() < 0:
+		req.Side = busv1.OrderSide_ORDER_SIDE_SELL
+	default:
+		return nil, ErrNotMatched
+	}
+
+	qty, err := followerQuantity(sub, delta, price)
+	if err != nil {
+		return nil, err
+	}
+	if hasMarketMetadata(sig) {
+		lot := qty.Truncate(sig.GetSizeDecimals())
+		if lot.IsZero() {
+			return nil, fmt.Errorf("%w: quantity %s is below the lot size of %s", ErrBelowLotSize, qty, sig.GetMarket())
+		}
+		qty = lot
+	}
+	notional := qty.Mul(price)
+	req.Quantity, req.QuantityDecimal = qty.Float64(), qty.String()
+	req.Notional, req.NotionalDecimal = notional.Float64(), notional.String()
+	if sub.MaxNotionalPerSignal > 0 && req.Notional > sub.MaxNotionalPerSignal {
+		return nil, fmt.Errorf("%w: notional %s exceeds max_notional_per_signal %.2f", ErrMaxNotional, notional, sub.MaxNotionalPerSignal)
+	}
+	req.Leverage, req.MarginMode = followerLeverage(sub, sig)
+	return req, nil
+}
+
+// quantityPlaces is the precision of notional-sized quantities before lot
+// rounding.
+const quantityPlaces = 12
+
+func followerQuantity(sub domain.Subscription, delta, price numbers.Decimal) (numbers.Decimal, error) {
+	value, err := numbers.DecimalFromFloat(sub.SizeValue)
+	if err != nil || value.Sign() <= 0 {
+		return numbers.Decimal{}, fmt.Errorf("%w: size_value must be positive, got %v", ErrInvalidSizing, sub.SizeValue)
+	}
+	switch strings.ToUpper(sub.SizeMode) {
+	case domain.SizeModeSizeFactor:
+		return delta.Abs().Mul(value), nil
+	case domain.SizeModeNotional:
+		if price.Sign() <= 0 {
+			return numbers.Decimal{}, fmt.Errorf("%w: no price for notional sizing", ErrInvalidSignal)
+		}
+		return value.Quo(price, quantityPlaces)
+	case domain.SizeModeFixedSize:
+		return value, nil
+	default:
+		return numbers.Decimal{}, fmt.Errorf("%w: unknown size_mode %q", ErrInvalidSizing, sub.SizeMode)
+	}
+}
+
+// hasMarketMetadata reports whether sig carries the market's size decimals.
+// Perps always have a max leverage; spot markets only a size precision.
+func hasMarketMetadata(sig *busv1.Signal) bool {
+	return sig.GetMaxLeverage() > 0 || sig.GetSizeDecimals() > 0
+}
+
+func buildExecutionRequestID(signalID, subscriptionID string) string {
+	hash := sha256.Sum256([]byte(signalID + "|" + subscriptionID))
+	return hex.EncodeToString(hash[:])
+}
+
+func marketTypeName(t busv1.MarketType) string {
+	switch t {
+	case busv1.MarketType_MARKET_TYPE_PERP:
+		return domain.MarketTypePerp
+	case busv1.MarketType_MARKET_TYPE_SPOT:
+		return domain.MarketTypeSpot
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
+)
+
+func testSignal(mutate func(*busv1.Signal)) *busv1.Signal {
+	sig := &busv1.Signal{
+		SignalId:         "sig-1",
+		InfluencerId:     "0xinfluencer",
+		Market:           "ETH-PERP",
+		MarketType:       busv1.MarketType_MARKET_TYPE_PERP,
+		Side:             busv1.SignalSide_SIGNAL_SIDE_LONG,
+		Action:           busv1.SignalAction_SIGNAL_ACTION_OPEN,
+		PriceDecimal:     "2000",
+		DeltaSizeDecimal: "1.5",
+		SizeDecimals:     4,
+		MaxLeverage:      50,
+		Leverage:         10,
+		MarginMode:       busv1.MarginMode_MARGIN_MODE_CROSS,
+	}
+	if mutate != nil {
+		mutate(sig)
+	}
+	return sig
+}
+
+func testSubscription(mutate func(*domain.Subscription)) domain.Subscription {
+	sub := domain.Subscription{
+		ID:           "sub-1",
+		InfluencerID: "0xinfluencer",
+		SubscriberID: "follower-1",
+		Status:       domain.StatusActive,
+		SizeMode:     domain.SizeModeSizeFactor,
+		SizeValue:    0.5,
+	}
+	if mutate != nil {
+		mutate(&sub)
+	}
+	return sub
+}
+
+func TestBuildExecutionRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		sub     domain.Subscription
+		sig     *busv1.Signal
+		wantErr error
+
+		wantSide     busv1.OrderSide
+		wantQuantity string
+		wantNotional string
+	}{
+		{
+			name:         "size factor scales the influencer's change",
+			sub:          testSubscription(nil),
+			sig:          testSignal(nil),
+			wantSide:     busv1.OrderSide_ORDER_SIDE_BUY,
+			wantQuantity: "0.75",
+			wantNotional: "1500",
+		},
+		{
+			name:         "sell on a negative change",
+			sub:          testSubscription(nil),
+			sig:          testSignal(func(s *busv1.Signal) { s.DeltaSizeDecimal = "-2" }),
+			wantSide:     busv1.OrderSide_ORDER_SIDE_SELL,
+			wantQuantity: "1",
+			wantNotional: "2000",
+		},
+		{
+			name: "notional sizing divides by price and truncates to the lot size",
+			sub: testSubscription(func(s *domain.Subscription) {
+				s.SizeMode = domain.SizeModeNotional
+				s.SizeValue = 100
+			}),
+			sig:          testSignal(func(s *busv1.Signal) { s.PriceDecimal = "3000" }),
+			wantSide:     busv1.OrderSide_ORDER_SIDE_BUY,
+			wantQuantity: "0.0333",
+			wantNotional: "99.9",
+		},
+		{
+			name: "fixed size ignores the influencer's size",
+			sub: testSubscription(func(s *domain.Subscription) {
+				s.SizeMode = domain.SizeModeFixedSize
+				s.SizeValue = 0.2
+			}),
+			sig:          testSignal(nil),
+			wantSide:     busv1.OrderSide_ORDER_SIDE_BUY,
+			wantQuantity: "0.2",
+			wantNotional: "400",
+		},
+		{
+			name:     "flat influencer closes the follower regardless of sizing",
+			sub:      testSubscription(func(s *domain.Subscription) { s.SizeMode = "BOGUS" }),
+			sig:      testSignal(func(s *busv1.Signal) { s.Side = busv1.SignalSide_SIGNAL_SIDE_FLAT }),
+			wantSide: busv1.OrderSide_ORDER_SIDE_CLOSE,
+		},
+		{
+			name:    "inactive subscription",
+			sub:     testSubscription(func(s *domain.Subscription) { s.Status = domain.StatusPaused }),
+			sig:     testSignal(nil),
+			wantErr: ErrNotMatched,
+		},
+		{
+			name:    "market filtered out",
+			sub:     testSubscription(func(s *domain.Subscription) { s.AllowedMarkets = []string{"BTC-PERP"} }),
+			sig:     testSignal(nil),
+			wantErr: ErrNotMatched,
+		},
+		{
+			name:         "market filter is case-insensitive",
+			sub:          testSubscription(func(s *domain.Subscription) { s.AllowedMarkets = []string{"eth-perp"} }),
+			sig:          testSignal(nil),
+			wantSide:     busv1.OrderSide_ORDER_SIDE_BUY,
+			wantQuantity: "0.75",
+			wantNotional: "1500",
+		},
+		{
+			name:    "market type filtered out",
+			sub:     testSubscription(func(s *domain.Subscription) { s.AllowedMarketTypes = []string{domain.MarketTypeSpot} }),
+			sig:     testSignal(nil),
+			wantErr: ErrNotMatched,
+		},
+		{
+			name:    "no size change",
+			sub:     testSubscription(nil),
+			sig:     testSignal(func(s *busv1.Signal) { s.DeltaSizeDecimal = "0" }),
+			wantErr: ErrNotMatched,
+		},
+		{
+			name:    "unparsable price",
+			sub:     testSubscription(nil),
+			sig:     testSignal(func(s *busv1.Signal) { s.PriceDecimal = "abc" }),
+			wantErr: ErrInvalidSignal,
+		},
+		{
+			name:    "non-positive size value",
+			sub:     testSubscription(func(s *domain.Subscription) { s.SizeValue = 0 }),
+			sig:     testSignal(nil),
+			wantErr: ErrInvalidSizing,
+		},
+		{
+			name:    "unknown size mode",
+			sub:     testSubscription(func(s *domain.Subscription) { s.SizeMode = "PERCENT" }),
+			sig:     testSignal(nil),
+			wantErr: ErrInvalidSizing,
+		},
+		{
+			name:    "quantity below the lot size",
+			sub:     testSubscription(func(s *domain.Subscription) { s.SizeValue = 0.00001 }),
+			sig:     testSignal(nil),
+			wantErr: ErrBelowLotSize,
+		},
+		{
+			name:    "max notional per signal exceeded",
+			sub:     testSubscription(func(s *domain.Subscription) { s.MaxNotionalPerSignal = 1000 }),
+			sig:     testSignal(nil),
+			wantErr: ErrMaxNotional,
+		},
+	}
+
+	now := time.UnixMilli(1_700_000_000_000)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := BuildExecutionRequest(tt.sub, tt.sig, now)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("BuildExecutionRequest: %v", err)
+			}
+			if req.GetSide() != tt.wantSide {
+				t.Errorf("side = %v, want %v", req.GetSide(), tt.wantSide)
+			}
+			if req.GetQuantityDecimal() != tt.wantQuantity || req.GetNotionalDecimal() != tt.wantNotional {
+				t.Errorf("quantity/notional = %q/%q, want %q/%q", req.GetQuantityDecimal(), req.GetNotionalDecimal(), tt.wantQuantity, tt.wantNotional)
+			}
+			if lev, mode := followerLeverage(tt.sub, tt.sig); req.GetLeverage() != lev || req.GetMarginMode() != mode {
+				t.Errorf("leverage/margin = %v/%v, want %v/%v", req.GetLeverage(), req.GetMarginMode(), lev, mode)
+			}
+			if req.GetExecutionRequestId() != buildExecutionRequestID(tt.sig.GetSignalId(), tt.sub.ID) {
+				t.Errorf("execution request id is not derived from the signal and subscription")
+			}
+			if !req.GetRiskChecksPassed() || req.GetSubscriberId() != tt.sub.SubscriberID || req.GetMarket() != tt.sig.GetMarket() {
+				t.Errorf("unexpected request envelope: %v", req)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package services
+
+import (
+	"strings"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
+)
+
+// followerLeverage resolves the leverage and margin mode for the follower's
+// order according to sub.LeverageMode.
+func followerLeverage(sub domain.Subscription, sig *busv1.Signal) (float64, busv1.MarginMode) {
+	if !strings.EqualFold(sub.LeverageMode, domain.LeverageModeMatchInfluencer) {
+		return sub.Leverage, busv1.MarginMode_MARGIN_MODE_UNSPECIFIED
+	}
+	lev := sig.GetLeverage()
+	if lev <= 0 {
+		// Influencer leverage unknown; fall back to the subscription setting.
+		return sub.Leverage, busv1.MarginMode_MARGIN_MODE_UNSPECIFIED
+	}
+	if sub.Leverage > 0 {
+		lev = min(lev, sub.Leverage)
+	}
+	if maxLev := float64(sig.GetMaxLeverage()); maxLev > 0 {
+		lev = min(lev, maxLev)
+	}
+	mode := sig.GetMarginMode()
+	if sig.GetOnlyIsolated() {
+		mode = busv1.MarginMode_MARGIN_MODE_ISOLATED
+	}
+	return lev, mode
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"testing"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
+)
+
+func TestFollowerLeverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		sub      domain.Subscription
+		sig      *busv1.Signal
+		wantLev  float64
+		wantMode busv1.MarginMode
+	}{
+		{
+			name:    "fixed mode applies the subscription leverage",
+			sub:     testSubscription(func(s *domain.Subscription) { s.Leverage = 4 }),
+			sig:     testSignal(nil),
+			wantLev: 4,
+		},
+		{
+			name:     "match influencer copies leverage and margin mode",
+			sub:      testSubscription(func(s *domain.Subscription) { s.LeverageMode = "match_influencer" }),
+			sig:      testSignal(nil),
+			wantLev:  10,
+			wantMode: busv1.MarginMode_MARGIN_MODE_CROSS,
+		},
+		{
+			name: "match influencer capped by the subscription",
+			sub: testSubscription(func(s *domain.Subscription) {
+				s.LeverageMode = domain.LeverageModeMatchInfluencer
+				s.Leverage = 5
+			}),
+			sig:      testSignal(nil),
+			wantLev:  5,
+			wantMode: busv1.MarginMode_MARGIN_MODE_CROSS,
+		},
+		{
+			name: "match influencer falls back to the subscription when leverage is unknown",
+			sub: testSubscription(func(s *domain.Subscription) {
+				s.LeverageMode = domain.LeverageModeMatchInfluencer
+				s.Leverage = 3
+			}),
+			sig:     testSignal(func(s *busv1.Signal) { s.Leverage = 0 }),
+			wantLev: 3,
+		},
+		{
+			name: "isolated-only markets force isolated margin under the max leverage",
+			sub:  testSubscription(func(s *domain.Subscription) { s.LeverageMode = domain.LeverageModeMatchInfluencer }),
+			sig: testSignal(func(s *busv1.Signal) {
+				s.OnlyIsolated = true
+				s.Leverage = 80
+			}),
+			wantLev:  50,
+			wantMode: busv1.MarginMode_MARGIN_MODE_ISOLATED,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lev, mode := followerLeverage(tt.sub, tt.sig)
+			if lev != tt.wantLev || mode != tt.wantMode {
+				t.Fatalf("leverage/margin = %v/%v, want %v/%v", lev, mode, tt.wantLev, tt.wantMode)
+			}
+		})
+	}
+}
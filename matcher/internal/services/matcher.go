@@ -2,49 +2,273 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
+	"time"
 
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
 	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/kafka"
 	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/store"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// Backoff bounds for retrying subscription lookups and publishes.
+const (
+	retryMinBackoff = 100 * time.Millisecond
+	retryMaxBackoff = 10 * time.Second
+)
+
+// tracer creates the matching and fan-out spans.
+var tracer = observability.Tracer("github.com/0xRichardL/vibe-copy-trading/matcher")
+
 // MatcherService owns the background routines that consume influencer signals
-// and (in future iterations) fan them out into execution requests for subscribers.
+// and fan them out into execution requests for subscribers.
 type MatcherService struct {
 	store     *store.SubscriptionStore
 	consumer  *kafka.SignalConsumer
 	publisher *kafka.ExecutionRequestPublisher
-	logger    *log.Logger
+	settings  *libconfig.Reloader[config.Runtime]
+	modes     *tradingmode.Switch
+	logger    *slog.Logger
+
+	// mu serializes sequencing and matching between the consumer and the
+	// reorder flush loop.
+	mu        sync.Mutex
+	sequences *sequenceGuard
 }
 
 // NewMatcherService constructs a MatcherService with its dependencies.
-func NewMatcherService(store *store.SubscriptionStore, consumer *kafka.SignalConsumer, publisher *kafka.ExecutionRequestPublisher, logger *log.Logger) *MatcherService {
+// reorderWindow is how long signals that arrive after a sequence gap are held
+// waiting for the missing ones; zero only logs gaps. settings supplies the
+// signal age limit and publish timeout as they change, and modes the trading
+// mode of each request.
+func NewMatcherService(store *store.SubscriptionStore, consumer *kafka.SignalConsumer, publisher *kafka.ExecutionRequestPublisher, reorderWindow time.Duration, settings *libconfig.Reloader[config.Runtime], modes *tradingmode.Switch, logger *slog.Logger) *MatcherService {
 	return &MatcherService{
 		store:     store,
 		consumer:  consumer,
 		publisher: publisher,
+		settings:  settings,
+		modes:     modes,
 		logger:    logger,
+		sequences: newSequenceGuard(reorderWindow, logger),
 	}
 }
 
-// Start begins consuming influencer signals. Matching and fan-out logic
-// will be added in subsequent revisions to align with the TECHNICAL_SPECS.
+// Start begins consuming influencer signals and matching them against
+// subscriptions until ctx is done.
 func (s *MatcherService) Start(ctx context.Context) error {
-	handler := func(ctx context.Context, sig *busv1.Signal) error {
-		if sig == nil {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	flushErr := make(chan error, 1)
+	if s.sequences.window > 0 {
+		go func() {
+			if err := s.flushHeld(ctx); err != nil {
+				flushErr <- err
+				cancel()
+			}
+		}()
+	}
+
+	if err := s.consumer.Consume(ctx, s.sequenceSignal); err != nil {
+		select {
+		case ferr := <-flushErr:
+			return fmt.Errorf("flush held signals: %w", ferr)
+		default:
+		}
+		return fmt.Errorf("consume signals: %w", err)
+	}
+	return nil
+}
+
+// sequenceSignal passes the signal through the sequence guard, matches every
+// signal it releases and acknowledges them. A held signal is acknowledged only
+// once it is released, so a crash within the reorder window redelivers it.
+func (s *MatcherService) sequenceSignal(ctx context.Context, d messaging.Delivery[*busv1.Signal]) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ready, duplicate := s.sequences.Offer(inboundSignal{sig: d.Message, msg: d.Raw, headers: d.Headers, trace: trace.SpanContextFromContext(ctx)}, time.Now())
+	if duplicate {
+		return s.consumer.Ack(ctx, d.Raw)
+	}
+	return s.handleSignals(ctx, ready)
+}
+
+// flushHeld periodically matches held signals whose reorder window expired.
+func (s *MatcherService) flushHeld(ctx context.Context) error {
+	ticker := time.NewTicker(max(s.sequences.window/4, 10*time.Millisecond))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
 			return nil
+		case now := <-ticker.C:
+			s.mu.Lock()
+			err := s.handleSignals(ctx, s.sequences.Expire(now))
+			s.mu.Unlock()
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *MatcherService) handleSignals(ctx context.Context, ins []inboundSignal) error {
+	for _, in := range ins {
+		sigCtx := ctx
+		if in.trace.IsValid() {
+			sigCtx = trace.ContextWithRemoteSpanContext(ctx, in.trace)
+		}
+		if err := s.handleSignal(sigCtx, in.sig, in.headers); err != nil {
+			return err
 		}
+	}
+	if len(ins) == 0 {
+		return nil
+	}
+	msgs := make([]messaging.Message, len(ins))
+	for i, in := range ins {
+		msgs[i] = in.msg
+	}
+	if err := s.consumer.Ack(ctx, msgs...); err != nil {
+		return fmt.Errorf("acknowledge signals: %w", err)
+	}
+	return nil
+}
 
-		s.logger.Printf("received signal for influencer %s", sig.GetInfluencerId())
-		// TODO: resolve active subscriptions from s.store, apply filters,
-		// build ExecutionRequest messages, and publish via s.publisher.
+// handleSignal publishes one ExecutionRequest per subscription of the signal's
+// influencer that passes filters and pre-risk checks. Requests resolved to the
+// record-only trading mode are published marked as rejected; halted ones are
+// not published. ctx carries the signal's trace, which the requests continue.
+// Failed subscription lookups and publishes are retried until they succeed or
+// ctx is done, so a broker or Redis outage stalls the consumer instead of
+// stopping it; requests already published are not repeated.
+func (s *MatcherService) handleSignal(ctx context.Context, sig *busv1.Signal, headers messaging.Headers) (err error) {
+	if sig == nil {
 		return nil
 	}
+	ctx, span := tracer.Start(ctx, "signal.match", trace.WithAttributes(
+		attribute.String("signal.id", sig.GetSignalId()),
+		attribute.String("influencer.id", sig.GetInfluencerId()),
+		attribute.String("market", sig.GetMarket()),
+		attribute.Int64("signal.sequence", sig.GetSequence()),
+	))
+	defer func() { observability.EndSpan(span, err) }()
+	ctx = observability.WithLogAttrs(ctx,
+		slog.String(observability.LogKeyInfluencerID, sig.GetInfluencerId()),
+		slog.String(observability.LogKeySignalID, sig.GetSignalId()),
+	)
 
-	if err := s.consumer.Consume(ctx, handler); err != nil {
-		return fmt.Errorf("consume signals: %w", err)
+	var subs []domain.Subscription
+	err = s.retry(ctx, retryListSubscriptions, func() error {
+		var err error
+		subs, err = s.store.ListByInfluencer(ctx, sig.GetInfluencerId())
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("list subscriptions for influencer %s: %w", sig.GetInfluencerId(), err)
 	}
+
+	now := time.Now().UTC()
+	signalsConsumed.Inc()
+	observability.ObserveSinceMillis(stageLatency.WithLabelValues(stageConsumed), sig.GetTimestampMs(), now)
+	stale := s.isStale(sig, now)
+	matched := 0
+	for _, sub := range subs {
+		req, err := BuildExecutionRequest(sub, sig, now)
+		if errors.Is(err, ErrNotMatched) {
+			continue
+		}
+		if err != nil {
+			rejections.WithLabelValues(rejectionReason(err)).Inc()
+			s.logger.InfoContext(ctx, "signal rejected", slog.String(observability.LogKeySubscriptionID, sub.ID), slog.String("reason", rejectionReason(err)), observability.Err(err))
+			continue
+		}
+		if stale {
+			rejections.WithLabelValues(rejectStaleSignal).Inc()
+			s.logger.InfoContext(ctx, "signal rejected", slog.String(observability.LogKeySubscriptionID, sub.ID), slog.String("reason", rejectStaleSignal))
+			continue
+		}
+		switch s.modes.Resolve(tradingmode.Target{Influencer: sig.GetInfluencerId(), Market: sig.GetMarket(), Subscriber: sub.SubscriberID}) {
+		case tradingmode.Halted:
+			rejections.WithLabelValues(rejectTradingHalted).Inc()
+			s.logger.InfoContext(ctx, "signal rejected", slog.String(observability.LogKeySubscriptionID, sub.ID), slog.String("reason", rejectTradingHalted), slog.String("trading_mode", string(tradingmode.Halted)))
+			continue
+		case tradingmode.RecordOnly:
+			req.RiskChecksPassed = false
+			req.RejectionReason = tradingmode.RejectionReason
+			rejections.WithLabelValues(rejectTradingHalted).Inc()
+			s.logger.InfoContext(ctx, "signal rejected", slog.String(observability.LogKeySubscriptionID, sub.ID), slog.String("reason", rejectTradingHalted), slog.String("trading_mode", string(tradingmode.RecordOnly)))
+		}
+
+		if err := s.retry(ctx, retryPublish, func() error { return s.fanOut(ctx, sub, req) }); err != nil {
+			return fmt.Errorf("publish execution request for subscription %s: %w", sub.ID, err)
+		}
+		requestsPublished.Inc()
+		observability.ObserveSinceMillis(stageLatency.WithLabelValues(stagePublished), sig.GetTimestampMs(), time.Now())
+		matched++
+	}
+	fanOutSize.Observe(float64(matched))
+
+	span.SetAttributes(attribute.Int("subscriptions.matched", matched), attribute.Int("subscriptions.total", len(subs)))
+	s.logger.InfoContext(ctx, "signal matched", slog.Int("matched", matched), slog.Int("subscriptions", len(subs)), slog.Duration("bus_latency", headers.Latency(now)))
 	return nil
 }
+
+// retry calls op until it succeeds, backing off exponentially between
+// attempts. It only fails once ctx is done.
+func (s *MatcherService) retry(ctx context.Context, operation string, op func() error) error {
+	backoff := retryMinBackoff
+	for {
+		err := op()
+		if err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+		retries.WithLabelValues(operation).Inc()
+		s.logger.ErrorContext(ctx, "matcher operation failed, retrying", slog.String("operation", operation), slog.Duration("retry_in", backoff), observability.Err(err))
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, retryMaxBackoff)
+	}
+}
+
+// isStale reports whether sig is older than the max_signal_age runtime
+// setting.
+func (s *MatcherService) isStale(sig *busv1.Signal, now time.Time) bool {
+	maxAge := s.settings.Current().MaxSignalAge
+	return maxAge > 0 && now.Sub(time.UnixMilli(sig.GetTimestampMs())) > maxAge
+}
+
+// fanOut stamps req with the signal's trace ID and publishes it under a
+// producer span, whose context goes into the message headers.
+func (s *MatcherService) fanOut(ctx context.Context, sub domain.Subscription, req *busv1.ExecutionRequest) (err error) {
+	ctx, span := tracer.Start(ctx, s.publisher.Topic+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "kafka"),
+			attribute.String("messaging.destination.name", s.publisher.Topic),
+			attribute.String("subscription.id", sub.ID),
+			attribute.String("execution_request.id", req.GetExecutionRequestId()),
+		))
+	defer func() { observability.EndSpan(span, err) }()
+
+	req.TraceId = span.SpanContext().TraceID().String()
+	ctxPub, cancel := context.WithTimeout(ctx, s.settings.Current().PublishTimeout)
+	defer cancel()
+	return s.publisher.Publish(ctxPub, req)
+}
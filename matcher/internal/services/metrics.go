@@ -0,0 +1,73 @@
+package services
+
+import (
+	"errors"
+
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+// Matcher metrics, served on GET /metrics.
+var (
+	signalsConsumed = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "matcher_signals_consumed_total",
+		Help: "Signals matched against subscriptions.",
+	})
+	requestsPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "matcher_execution_requests_published_total",
+		Help: "Execution requests published to Kafka.",
+	})
+	rejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "matcher_rejections_total",
+		Help: "Subscriptions that matched a signal but failed a pre-risk check, were blocked by runtime settings or hit the trading kill switch, by reason.",
+	}, []string{"reason"})
+	retries = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "matcher_retries_total",
+		Help: "Failed subscription lookups and execution request publishes that were retried, by operation.",
+	}, []string{"operation"})
+	fanOutSize = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "matcher_fanout_size",
+		Help:    "Execution requests published per signal.",
+		Buckets: []float64{0, 1, 2, 5, 10, 25, 50, 100, 250, 500, 1000},
+	})
+	stageLatency = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "matcher_stage_latency_seconds",
+		Help:    "Time from the exchange event to each matcher stage (consumed, published).",
+		Buckets: observability.LatencyBuckets,
+	}, []string{"stage"})
+)
+
+// Matcher stages measured by stageLatency.
+const (
+	stageConsumed  = "consumed"
+	stagePublished = "published"
+)
+
+// Operations retried by MatcherService.retry, labelling retries.
+const (
+	retryListSubscriptions = "list_subscriptions"
+	retryPublish           = "publish"
+)
+
+// Rejection reasons not derived from BuildExecutionRequest errors.
+const (
+	rejectStaleSignal   = "stale_signal"
+	rejectTradingHalted = "trading_halted"
+)
+
+// rejectionReason maps a BuildExecutionRequest error to a rejections label.
+func rejectionReason(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidSignal):
+		return "invalid_signal"
+	case errors.Is(err, ErrInvalidSizing):
+		return "invalid_sizing"
+	case errors.Is(err, ErrBelowLotSize):
+		return "below_lot_size"
+	case errors.Is(err, ErrMaxNotional):
+		return "max_notional"
+	default:
+		return "other"
+	}
+}
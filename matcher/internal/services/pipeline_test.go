@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	libconfig "github.com/0xRichardL/vibe-copy-trading/libs/go/config"
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging/membus"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/tradingmode"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/config"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/domain"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/kafka"
+	"github.com/0xRichardL/vibe-copy-trading/matcher/internal/store"
+	"github.com/alicebob/miniredis/v2"
+	redis "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TestPipeline runs the ingestion → matcher flow over an in-memory bus:
+// signals are published as ingestion's SignalPublisher does (keyed by
+// influencer, with standard headers) and the matcher turns them into
+// execution requests, continuing each signal's trace, in sequence order.
+func TestPipeline(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	logger := slog.New(slog.DiscardHandler)
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+	subs := store.NewSubscriptionStore(client, "subscriptions")
+	for _, sub := range []domain.Subscription{
+		testSubscription(nil),
+		testSubscription(func(s *domain.Subscription) { s.ID, s.SubscriberID = "sub-2", "follower-2" }),
+	} {
+		if err := subs.Add(ctx, sub); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modes := tradingmode.NewSwitch(client, "trading:modes", time.Minute, logger)
+	if err := modes.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	settings, err := libconfig.NewReloader[config.Runtime](libconfig.NewRedisSource(client, "matcher:settings"), time.Minute, logger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bus := membus.New(3)
+	cfg := config.Config{KafkaTopicSignals: "signals", KafkaTopicExecRequests: "execution-requests", InstanceID: "matcher-1"}
+	signals := messaging.NewTypedPublisher(bus.Publisher(cfg.KafkaTopicSignals), messaging.Producer{Service: "ingestion", Instance: "ingestion-1"}, (*busv1.Signal).GetInfluencerId)
+	consumer := kafka.NewSignalConsumer(cfg, bus.Consumer(cfg.KafkaTopicSignals, "matcher"), logger)
+	publisher := kafka.NewExecutionRequestPublisher(cfg, bus.Publisher(cfg.KafkaTopicExecRequests))
+	matcher := NewMatcherService(subs, consumer, publisher, time.Minute, settings, modes, logger)
+
+	// Sequence 3 overtakes sequence 2 and is held until it arrives.
+	traces := map[string]trace.SpanContext{}
+	for _, seq := range []int64{1, 3, 2} {
+		sig := testSignal(func(s *busv1.Signal) {
+			s.SignalId = fmt.Sprintf("sig-%d", seq)
+			s.Sequence = seq
+			s.TimestampMs = time.Now().UnixMilli()
+		})
+		sc := messaging.NewTrace()
+		traces[sig.GetSignalId()] = sc
+		if err := signals.Publish(trace.ContextWithSpanContext(ctx, sc), sig); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// No subscription follows this influencer.
+	if err := signals.Publish(ctx, testSignal(func(s *busv1.Signal) { s.SignalId, s.InfluencerId = "sig-other", "0xother" })); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- matcher.Start(ctx) }()
+	if err := consumer.CheckGroupMembership(ctx); err != nil {
+		t.Fatalf("matcher is not a group member: %v", err)
+	}
+
+	requests := bus.Consumer(cfg.KafkaTopicExecRequests, "worker")
+	defer requests.Close()
+	typed := messaging.NewTypedConsumer[*busv1.ExecutionRequest](requests, logger)
+	var got []string
+	for len(got) < 6 {
+		fetchCtx, cancelFetch := context.WithTimeout(ctx, 5*time.Second)
+		d, err := typed.Fetch(fetchCtx)
+		cancelFetch()
+		if err != nil {
+			t.Fatalf("fetch execution request %d: %v", len(got)+1, err)
+		}
+		req := d.Message
+		if want := traces[req.GetSignalId()].TraceID(); d.Headers.Trace.TraceID() != want {
+			t.Errorf("request for %s has trace %s, want the signal's %s", req.GetSignalId(), d.Headers.Trace.TraceID(), want)
+		}
+		if d.Headers.Producer.Service != "matcher" || !req.GetRiskChecksPassed() {
+			t.Errorf("request %+v from %+v", req, d.Headers.Producer)
+		}
+		got = append(got, req.GetSignalId()+"/"+req.GetSubscriptionId())
+	}
+	// Requests are keyed by subscriber, so each subscriber sees its own in
+	// signal order.
+	for _, sub := range []string{"sub-1", "sub-2"} {
+		var order []string
+		for _, r := range got {
+			if id, s, _ := strings.Cut(r, "/"); s == sub {
+				order = append(order, id)
+			}
+		}
+		if !slices.Equal(order, []string{"sig-1", "sig-2", "sig-3"}) {
+			t.Fatalf("requests for %s = %v, want them in sequence order", sub, order)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatalf("Start: %v", err)
+	}
+	if err := consumer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Every signal was acknowledged, so the group has nothing to redeliver.
+	again := bus.Consumer(cfg.KafkaTopicSignals, "matcher")
+	defer again.Close()
+	fetchCtx, cancelFetch := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancelFetch()
+	if msg, err := again.Fetch(fetchCtx); err == nil {
+		t.Fatalf("redelivered offset %d of partition %d after every signal was handled", msg.Offset, msg.Partition)
+	}
+}
@@ -0,0 +1,146 @@
+package services
+
+import (
+	"log/slog"
+	"sort"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/observability"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// sequenceGuard tracks the per influencer+market signal sequence numbers
+// assigned by ingestion. Gaps and out-of-order arrivals are logged; with a
+// non-zero window, signals that arrive ahead of a gap are held for up to the
+// window so a late predecessor can be delivered first.
+//
+// sequenceGuard is not safe for concurrent use.
+type sequenceGuard struct {
+	window  time.Duration
+	logger  *slog.Logger
+	streams map[string]*sequenceStream
+}
+
+type sequenceStream struct {
+	last int64
+	held map[int64]heldSignal
+}
+
+// inboundSignal is a consumed signal with its bus message, the message's
+// headers and its consumer span, kept together so held signals continue their
+// own trace and are acknowledged once released.
+type inboundSignal struct {
+	sig     *busv1.Signal
+	msg     messaging.Message
+	headers messaging.Headers
+	trace   trace.SpanContext
+}
+
+type heldSignal struct {
+	in inboundSignal
+	at time.Time
+}
+
+func newSequenceGuard(window time.Duration, logger *slog.Logger) *sequenceGuard {
+	return &sequenceGuard{window: window, logger: logger, streams: make(map[string]*sequenceStream)}
+}
+
+func sequenceKey(sig *busv1.Signal) string {
+	return sig.GetInfluencerId() + "|" + sig.GetMarket()
+}
+
+// Offer records in and returns the signals that are ready to be matched, in
+// sequence order. Unsequenced signals (sequence 0) pass through unchanged.
+// duplicate reports that in repeats a signal already held and was dropped.
+func (g *sequenceGuard) Offer(in inboundSignal, now time.Time) (ready []inboundSignal, duplicate bool) {
+	sig := in.sig
+	seq := sig.GetSequence()
+	if seq <= 0 {
+		return []inboundSignal{in}, false
+	}
+
+	key := sequenceKey(sig)
+	st, ok := g.streams[key]
+	if !ok {
+		// The first signal seen for a stream (e.g. after a restart) is the baseline.
+		g.streams[key] = &sequenceStream{last: seq, held: make(map[int64]heldSignal)}
+		return []inboundSignal{in}, false
+	}
+
+	switch {
+	case seq <= st.last:
+		// Redeliveries are matched again; execution request IDs are derived from
+		// the signal ID so downstream dedupe still applies.
+		g.logger.Warn("signal arrived out of order", slog.String(observability.LogKeySignalID, sig.GetSignalId()), slog.String("stream", key), slog.Int64("sequence", seq), slog.Int64("last", st.last))
+		return []inboundSignal{in}, false
+	case seq == st.last+1:
+		st.last = seq
+		return append([]inboundSignal{in}, st.drain()...), false
+	case g.window <= 0:
+		g.logger.Warn("sequence gap", slog.String("stream", key), slog.Int64("expected", st.last+1), slog.Int64("sequence", seq), slog.Int64("missing", seq-st.last-1))
+		st.last = seq
+		return []inboundSignal{in}, false
+	default:
+		if _, dup := st.held[seq]; dup {
+			g.logger.Warn("signal duplicates held sequence", slog.String(observability.LogKeySignalID, sig.GetSignalId()), slog.String("stream", key), slog.Int64("sequence", seq))
+			return nil, true
+		}
+		st.held[seq] = heldSignal{in: in, at: now}
+		return nil, false
+	}
+}
+
+// Expire releases held signals that have waited longer than the window,
+// skipping over the missing sequence numbers in front of them.
+func (g *sequenceGuard) Expire(now time.Time) []inboundSignal {
+	var out []inboundSignal
+	for key, st := range g.streams {
+		for {
+			next, ok := st.oldestExpired(now, g.window)
+			if !ok {
+				break
+			}
+			g.logger.Warn("sequence gap after reorder window", slog.String("stream", key), slog.Int64("expected", st.last+1), slog.Int64("sequence", next), slog.Int64("missing", next-st.last-1), slog.Duration("window", g.window))
+			st.last = next - 1
+			out = append(out, st.drain()...)
+		}
+	}
+	return out
+}
+
+// drain returns the held signals that directly follow last, advancing last.
+func (st *sequenceStream) drain() []inboundSignal {
+	var out []inboundSignal
+	for {
+		h, ok := st.held[st.last+1]
+		if !ok {
+			return out
+		}
+		delete(st.held, st.last+1)
+		st.last++
+		out = append(out, h.in)
+	}
+}
+
+// oldestExpired returns the lowest held sequence if any held signal has
+// waited longer than window. Releasing from the lowest sequence keeps order.
+func (st *sequenceStream) oldestExpired(now time.Time, window time.Duration) (int64, bool) {
+	if len(st.held) == 0 {
+		return 0, false
+	}
+	seqs := make([]int64, 0, len(st.held))
+	expired := false
+	for seq, h := range st.held {
+		seqs = append(seqs, seq)
+		if now.Sub(h.at) >= window {
+			expired = true
+		}
+	}
+	if !expired {
+		return 0, false
+	}
+	sort.Slice(seqs, func(i, j int) bool { return seqs[i] < seqs[j] })
+	return seqs[0], true
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	busv1 "github.com/0xRichardL/vibe-copy-trading/libs/go/domain/bus/v1"
+	"github.com/0xRichardL/vibe-copy-trading/libs/go/messaging"
+)
+
+func sequenced(market string, seq int64) inboundSignal {
+	return inboundSignal{
+		sig: &busv1.Signal{InfluencerId: "0xinfluencer", Market: market, Sequence: seq},
+		msg: messaging.Message{Offset: seq},
+	}
+}
+
+func sequences(ins []inboundSignal) []int64 {
+	out := make([]int64, len(ins))
+	for i, in := range ins {
+		out[i] = in.sig.GetSequence()
+	}
+	return out
+}
+
+func TestSequenceGuardOffer(t *testing.T) {
+	type offer struct {
+		seq       int64
+		want      []int64
+		duplicate bool
+	}
+	tests := []struct {
+		name   string
+		window time.Duration
+		offers []offer
+	}{
+		{
+			name:   "in order",
+			window: time.Minute,
+			offers: []offer{{seq: 5, want: []int64{5}}, {seq: 6, want: []int64{6}}, {seq: 7, want: []int64{7}}},
+		},
+		{
+			name:   "unsequenced signals pass through",
+			window: time.Minute,
+			offers: []offer{{seq: 0, want: []int64{0}}, {seq: 0, want: []int64{0}}},
+		},
+		{
+			name:   "gap without a window is logged and passed through",
+			offers: []offer{{seq: 1, want: []int64{1}}, {seq: 4, want: []int64{4}}, {seq: 5, want: []int64{5}}},
+		},
+		{
+			name:   "gap holds until the missing signal arrives",
+			window: time.Minute,
+			offers: []offer{
+				{seq: 1, want: []int64{1}},
+				{seq: 3},
+				{seq: 4},
+				{seq: 2, want: []int64{2, 3, 4}},
+				{seq: 5, want: []int64{5}},
+			},
+		},
+		{
+			name:   "late redelivery is matched again",
+			window: time.Minute,
+			offers: []offer{{seq: 3, want: []int64{3}}, {seq: 4, want: []int64{4}}, {seq: 2, want: []int64{2}}, {seq: 4, want: []int64{4}}},
+		},
+		{
+			name:   "duplicate of a held signal is dropped",
+			window: time.Minute,
+			offers: []offer{{seq: 1, want: []int64{1}}, {seq: 3}, {seq: 3, duplicate: true}, {seq: 2, want: []int64{2, 3}}},
+		},
+	}
+	now := time.UnixMilli(1_700_000_000_000)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newSequenceGuard(tt.window, slog.New(slog.DiscardHandler))
+			for i, o := range tt.offers {
+				ready, duplicate := g.Offer(sequenced("ETH-PERP", o.seq), now)
+				if got := sequences(ready); !slices.Equal(got, o.want) || duplicate != o.duplicate {
+					t.Fatalf("offer %d (sequence %d) = %v, duplicate %v; want %v, duplicate %v", i, o.seq, got, duplicate, o.want, o.duplicate)
+				}
+			}
+		})
+	}
+}
+
+func TestSequenceGuardExpire(t *testing.T) {
+	g := newSequenceGuard(time.Second, slog.New(slog.DiscardHandler))
+	start := time.UnixMilli(1_700_000_000_000)
+
+	g.Offer(sequenced("ETH-PERP", 1), start)
+	g.Offer(sequenced("ETH-PERP", 4), start)
+	g.Offer(sequenced("ETH-PERP", 3), start.Add(500*time.Millisecond))
+	g.Offer(sequenced("BTC-PERP", 10), start)
+	g.Offer(sequenced("BTC-PERP", 12), start.Add(900*time.Millisecond))
+
+	if got := g.Expire(start.Add(999 * time.Millisecond)); len(got) != 0 {
+		t.Fatalf("released %v before the window expired", sequences(got))
+	}
+
+	// Expiry skips the missing sequence and releases everything held behind
+	// it in order, even signals that have not waited the whole window.
+	got := g.Expire(start.Add(time.Second))
+	if want := []int64{3, 4}; !slices.Equal(sequences(got), want) {
+		t.Fatalf("released %v, want %v", sequences(got), want)
+	}
+	if ready, _ := g.Offer(sequenced("ETH-PERP", 5), start.Add(time.Second)); !slices.Equal(sequences(ready), []int64{5}) {
+		t.Fatalf("signal after expiry = %v, want it released immediately", sequences(ready))
+	}
+
+	if got := g.Expire(start.Add(1900 * time.Millisecond)); !slices.Equal(sequences(got), []int64{12}) {
+		t.Fatalf("released %v, want [12]", sequences(got))
+	}
+	if got := g.Expire(start.Add(time.Hour)); len(got) != 0 {
+		t.Fatalf("released %v after every stream drained", sequences(got))
+	}
+}